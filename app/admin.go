@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"strconv"
+	"tax-aggregator-service-demo/audit"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/tax/domain"
+
+	auditDomain "tax-aggregator-service-demo/audit/domain"
+	auditEntity "tax-aggregator-service-demo/audit/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// adminHandler serve the admin listener, requests are not authenticated so the listener must not be reachable publicly.
+type adminHandler struct {
+	configHandler  *configHandler
+	sourceDBRouter *dbconn.Router
+	serviceDBConn  *sql.DB
+	taxUsecase     domain.TaxUsecase
+	auditUsecase   auditDomain.AuditUsecase
+}
+
+// build information of the running binary
+type buildInfo struct {
+	Version      string `json:"version"`
+	GoVersion    string `json:"go_version"`
+	Revision     string `json:"revision,omitempty"`
+	RevisionTime string `json:"revision_time,omitempty"`
+	Modified     bool   `json:"modified"`
+}
+
+// AdminRegistry return the admin server to be started on admin address, nil when the admin listener is not enabled.
+// the admin address must not share the public port, so none of the admin routes is reachable with /tax.
+func AdminRegistry(config *config.Config, port int, configHandler *configHandler, sourceDBRouter *dbconn.Router, serviceDBConn *sql.DB, usecase domain.TaxUsecase, auditUsecase auditDomain.AuditUsecase) (*echo.Echo, error) {
+	if !config.Admin.Enabled {
+		return nil, nil
+	}
+	_, adminPort, err := net.SplitHostPort(config.Admin.Address)
+	if err != nil {
+		return nil, err
+	}
+	if adminPort == strconv.Itoa(port) {
+		return nil, fmt.Errorf("admin.address %s must not use the public port %d", config.Admin.Address, port)
+	}
+	e := echo.New()
+	e.HideBanner = true
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(middleware.Recover())
+	e.Use(logger.Middleware())
+	adminHandler := &adminHandler{
+		configHandler:  configHandler,
+		sourceDBRouter: sourceDBRouter,
+		serviceDBConn:  serviceDBConn,
+		taxUsecase:     usecase,
+		auditUsecase:   auditUsecase,
+	}
+	adminHandler.Routes(e)
+	return e, nil
+}
+
+func (ah *adminHandler) Routes(echo *echo.Echo) {
+	echo.GET("/debug/pprof/*", wrapHandlerFunc(pprof.Index))
+	echo.GET("/debug/pprof/cmdline", wrapHandlerFunc(pprof.Cmdline))
+	echo.GET("/debug/pprof/profile", wrapHandlerFunc(pprof.Profile))
+	echo.Any("/debug/pprof/symbol", wrapHandlerFunc(pprof.Symbol))
+	echo.GET("/debug/pprof/trace", wrapHandlerFunc(pprof.Trace))
+	echo.GET("/config", ah.GetConfig)
+	echo.POST("/config/reload", ah.configHandler.ReloadConfig)
+	echo.GET("/db/stats", ah.GetDBStats)
+	echo.GET("/version", ah.GetVersion)
+	echo.DELETE("/tax/cache", ah.FlushTaxCache)
+	echo.GET("/log/level", ah.GetLogLevel)
+	echo.PUT("/log/level", ah.SetLogLevel)
+}
+
+func wrapHandlerFunc(handlerFunc http.HandlerFunc) echo.HandlerFunc {
+	return echo.WrapHandler(handlerFunc)
+}
+
+// GetConfig return the effective config with secrets redacted.
+func (ah *adminHandler) GetConfig(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success get config",
+		Data: map[string]any{
+			"config_hash": ah.configHandler.reloader.Current().Hash(),
+			"config":      ah.configHandler.reloader.Current().Redacted(),
+		},
+	})
+}
+
+// GetDBStats return the pool stats of the source primary, every source replica and the service database.
+func (ah *adminHandler) GetDBStats(ctx echo.Context) error {
+	stats := map[string]sql.DBStats{
+		"source":  ah.sourceDBRouter.Primary().Stats(),
+		"service": ah.serviceDBConn.Stats(),
+	}
+	for _, replica := range ah.sourceDBRouter.Replicas() {
+		stats["source_replica_"+replica.Name] = replica.DB.Stats()
+	}
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success get database stats",
+		Data:    stats,
+	})
+}
+
+// GetVersion return the cli version with the go version and vcs revision the binary was built from.
+func (ah *adminHandler) GetVersion(ctx echo.Context) error {
+	info := &buildInfo{Version: Version}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = build.GoVersion
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.time":
+				info.RevisionTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success get version",
+		Data:    info,
+	})
+}
+
+// FlushTaxCache remove every cached tax response of this replica, or only the ones overlapping [start_date, end_date) when given,
+// eg: after tax_transaction was adjusted manually.
+func (ah *adminHandler) FlushTaxCache(ctx echo.Context) error {
+	if !ctx.QueryParams().Has("start_date") && !ctx.QueryParams().Has("end_date") {
+		removed := ah.taxUsecase.InvalidateTax(ctx.Request().Context(), math.MinInt64, math.MaxInt64)
+		ah.record(ctx, &auditEntity.AuditLog{Action: auditDomain.AuditActionFlushTaxCache})
+		return ctx.JSON(http.StatusOK, &domain.Response{
+			Code:    http.StatusOK,
+			Message: "success flush tax cache",
+			Data:    map[string]int{"removed": removed},
+		})
+	}
+	var startDate, endDate int64
+	err := echo.QueryParamsBinder(ctx).
+		MustInt64("start_date", &startDate).
+		MustInt64("end_date", &endDate).
+		BindError()
+	if err != nil {
+		slog.WarnContext(ctx.Request().Context(), "[main.adminHandler.FlushTaxCache]:: error bind query params", logger.Err(err))
+		return ctx.JSON(http.StatusBadRequest, &domain.Response{
+			Code:    http.StatusBadRequest,
+			Message: "bad request",
+		})
+	}
+	removed := ah.taxUsecase.InvalidateTax(ctx.Request().Context(), startDate, endDate)
+	ah.record(ctx, &auditEntity.AuditLog{Action: auditDomain.AuditActionInvalidateTaxCache})
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success invalidate tax cache",
+		Data:    map[string]int{"removed": removed},
+	})
+}
+
+func (ah *adminHandler) GetLogLevel(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success get log level",
+		Data:    map[string]string{"level": logger.Level()},
+	})
+}
+
+// SetLogLevel change the log level until the next restart or config reload, eg: to debug a running replica.
+func (ah *adminHandler) SetLogLevel(ctx echo.Context) error {
+	auditLog := &auditEntity.AuditLog{Action: auditDomain.AuditActionSetLogLevel}
+	err := logger.SetLevel(ctx.QueryParam("level"))
+	if err != nil {
+		auditLog.Status = auditDomain.AuditStatusFailed
+		auditLog.Error = err.Error()
+	}
+	ah.record(ctx, auditLog)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, &domain.Response{
+			Code:    http.StatusBadRequest,
+			Message: "level must be one of debug, info, warn or error",
+		})
+	}
+	slog.InfoContext(ctx.Request().Context(), "[main.adminHandler.SetLogLevel]:: log level changed.", slog.String("level", logger.Level()))
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success set log level",
+		Data:    map[string]string{"level": logger.Level()},
+	})
+}
+
+// record the admin action on audit trail, even when the client went away.
+func (ah *adminHandler) record(ctx echo.Context, auditLog *auditEntity.AuditLog) {
+	auditLog.Actor = audit.Actor(ctx)
+	auditLog.ClaimedActor = audit.ClaimedActor(ctx)
+	auditLog.Params = audit.Params(ctx.QueryParams())
+	if err := ah.auditUsecase.Record(context.WithoutCancel(ctx.Request().Context()), auditLog); err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "[main.adminHandler.record]:: error recording audit log.", logger.Err(err))
+	}
+}
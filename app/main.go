@@ -3,14 +3,40 @@ package main
 import (
 	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"os/user"
 	"strconv"
+	"tax-aggregator-service-demo/pkg/auth"
 	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/lifecycle"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+	"tax-aggregator-service-demo/pkg/scheduler"
+	"tax-aggregator-service-demo/pkg/secret"
+	"tax-aggregator-service-demo/pkg/tracing"
 	"tax-aggregator-service-demo/tax/domain"
 	"time"
 
+	auditDomain "tax-aggregator-service-demo/audit/domain"
+	auditEntity "tax-aggregator-service-demo/audit/entity"
+	auditHandler "tax-aggregator-service-demo/audit/handler"
+	auditRepository "tax-aggregator-service-demo/audit/repository"
+	auditUsecase "tax-aggregator-service-demo/audit/usecase"
+	healthDomain "tax-aggregator-service-demo/health/domain"
+	healthHandler "tax-aggregator-service-demo/health/handler"
+	healthRepository "tax-aggregator-service-demo/health/repository"
+	healthUsecase "tax-aggregator-service-demo/health/usecase"
+	jobDomain "tax-aggregator-service-demo/job/domain"
+	jobHandler "tax-aggregator-service-demo/job/handler"
+	jobRepository "tax-aggregator-service-demo/job/repository"
+	jobUsecase "tax-aggregator-service-demo/job/usecase"
 	taxHandler "tax-aggregator-service-demo/tax/handler"
 	taxRepository "tax-aggregator-service-demo/tax/repository"
 	taxUsecase "tax-aggregator-service-demo/tax/usecase"
@@ -24,6 +50,9 @@ import (
 
 type Server *echo.Echo
 
+// Version of the service, reported by --version and the admin listener
+const Version = "1.0.1"
+
 var commands = []*cli.Command{
 	{
 		Name: "start",
@@ -46,17 +75,46 @@ var commands = []*cli.Command{
 			return App(config, port)
 		},
 	},
+	{
+		Name:  "backfill",
+		Usage: "backfill tax_transaction from source database, re-run the same range to resume",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "--from date (inclusive) will be used as backfill start eg: --from 2022-01-01",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "to",
+				Usage:    "--to date (exclusive) will be used as backfill end eg: --to 2023-01-01",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Value: 2,
+				Usage: "--concurrency number of month chunks processed at the same time eg: --concurrency 2",
+			},
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "-c path will be used for config eg: -c ./config/config.json",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			return Backfill(ctx.String("config"), ctx.String("from"), ctx.String("to"), ctx.Int("concurrency"))
+		},
+	},
 }
 
 func main() {
 	app := &cli.App{
 		Name:     "tax-aggregator-service",
-		Version:  "1.0.1",
+		Version:  Version,
 		Commands: commands,
 	}
 	if err := app.Run(os.Args); err != nil {
-		log.Println("[app.main]:: error running application.")
-		os.Exit(1)
+		slog.Error("[app.main]:: error running application.", logger.Err(err))
+		os.Exit(lifecycle.ExitCode(err))
 	}
 }
 
@@ -68,59 +126,327 @@ func App(cfg string, port int) error {
 	if err != nil {
 		return err
 	}
-	sourceDBConn, err := dbconn.NewMySQLDBConn(&config.SourceDatabase)
+	if err := logger.Setup(config.Logging.Format, config.Logging.Level); err != nil {
+		return err
+	}
+	manager := lifecycle.NewManager(lifecycle.WithShutdownTimeout(time.Duration(config.Timeout.ShutdownMs) * time.Millisecond))
+	shutdownTracing, err := tracing.Setup(context.Background(), &config.Tracing)
 	if err != nil {
 		return err
 	}
+	manager.Add(lifecycle.Component{Name: "tracing", Stop: shutdownTracing})
+	e.Use(tracing.Middleware())
+	e.Use(logger.Middleware())
+	if config.Timeout.RequestMs > 0 {
+		e.Use(middleware.ContextTimeout(time.Duration(config.Timeout.RequestMs) * time.Millisecond))
+	}
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	e.Server.BaseContext = func(net.Listener) context.Context {
+		return requestCtx
+	}
+	secrets, err := secret.NewStoreFromConfig(&config.SecretManager)
+	if err != nil {
+		return err
+	}
+	manager.Add(lifecycle.Component{
+		Name: "secrets",
+		Start: func(ctx context.Context) error {
+			secrets.Start()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			secrets.Close()
+			return nil
+		},
+	})
+	sourceDBRouter, err := dbconn.NewSourceRouter(&config.SourceDatabase, dbconn.WithSecrets(secrets))
+	if err != nil {
+		return err
+	}
+	manager.Add(lifecycle.Component{
+		Name: "source_database",
+		Start: func(ctx context.Context) error {
+			sourceDBRouter.Start()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return sourceDBRouter.Close()
+		},
+	})
 
-	serviceDBConn, err := dbconn.NewPostgreSQLDBConn(&config.ServiceDatabase)
+	serviceDBConn, err := dbconn.NewServiceDBConn(&config.ServiceDatabase, dbconn.WithSecrets(secrets))
 	if err != nil {
 		return err
 	}
+	manager.Add(lifecycle.Component{
+		Name: "service_database",
+		Stop: func(ctx context.Context) error {
+			return serviceDBConn.Close()
+		},
+	})
 
-	TaxRegistry(e, sourceDBConn, serviceDBConn, &config.PpnConfig)
+	e.Use(metrics.Middleware())
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	if err := registerDBMetrics(sourceDBRouter, serviceDBConn); err != nil {
+		return err
+	}
+	authenticator, err := auth.NewAuthenticator(context.Background(), &config.Auth, secrets)
+	if err != nil {
+		return err
+	}
+	e.IPExtractor, err = ratelimit.IPExtractor(config.RateLimit.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	limiter := ratelimit.NewLimiter(config.RateLimit.RequestsPerSecond, config.RateLimit.Burst)
+	auditUsecase := AuditRegistry(e, serviceDBConn, config, authenticator, limiter)
+	usecase := TaxRegistry(e, sourceDBRouter, serviceDBConn, config, auditUsecase, authenticator, limiter)
+	HealthRegistry(e, sourceDBRouter, serviceDBConn, config)
+	jobScheduler, err := JobRegistry(e, serviceDBConn, config, usecase, auditUsecase, authenticator, limiter)
+	if err != nil {
+		return err
+	}
+	configHandler, stopReload := ConfigRegistry(e, cfg, config, usecase, auditUsecase, authenticator, limiter)
+	manager.Add(lifecycle.Component{
+		Name: "config_reload",
+		Stop: func(ctx context.Context) error {
+			stopReload()
+			return nil
+		},
+	})
+	manager.Add(lifecycle.Component{
+		Name: "scheduler",
+		Start: func(ctx context.Context) error {
+			jobScheduler.Start()
+			return nil
+		},
+		Stop: jobScheduler.Stop,
+	})
+	adminServer, err := AdminRegistry(config, port, configHandler, sourceDBRouter, serviceDBConn, usecase, auditUsecase)
+	if err != nil {
+		return err
+	}
+
+	// running requests are cancelled when they are not drained within the shutdown timeout
+	manager.Add(serverComponent(manager, "http_server", e, ":" + strconv.Itoa(port), cancelRequests))
+	if adminServer != nil {
+		manager.Add(serverComponent(manager, "admin_server", adminServer, config.Admin.Address, func() {}))
+	}
 
-	serverPort := ":" + strconv.Itoa(port)
-	go func(){
-		log.Println("starting tax-aggregator-service")
-		if err := e.Start(serverPort); err != nil {
-			log.Printf("[main.App]:: error starting server on port %s.\n", serverPort)
-			e.Logger.Fatal("[main.App]:: shutting down application.")
+	ctx, stop := signal.NotifyContext(context.Background(), lifecycle.Signals...)
+	defer stop()
+	return manager.Run(ctx)
+}
+
+func Backfill(cfg, from, to string, concurrency int) error {
+	fromDate, err := time.Parse(time.DateOnly, from)
+	if err != nil {
+		return err
+	}
+	toDate, err := time.Parse(time.DateOnly, to)
+	if err != nil {
+		return err
+	}
+	config, err := config.LoadConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if err := logger.Setup(config.Logging.Format, config.Logging.Level); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), lifecycle.Signals...)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, &config.Tracing)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("[main.Backfill]:: error flushing pending spans.", logger.Err(err))
 		}
 	}()
+	secrets, err := secret.NewStoreFromConfig(&config.SecretManager)
+	if err != nil {
+		return err
+	}
+	secrets.Start()
+	defer secrets.Close()
+	sourceDBRouter, err := dbconn.NewSourceRouter(&config.SourceDatabase, dbconn.WithSecrets(secrets))
+	if err != nil {
+		return err
+	}
+	sourceDBRouter.Start()
+	defer sourceDBRouter.Close()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
-	ctx, cancel := context.WithTimeout(context.Background(), 120 * time.Second)
-	defer cancel()
+	serviceDBConn, err := dbconn.NewServiceDBConn(&config.ServiceDatabase, dbconn.WithSecrets(secrets))
+	if err != nil {
+		return err
+	}
+	defer serviceDBConn.Close()
+
+	backfillRepository := taxRepository.NewBackfillRepository(serviceDBConn)
+	taxRepository := newTaxRepository(sourceDBRouter, serviceDBConn, config)
+	backfillUsecase := taxUsecase.NewBackfillUsecase(
+		newTaxUsecase(taxRepository, &config.PpnConfig, taxUsecase.WithSourceConcurrency(config.SourceQuery.Concurrency)),
+		taxRepository,
+		backfillRepository,
+		&domain.BackfillConfig{Concurrency: concurrency},
+	)
+	err = backfillUsecase.Backfill(ctx, fromDate.Unix(), toDate.Unix())
 
-	if err := e.Shutdown(ctx); err != nil {
-		e.Logger.Fatal(err)
+	auditLog := &auditEntity.AuditLog{
+		Action: auditDomain.AuditActionBackfill,
+		Actor:  cliActor(),
+		Params: fmt.Sprintf(`{"from":%q,"to":%q,"concurrency":%d}`, from, to, concurrency),
+	}
+	if err != nil {
+		auditLog.Status = auditDomain.AuditStatusFailed
+		auditLog.Error = err.Error()
 	}
+	auditUsecase := auditUsecase.NewAuditUsecase(auditRepository.NewAuditRepository(serviceDBConn), config.Hash())
+	if err := auditUsecase.Record(context.WithoutCancel(ctx), auditLog); err != nil {
+		slog.ErrorContext(ctx, "[main.Backfill]:: error recording audit log.", logger.Err(err))
+	}
+	return err
+}
 
-	defer func() {
-		log.Println("[main.App]:: closing source database connection...")
-		if err := sourceDBConn.Close(); err != nil {
-			log.Fatal("[main.App]:: error closing source database connection.")
-		}
+// cliActor return the operating system user running the command as audit log actor.
+func cliActor() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return "unknown"
+}
+
+// serverComponent listen on address when started, so a port already in use fail the startup,
+// and drain in-flight requests when stopped. onShutdownError is called when requests are not drained in time.
+func serverComponent(manager *lifecycle.Manager, name string, server *echo.Echo, address string, onShutdownError func()) lifecycle.Component {
+	return lifecycle.Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return err
+			}
+			server.Listener = listener
+			slog.InfoContext(ctx, "[main.App]:: starting server.", slog.String("server", name), slog.String("address", address))
+			go func(){
+				if err := server.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					manager.Fail(name, err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			if err := server.Shutdown(ctx); err != nil {
+				onShutdownError()
+				return err
+			}
+			return nil
+		},
+	}
+}
 
-		log.Println("[main.App]:: closing service database conection...")
-		if err := serviceDBConn.Close(); err != nil {
-			log.Fatal("[main.App]:: error closing service database connection.")
+// registerDBMetrics expose the pool stats of source primary, source replicas and service database.
+func registerDBMetrics(sourceDBRouter *dbconn.Router, serviceDBConn *sql.DB) error {
+	if err := metrics.RegisterDB("source", sourceDBRouter.Primary()); err != nil {
+		return err
+	}
+	for _, replica := range sourceDBRouter.Replicas() {
+		if err := metrics.RegisterDB("source_replica_" + replica.Name, replica.DB); err != nil {
+			return err
 		}
-	}()
-	return nil
+	}
+	return metrics.RegisterDB("service", serviceDBConn)
 }
 
-func TaxRegistry(e Server, sourceDBConn, serviceDBConn *sql.DB, ppnConfig *config.PpnConfig) {
-	taxRepository := taxRepository.NewTaxRepository(sourceDBConn, serviceDBConn)
-	taxUsecase := taxUsecase.NewTaxUsecase(taxRepository, &domain.TaxConfig{
+func AuditRegistry(e Server, serviceDBConn *sql.DB, config *config.Config, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) auditDomain.AuditUsecase {
+	auditRepository := auditRepository.NewAuditRepository(serviceDBConn)
+	auditUsecase := auditUsecase.NewAuditUsecase(auditRepository, config.Hash())
+	auditHandler := auditHandler.NewAuditHandler(auditUsecase, authenticator, limiter)
+	auditHandler.Routes(e)
+	return auditUsecase
+}
+
+func TaxRegistry(e Server, sourceDBRouter *dbconn.Router, serviceDBConn *sql.DB, config *config.Config, auditUsecase auditDomain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) domain.TaxUsecase {
+	taxRepository := newTaxRepository(sourceDBRouter, serviceDBConn, config)
+	taxUsecase := newTaxUsecase(taxRepository, &config.PpnConfig,
+		taxUsecase.WithSourceFallback(!config.Aggregator.Enabled),
+		taxUsecase.WithSourceConcurrency(config.SourceQuery.Concurrency),
+		taxUsecase.WithResultCache(config.ResultCache.Size, time.Duration(config.ResultCache.TTLSeconds) * time.Second),
+		taxUsecase.WithSourceFetchLimit(config.RateLimit.SourceFetchConcurrency, config.RateLimit.SourceFetchQueueSize, time.Duration(config.RateLimit.SourceFetchQueueTimeoutMs) * time.Millisecond),
+	)
+	taxHandler := taxHandler.NewTaxHandler(taxUsecase, auditUsecase, authenticator, limiter)
+	taxHandler.Routes(e)
+	return taxUsecase
+}
+
+func newTaxUsecase(taxRepository domain.TaxRepository, ppnConfig *config.PpnConfig, opts ...taxUsecase.TaxUsecaseOption) domain.TaxUsecase {
+	return taxUsecase.NewTaxUsecase(taxRepository, &domain.TaxConfig{
 		TimeStartPpn: ppnConfig.TimeStartPpn,
 		TimeStartPpnNew: ppnConfig.TimeStartPpnNew,
 		TarifPpn: ppnConfig.TarifPpn,
 		TarifPpnNew: ppnConfig.TarifPpnNew,
+	}, opts...)
+}
+
+func newTaxRepository(sourceDBRouter *dbconn.Router, serviceDBConn *sql.DB, config *config.Config) domain.TaxRepository {
+	return taxRepository.NewInstrumentedTaxRepository(taxRepository.NewResilientTaxRepository(taxRepository.NewTaxRepository(sourceDBRouter.Primary(), serviceDBConn,
+		taxRepository.WithSourceRouter(sourceDBRouter),
+		taxRepository.WithQueryTimeout(
+			time.Duration(config.Timeout.SourceQueryMs) * time.Millisecond,
+			time.Duration(config.Timeout.ServiceQueryMs) * time.Millisecond,
+		),
+		taxRepository.WithChunking(config.SourceQuery.ChunkDays, config.SourceQuery.ChunkConcurrency),
+	), &domain.ResilienceConfig{
+		RetryAttempts: config.Resilience.RetryAttempts,
+		RetryBaseDelay: time.Duration(config.Resilience.RetryBaseDelayMs) * time.Millisecond,
+		RetryMaxDelay: time.Duration(config.Resilience.RetryMaxDelayMs) * time.Millisecond,
+		BreakerFailureThreshold: config.Resilience.BreakerFailureThreshold,
+		BreakerOpenTimeout: time.Duration(config.Resilience.BreakerOpenTimeoutMs) * time.Millisecond,
+	}))
+}
+
+// HealthRegistry serve /healthz for liveness and /readyz for readiness of the source and service databases.
+func HealthRegistry(e Server, sourceDBRouter *dbconn.Router, serviceDBConn *sql.DB, config *config.Config) {
+	healthUsecase := healthUsecase.NewHealthUsecase(healthRepository.NewHealthRepository(sourceDBRouter, serviceDBConn), taxRepository.NewAggregatorRepository(serviceDBConn), &healthDomain.HealthConfig{
+		Timeout: time.Duration(config.Timeout.HealthCheckMs) * time.Millisecond,
+		AggregatorEnabled: config.Aggregator.Enabled,
+		CloseDelay: time.Duration(config.Aggregator.CloseDelaySeconds) * time.Second,
 	})
-	taxHandler := taxHandler.NewTaxHandler(taxUsecase)
-	taxHandler.Routes(e)
+	healthHandler := healthHandler.NewHealthHandler(healthUsecase)
+	healthHandler.Routes(e)
+}
+
+// JobRegistry register periodic jobs into the scheduler, only one replica holding the service database lock run each job.
+func JobRegistry(e Server, serviceDBConn *sql.DB, config *config.Config, usecase domain.TaxUsecase, auditUsecase auditDomain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) (jobDomain.JobScheduler, error) {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	var locker scheduler.Locker // sqlite has no distributed lock, jobs are refused
+	switch dbconn.DialectOf(serviceDBConn).Driver {
+	case dbconn.DriverPostgres:
+		locker = scheduler.NewPostgresLocker(serviceDBConn)
+	case dbconn.DriverMySQL:
+		locker = scheduler.NewMySQLLocker(serviceDBConn)
+	}
+	jobScheduler := jobUsecase.NewJobScheduler(jobRepository.NewJobRepository(serviceDBConn), locker, instance)
+	if config.Aggregator.Enabled {
+		aggregatorUsecase := taxUsecase.NewAggregatorUsecase(usecase, taxRepository.NewAggregatorRepository(serviceDBConn), &domain.AggregatorConfig{
+			CloseDelay: time.Duration(config.Aggregator.CloseDelaySeconds) * time.Second,
+			StartDate: config.Aggregator.StartDate,
+		})
+		if err := jobScheduler.Register(jobDomain.Job{
+			Name: domain.DailyAggregationWatermark,
+			Schedule: config.Aggregator.Schedule,
+			Run: aggregatorUsecase.Aggregate,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	jobHandler := jobHandler.NewJobHandler(jobScheduler, auditUsecase, authenticator, limiter)
+	jobHandler.Routes(e)
+	return jobScheduler, nil
 }
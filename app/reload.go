@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"tax-aggregator-service-demo/audit"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/auth"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+	"tax-aggregator-service-demo/tax/domain"
+	"time"
+
+	auditDomain "tax-aggregator-service-demo/audit/domain"
+	auditEntity "tax-aggregator-service-demo/audit/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+type configHandler struct {
+	reloader      *config.Reloader
+	auditUsecase  auditDomain.AuditUsecase
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+// ConfigRegistry reload the config file on SIGHUP, on POST /admin/config/reload for the admin role and on POST /config/reload
+// of the admin listener, the tax settings and the audit config hash are swapped on every valid reload. the returned handler
+// hold the current config, stop func stop listening for SIGHUP.
+func ConfigRegistry(e Server, cfg string, current *config.Config, usecase domain.TaxUsecase, auditUsecase auditDomain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) (handler *configHandler, stop func()) {
+	reloader := config.NewReloader(cfg, current)
+	reloader.Subscribe(func(config *config.Config) {
+		usecase.Reload(newTaxSettings(config))
+		if err := logger.SetLevel(config.Logging.Level); err != nil {
+			slog.Error("[main.ConfigRegistry]:: error setting log level.", logger.Err(err))
+		}
+		auditUsecase.SetConfigHash(config.Hash())
+	})
+	handler = &configHandler{reloader: reloader, auditUsecase: auditUsecase, authenticator: authenticator, limiter: limiter}
+	handler.Routes(e)
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			slog.Info("[main.ConfigRegistry]:: SIGHUP received, reloading config.")
+			handler.reload(context.Background(), &auditEntity.AuditLog{Actor: "SIGHUP", Params: "{}"})
+		}
+	}()
+	return handler, func() {
+		signal.Stop(hangup)
+		close(hangup)
+	}
+}
+
+func (ch *configHandler) Routes(echo *echo.Echo) {
+	echo.POST("/admin/config/reload", ch.ReloadConfig, ch.authenticator.Require(auth.RoleAdmin), ch.limiter.Middleware())
+}
+
+func (ch *configHandler) ReloadConfig(ctx echo.Context) error {
+	config, err := ch.reload(ctx.Request().Context(), &auditEntity.AuditLog{
+		Actor:        audit.Actor(ctx),
+		ClaimedActor: audit.ClaimedActor(ctx),
+		Params:       audit.Params(ctx.QueryParams()),
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, &domain.Response{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(http.StatusOK, &domain.Response{
+		Code:    http.StatusOK,
+		Message: "success reload config",
+		Data:    map[string]string{"config_hash": config.Hash()},
+	})
+}
+
+// reload the config file and record the attempt on audit trail, the audit log is stamped with the reloaded config hash.
+func (ch *configHandler) reload(ctx context.Context, auditLog *auditEntity.AuditLog) (*config.Config, error) {
+	auditLog.Action = auditDomain.AuditActionReloadConfig
+	config, err := ch.reloader.Reload()
+	if err != nil {
+		auditLog.Status = auditDomain.AuditStatusFailed
+		auditLog.Error = err.Error()
+	}
+	if err := ch.auditUsecase.Record(context.WithoutCancel(ctx), auditLog); err != nil {
+		slog.ErrorContext(ctx, "[main.configHandler.reload]:: error recording audit log.", logger.Err(err))
+	}
+	return config, err
+}
+
+// newTaxSettings return the reloadable tax settings of config.
+func newTaxSettings(config *config.Config) *domain.TaxSettings {
+	return &domain.TaxSettings{
+		TaxConfig: domain.TaxConfig{
+			TimeStartPpn:    config.PpnConfig.TimeStartPpn,
+			TimeStartPpnNew: config.PpnConfig.TimeStartPpnNew,
+			TarifPpn:        config.PpnConfig.TarifPpn,
+			TarifPpnNew:     config.PpnConfig.TarifPpnNew,
+		},
+		SourceConcurrency:   config.SourceQuery.Concurrency,
+		SourceQueryTimeout:  time.Duration(config.Timeout.SourceQueryMs) * time.Millisecond,
+		ServiceQueryTimeout: time.Duration(config.Timeout.ServiceQueryMs) * time.Millisecond,
+		ChunkDays:           config.SourceQuery.ChunkDays,
+		ChunkConcurrency:    config.SourceQuery.ChunkConcurrency,
+	}
+}
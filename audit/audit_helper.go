@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"encoding/json"
+	"tax-aggregator-service-demo/pkg/auth"
+
+	"github.com/labstack/echo/v4"
+)
+
+// caller identity header set by the monolith, eg: the user acting through it. it is client controlled so it is
+// only recorded as the claimed actor.
+const ActorHeader = "X-Actor"
+
+// Actor return the authenticated caller identity of the request, the subject of the bearer token or the api key
+// client, the client ip when the request is not authenticated.
+func Actor(ctx echo.Context) string {
+	if claims, ok := auth.FromContext(ctx.Request().Context()); ok && claims.Subject != "" {
+		return claims.Subject
+	}
+	return ctx.RealIP()
+}
+
+// ClaimedActor return the identity the caller claims in the actor header, empty when missing.
+func ClaimedActor(ctx echo.Context) string {
+	return ctx.Request().Header.Get(ActorHeader)
+}
+
+// Params encode the request params as json to be stored on the audit log.
+func Params(params any) string {
+	bytes, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"tax-aggregator-service-demo/pkg/auth"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	newContext := func(claims *auth.Claims) echo.Context {
+		request := httptest.NewRequest(http.MethodGet, "/tax", nil)
+		request.RemoteAddr = "203.0.113.7:40000"
+		request.Header.Set(ActorHeader, "admin@example.com")
+		if claims != nil {
+			request = request.WithContext(auth.WithClaims(request.Context(), claims))
+		}
+		e := echo.New()
+		e.IPExtractor = echo.ExtractIPDirect()
+		return e.NewContext(request, httptest.NewRecorder())
+	}
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test actor is the authenticated subject and the header only claimed",
+			testFunction: func(t *testing.T) {
+				ctx := newContext(&auth.Claims{Subject: "monolith", KeyID: "monolith-2024"})
+				assert.Equal(t, "monolith", Actor(ctx))
+				assert.Equal(t, "admin@example.com", ClaimedActor(ctx))
+			},
+		},
+		{
+			name: "test actor is the client ip when not authenticated",
+			testFunction: func(t *testing.T) {
+				ctx := newContext(nil)
+				assert.Equal(t, "203.0.113.7", Actor(ctx))
+				assert.Equal(t, "admin@example.com", ClaimedActor(ctx))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
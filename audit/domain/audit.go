@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"tax-aggregator-service-demo/audit/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// audited actions, admin actions are prefixed with admin.
+const (
+	AuditActionGetTax             = "tax.get"
+	AuditActionGetJobRuns         = "admin.get_job_runs"
+	AuditActionGetAuditLogs       = "admin.get_audit_logs"
+	AuditActionBackfill           = "admin.backfill"
+	AuditActionInvalidateTaxCache = "admin.invalidate_tax_cache"
+	AuditActionReloadConfig       = "admin.reload_config"
+	AuditActionFlushTaxCache      = "admin.flush_tax_cache"
+	AuditActionSetLogLevel        = "admin.set_log_level"
+)
+
+// audit log status stored in service database
+const (
+	AuditStatusSuccess = "success"
+	AuditStatusFailed  = "failed"
+)
+
+// audit filter from query params, zero value fields are not filtered
+type AuditFilter struct {
+	StartDate int64
+	EndDate   int64
+	Actor     string
+	Limit     int
+}
+
+// audit handler interface
+type AuditHandler interface {
+	Routes(route *echo.Echo)
+	GetAuditLogs(ctx echo.Context) error
+}
+
+// audit usecase interface contract for append-only audit trail
+type AuditUsecase interface {
+	Record(ctx context.Context, auditLog *entity.AuditLog) error
+	GetAuditLogs(ctx context.Context, auditFilter *AuditFilter) ([]entity.AuditLog, error)
+	SetConfigHash(configHash string)
+}
+
+// audit repository interface contract for audit log in service database, audit log is never updated nor deleted
+type AuditRepository interface {
+	InsertAuditLog(ctx context.Context, auditLog *entity.AuditLog) error
+	GetAuditLogs(ctx context.Context, auditFilter *AuditFilter) ([]entity.AuditLog, error)
+}
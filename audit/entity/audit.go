@@ -0,0 +1,17 @@
+package entity
+
+// Value Objects and Entities that will be mapped into service_database
+
+type AuditLog struct {
+	ID           int64  `json:"id"`
+	Action       string `json:"action"`
+	Actor        string `json:"actor"`
+	ClaimedActor string `json:"claimed_actor"`
+	Params       string `json:"params"`
+	Source       string `json:"source"`
+	RowsInserted int64  `json:"rows_inserted"`
+	ConfigHash   string `json:"config_hash"`
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	CreatedAt    int64  `json:"created_at"`
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"tax-aggregator-service-demo/audit"
+	"tax-aggregator-service-demo/audit/domain"
+	"tax-aggregator-service-demo/audit/entity"
+	"tax-aggregator-service-demo/pkg/auth"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+
+	taxDomain "tax-aggregator-service-demo/tax/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+type auditHandler struct {
+	auditUsecase  domain.AuditUsecase
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+func NewAuditHandler(auditUsecase domain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) domain.AuditHandler {
+	return &auditHandler{
+		auditUsecase:  auditUsecase,
+		authenticator: authenticator,
+		limiter:       limiter,
+	}
+}
+
+func (ah *auditHandler) Routes(echo *echo.Echo) {
+	echo.GET("/admin/audit", ah.GetAuditLogs, ah.authenticator.Require(auth.RoleAdmin), ah.limiter.Middleware())
+}
+
+func (ah *auditHandler) GetAuditLogs(ctx echo.Context) error {
+	auditFilter := &domain.AuditFilter{}
+	err := echo.QueryParamsBinder(ctx).
+		Int64("start_date", &auditFilter.StartDate).
+		Int64("end_date", &auditFilter.EndDate).
+		String("actor", &auditFilter.Actor).
+		Int("limit", &auditFilter.Limit).
+		BindError()
+	if err != nil {
+		slog.WarnContext(ctx.Request().Context(), "[AuditHandler.GetAuditLogs]:: error bind query params", logger.Err(err))
+		return ctx.JSON(http.StatusBadRequest, &taxDomain.Response{
+			Code:    http.StatusBadRequest,
+			Message: "bad request",
+		})
+	}
+	auditLog := &entity.AuditLog{
+		Action:       domain.AuditActionGetAuditLogs,
+		Actor:        audit.Actor(ctx),
+		ClaimedActor: audit.ClaimedActor(ctx),
+		Params:       audit.Params(ctx.QueryParams()),
+	}
+	auditLogs, err := ah.auditUsecase.GetAuditLogs(ctx.Request().Context(), auditFilter)
+	if err != nil {
+		auditLog.Status = domain.AuditStatusFailed
+		auditLog.Error = err.Error()
+	}
+	if err := ah.auditUsecase.Record(context.WithoutCancel(ctx.Request().Context()), auditLog); err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "[AuditHandler.GetAuditLogs]:: error recording audit log.", logger.Err(err))
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, &taxDomain.Response{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(http.StatusOK, &taxDomain.Response{
+		Code:    http.StatusOK,
+		Message: "success get audit logs",
+		Data:    auditLogs,
+	})
+}
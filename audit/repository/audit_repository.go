@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"tax-aggregator-service-demo/audit/domain"
+	"tax-aggregator-service-demo/audit/entity"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
+)
+
+type auditRepository struct {
+	serviceConn *sql.DB
+	dialect     dbconn.Dialect
+}
+
+func NewAuditRepository(serviceConn *sql.DB) domain.AuditRepository {
+	return &auditRepository{
+		serviceConn: serviceConn,
+		dialect:     dbconn.DialectOf(serviceConn),
+	}
+}
+
+// insert audit log query from service database.
+const insertAuditLog = `
+	INSERT INTO
+		audit_log(action, actor, claimed_actor, params, source, rows_inserted, config_hash, status, error, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+func (ar *auditRepository) InsertAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	serviceConn := ar.serviceConn
+	query, args := ar.dialect.Rebind(insertAuditLog,
+		auditLog.Action,
+		auditLog.Actor,
+		auditLog.ClaimedActor,
+		auditLog.Params,
+		auditLog.Source,
+		auditLog.RowsInserted,
+		auditLog.ConfigHash,
+		auditLog.Status,
+		auditLog.Error,
+		auditLog.CreatedAt,
+	)
+	if _, err := serviceConn.ExecContext(ctx, query, args...); err != nil {
+		slog.ErrorContext(ctx, "[AuditRepository.InsertAuditLog]:: error insert audit_log into service database.", logger.Err(err))
+		return err
+	}
+	return nil
+}
+
+// get audit logs query from service database, zero start date, end date and empty actor are not filtered.
+const getAuditLogs = `
+	SELECT
+		a.id,
+		a.action,
+		a.actor,
+		a.claimed_actor,
+		a.params,
+		a.source,
+		a.rows_inserted,
+		a.config_hash,
+		a.status,
+		a.error,
+		a.created_at
+	FROM
+		audit_log AS a
+	WHERE
+		($1 = 0 OR a.created_at >= $1)
+	AND
+		($2 = 0 OR a.created_at < $2)
+	AND
+		($3 = '' OR a.actor = $3)
+	ORDER BY
+		a.created_at
+	DESC
+	LIMIT $4
+`
+
+func (ar *auditRepository) GetAuditLogs(ctx context.Context, auditFilter *domain.AuditFilter) ([]entity.AuditLog, error) {
+	serviceConn := ar.serviceConn
+	auditLogs := []entity.AuditLog{}
+	query, args := ar.dialect.Rebind(getAuditLogs, auditFilter.StartDate, auditFilter.EndDate, auditFilter.Actor, auditFilter.Limit)
+	r, err := serviceConn.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuditRepository.GetAuditLogs]:: error getting audit_log from service database.", logger.Err(err))
+		return nil, err
+	}
+	defer r.Close()
+	auditLog := &entity.AuditLog{}
+	for r.Next() {
+		if err := r.Scan(
+			&auditLog.ID,
+			&auditLog.Action,
+			&auditLog.Actor,
+			&auditLog.ClaimedActor,
+			&auditLog.Params,
+			&auditLog.Source,
+			&auditLog.RowsInserted,
+			&auditLog.ConfigHash,
+			&auditLog.Status,
+			&auditLog.Error,
+			&auditLog.CreatedAt,
+		); err != nil {
+			slog.ErrorContext(ctx, "[AuditRepository.GetAuditLogs]:: error scanning audit_log from service database.", logger.Err(err))
+			return nil, err
+		}
+		auditLogs = append(auditLogs, *auditLog)
+	}
+	return auditLogs, r.Err()
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"tax-aggregator-service-demo/audit/domain"
+	"tax-aggregator-service-demo/audit/entity"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_InsertAuditLog(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceMock.ExpectExec(regexp.QuoteMeta(insertAuditLog)).
+		WithArgs("tax.get", "monolith", "user@example.com", `{"amount_of_days":["3"]}`, "source", int64(2), "abc", "success", "", int64(1683658800)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	auditRepository := NewAuditRepository(serviceConn)
+	assert.NoError(t, auditRepository.InsertAuditLog(context.Background(), &entity.AuditLog{
+		Action:       "tax.get",
+		Actor:        "monolith",
+		ClaimedActor: "user@example.com",
+		Params:       `{"amount_of_days":["3"]}`,
+		Source:       "source",
+		RowsInserted: 2,
+		ConfigHash:   "abc",
+		Status:       "success",
+		CreatedAt:    1683658800,
+	}))
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_GetAuditLogs(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	rows := sqlmock.NewRows([]string{"id", "action", "actor", "claimed_actor", "params", "source", "rows_inserted", "config_hash", "status", "error", "created_at"})
+	rows.AddRow(2, "tax.get", "monolith", "user@example.com", "{}", "cache", 0, "abc", "success", "", 1683658900)
+	rows.AddRow(1, "tax.get", "monolith", "", "{}", "source", 3, "abc", "success", "", 1683658800)
+	serviceMock.ExpectQuery(regexp.QuoteMeta(getAuditLogs)).WithArgs(int64(1683590400), int64(0), "monolith", 100).WillReturnRows(rows)
+	auditRepository := NewAuditRepository(serviceConn)
+	auditLogs, err := auditRepository.GetAuditLogs(context.Background(), &domain.AuditFilter{StartDate: 1683590400, Actor: "monolith", Limit: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.AuditLog{
+		{ID: 2, Action: "tax.get", Actor: "monolith", ClaimedActor: "user@example.com", Params: "{}", Source: "cache", ConfigHash: "abc", Status: "success", CreatedAt: 1683658900},
+		{ID: 1, Action: "tax.get", Actor: "monolith", Params: "{}", Source: "source", RowsInserted: 3, ConfigHash: "abc", Status: "success", CreatedAt: 1683658800},
+	}, auditLogs)
+}
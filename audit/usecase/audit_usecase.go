@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"tax-aggregator-service-demo/audit/domain"
+	"tax-aggregator-service-demo/audit/entity"
+	"time"
+)
+
+const (
+	defaultAuditLogsLimit = 100
+	maxAuditLogsLimit     = 1000
+)
+
+type auditUsecase struct {
+	auditRepository domain.AuditRepository
+	configHash      atomic.Pointer[string]
+	now             func() time.Time
+}
+
+// NewAuditUsecase create audit usecase, config hash is stamped on every audit log to tell which configuration produced the numbers.
+func NewAuditUsecase(auditRepository domain.AuditRepository, configHash string) domain.AuditUsecase {
+	auditUsecase := &auditUsecase{
+		auditRepository: auditRepository,
+		now:             time.Now,
+	}
+	auditUsecase.configHash.Store(&configHash)
+	return auditUsecase
+}
+
+// SetConfigHash stamp configHash on the next audit logs, eg: after the configuration is reloaded.
+func (au *auditUsecase) SetConfigHash(configHash string) {
+	au.configHash.Store(&configHash)
+}
+
+func (au *auditUsecase) Record(ctx context.Context, auditLog *entity.AuditLog) error {
+	auditLog.ConfigHash = *au.configHash.Load()
+	auditLog.CreatedAt = au.now().Unix()
+	if auditLog.Status == "" {
+		auditLog.Status = domain.AuditStatusSuccess
+	}
+	return au.auditRepository.InsertAuditLog(ctx, auditLog)
+}
+
+func (au *auditUsecase) GetAuditLogs(ctx context.Context, auditFilter *domain.AuditFilter) ([]entity.AuditLog, error) {
+	if auditFilter.Limit <= 0 {
+		auditFilter.Limit = defaultAuditLogsLimit
+	}
+	if auditFilter.Limit > maxAuditLogsLimit {
+		auditFilter.Limit = maxAuditLogsLimit
+	}
+	return au.auditRepository.GetAuditLogs(ctx, auditFilter)
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"tax-aggregator-service-demo/audit/domain"
+	"tax-aggregator-service-demo/audit/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/audit/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditUsecase_Record(t *testing.T) {
+	auditRepository := mocks.NewAuditRepository(t)
+	auditRepository.EXPECT().InsertAuditLog(mock.Anything, mock.MatchedBy(func(auditLog *entity.AuditLog) bool {
+		return auditLog.ConfigHash == "abc" && auditLog.CreatedAt == 1683658800 && auditLog.Status == domain.AuditStatusSuccess
+	})).Return(nil).Once()
+	auditRepository.EXPECT().InsertAuditLog(mock.Anything, mock.MatchedBy(func(auditLog *entity.AuditLog) bool {
+		return auditLog.ConfigHash == "def"
+	})).Return(nil).Once()
+	auditUsecase := NewAuditUsecase(auditRepository, "abc").(*auditUsecase)
+	auditUsecase.now = func() time.Time { return time.Unix(1683658800, 0) }
+	assert.NoError(t, auditUsecase.Record(context.Background(), &entity.AuditLog{Action: domain.AuditActionGetTax, Actor: "monolith"}))
+	auditUsecase.SetConfigHash("def") // config reloaded
+	assert.NoError(t, auditUsecase.Record(context.Background(), &entity.AuditLog{Action: domain.AuditActionReloadConfig, Actor: "SIGHUP"}))
+}
+
+func TestAuditUsecase_GetAuditLogs(t *testing.T) {
+	auditRepository := mocks.NewAuditRepository(t)
+	auditRepository.EXPECT().GetAuditLogs(mock.Anything, &domain.AuditFilter{Actor: "monolith", Limit: defaultAuditLogsLimit}).Return([]entity.AuditLog{}, nil).Once()
+	auditRepository.EXPECT().GetAuditLogs(mock.Anything, &domain.AuditFilter{Limit: maxAuditLogsLimit}).Return([]entity.AuditLog{}, nil).Once()
+	auditUsecase := NewAuditUsecase(auditRepository, "abc")
+	_, err := auditUsecase.GetAuditLogs(context.Background(), &domain.AuditFilter{Actor: "monolith"})
+	assert.NoError(t, err)
+	_, err = auditUsecase.GetAuditLogs(context.Background(), &domain.AuditFilter{Limit: 5000})
+	assert.NoError(t, err)
+}
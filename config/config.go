@@ -1,51 +1,299 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/labstack/gommon/log"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// database connection, driver is one of mysql, postgres or sqlite (database_name is the file path on sqlite),
+// replicas are only used on source database for aggregate queries,
+// replicas behind the primary more than max_replica_lag_seconds are skipped (lag is not checked when not set)
+// and every replica is health checked each replica_check_interval_seconds (default 5 seconds)
 type Database struct {
-	DBUsername string `json:"username"`
-	DBPassword string `json:"password"`
-	DBHost     string `json:"host"`
-	DBPort     string `json:"port"`
-	DBName     string `json:"database_name"`
+	Driver                      string     `json:"driver,omitempty" yaml:"driver" toml:"driver"`
+	DBUsername                  string     `json:"username" yaml:"username" toml:"username"`
+	DBPassword                  string     `json:"password" yaml:"password" toml:"password"`
+	DBHost                      string     `json:"host" yaml:"host" toml:"host"`
+	DBPort                      string     `json:"port" yaml:"port" toml:"port"`
+	DBName                      string     `json:"database_name" yaml:"database_name" toml:"database_name"`
+	Replicas                    []Database `json:"replicas,omitempty" yaml:"replicas" toml:"replicas"`
+	MaxReplicaLagSeconds        int64      `json:"max_replica_lag_seconds,omitempty" yaml:"max_replica_lag_seconds" toml:"max_replica_lag_seconds"`
+	ReplicaCheckIntervalSeconds int64      `json:"replica_check_interval_seconds,omitempty" yaml:"replica_check_interval_seconds" toml:"replica_check_interval_seconds"`
 }
 
+// database passwords written as secret:<name> are read from the secret manager provider,
+// file (name is a file under dir, eg: kubernetes mounted secret), env (name is an environment variable)
+// or vault (name is <path>#<key> of a kv v2 secret under vault_mount on vault_address).
+// secrets are re-read every refresh_seconds so new connections use rotated passwords
 type SecretManager struct {
-	SecretString string
+	Provider       string `json:"provider" yaml:"provider" toml:"provider"`
+	Dir            string `json:"dir" yaml:"dir" toml:"dir"`
+	VaultAddress   string `json:"vault_address" yaml:"vault_address" toml:"vault_address"`
+	VaultToken     string `json:"vault_token" yaml:"vault_token" toml:"vault_token"`
+	VaultMount     string `json:"vault_mount" yaml:"vault_mount" toml:"vault_mount"`
+	RefreshSeconds int64  `json:"refresh_seconds" yaml:"refresh_seconds" toml:"refresh_seconds"`
 }
 
+// SecretReferencePrefix mark a database password as the name of a secret on the secret manager.
+const SecretReferencePrefix = "secret:"
+
 type PpnConfig struct {
-	TimeStartPpn    int64 `json:"time_start_ppn"`
-	TimeStartPpnNew int64 `json:"time_start_ppn_new"`
-	TarifPpn        int64 `json:"tarif_ppn"`
-	TarifPpnNew     int64 `json:"tarif_ppn_new"`
+	TimeStartPpn    int64 `json:"time_start_ppn" yaml:"time_start_ppn" toml:"time_start_ppn"`
+	TimeStartPpnNew int64 `json:"time_start_ppn_new" yaml:"time_start_ppn_new" toml:"time_start_ppn_new"`
+	TarifPpn        int64 `json:"tarif_ppn" yaml:"tarif_ppn" toml:"tarif_ppn"`
+	TarifPpnNew     int64 `json:"tarif_ppn_new" yaml:"tarif_ppn_new" toml:"tarif_ppn_new"`
+}
+
+// daily aggregation job, when enabled reads are served only from service database
+type Aggregator struct {
+	Enabled           bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Schedule          string `json:"schedule" yaml:"schedule" toml:"schedule"`
+	CloseDelaySeconds int64  `json:"close_delay_seconds" yaml:"close_delay_seconds" toml:"close_delay_seconds"`
+	StartDate         int64  `json:"start_date" yaml:"start_date" toml:"start_date"`
+}
+
+// request and query deadlines in milliseconds, dbconn.DefaultTimeout is used when a query timeout is not set
+// and requests have no deadline when request_ms is not set. health check bound every dependency check of /readyz (2s when not set)
+// and shutdown bound draining in-flight requests and running jobs on SIGTERM/SIGINT (120s when not set)
+type Timeout struct {
+	RequestMs      int64 `json:"request_ms" yaml:"request_ms" toml:"request_ms"`
+	SourceQueryMs  int64 `json:"source_query_ms" yaml:"source_query_ms" toml:"source_query_ms"`
+	ServiceQueryMs int64 `json:"service_query_ms" yaml:"service_query_ms" toml:"service_query_ms"`
+	HealthCheckMs  int64 `json:"health_check_ms" yaml:"health_check_ms" toml:"health_check_ms"`
+	ShutdownMs     int64 `json:"shutdown_ms" yaml:"shutdown_ms" toml:"shutdown_ms"`
+}
+
+// source database query tuning, concurrency is the number of source queries running at the same time for a single range,
+// chunk days split a query over a range into sub-ranges per source table, up to chunk concurrency sub-ranges of a query run at the same time
+type SourceQuery struct {
+	Concurrency      int            `json:"concurrency" yaml:"concurrency" toml:"concurrency"`
+	ChunkDays        map[string]int `json:"chunk_days" yaml:"chunk_days" toml:"chunk_days"`
+	ChunkConcurrency int            `json:"chunk_concurrency" yaml:"chunk_concurrency" toml:"chunk_concurrency"`
+}
+
+// GetTax responses of ranges fully persisted in service database are cached in memory,
+// up to size responses for ttl_seconds (no expiry when not set), the cache is disabled when size is not set
+type ResultCache struct {
+	Size       int   `json:"size" yaml:"size" toml:"size"`
+	TTLSeconds int64 `json:"ttl_seconds" yaml:"ttl_seconds" toml:"ttl_seconds"`
+}
+
+// retry with jittered exponential backoff of transient database errors, up to retry_attempts calls per query,
+// the circuit breaker of a database opens after breaker_failure_threshold consecutive failures for breaker_open_timeout_ms
+type Resilience struct {
+	RetryAttempts           int   `json:"retry_attempts" yaml:"retry_attempts" toml:"retry_attempts"`
+	RetryBaseDelayMs        int64 `json:"retry_base_delay_ms" yaml:"retry_base_delay_ms" toml:"retry_base_delay_ms"`
+	RetryMaxDelayMs         int64 `json:"retry_max_delay_ms" yaml:"retry_max_delay_ms" toml:"retry_max_delay_ms"`
+	BreakerFailureThreshold int   `json:"breaker_failure_threshold" yaml:"breaker_failure_threshold" toml:"breaker_failure_threshold"`
+	BreakerOpenTimeoutMs    int64 `json:"breaker_open_timeout_ms" yaml:"breaker_open_timeout_ms" toml:"breaker_open_timeout_ms"`
+}
+
+// opentelemetry tracing, exporter is none (default), stdout (spans printed as json, for local use) or otlp
+// (otlp over http to endpoint host:port, the OTEL_EXPORTER_OTLP_* environment variables are used when not set),
+// sample ratio is the share of root traces recorded, traces started by the caller follow the caller sampling decision
+type Tracing struct {
+	Exporter    string  `json:"exporter" yaml:"exporter" toml:"exporter"`
+	Endpoint    string  `json:"endpoint" yaml:"endpoint" toml:"endpoint"`
+	Insecure    bool    `json:"insecure" yaml:"insecure" toml:"insecure"`
+	ServiceName string  `json:"service_name" yaml:"service_name" toml:"service_name"`
+	SampleRatio float64 `json:"sample_ratio" yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+// Logging configure the slog logger, format is json or text and level is debug, info, warn or error.
+// the level is applied on reload, the format after a restart
+type Logging struct {
+	Format string `json:"format" yaml:"format" toml:"format"`
+	Level  string `json:"level" yaml:"level" toml:"level"`
+}
+
+// Auth configure the jwt validation of requests, algorithm is HS256 with hmac secret (plain or a secret:<name> reference)
+// or RS256 with a pem public key file or a local jwks file. roles are read from the roles claim, issuer and audience
+// are checked when set. api keys authenticate hmac signed requests, a signature older than the signature window is rejected.
+// requests are not authenticated when auth is not enabled
+type Auth struct {
+	Enabled                bool     `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Algorithm              string   `json:"algorithm" yaml:"algorithm" toml:"algorithm"`
+	HMACSecret             string   `json:"hmac_secret" yaml:"hmac_secret" toml:"hmac_secret"`
+	PublicKeyFile          string   `json:"public_key_file" yaml:"public_key_file" toml:"public_key_file"`
+	JWKSFile               string   `json:"jwks_file" yaml:"jwks_file" toml:"jwks_file"`
+	Issuer                 string   `json:"issuer" yaml:"issuer" toml:"issuer"`
+	Audience               string   `json:"audience" yaml:"audience" toml:"audience"`
+	RolesClaim             string   `json:"roles_claim" yaml:"roles_claim" toml:"roles_claim"`
+	APIKeys                []APIKey `json:"api_keys" yaml:"api_keys" toml:"api_keys"`
+	SignatureWindowSeconds int64    `json:"signature_window_seconds" yaml:"signature_window_seconds" toml:"signature_window_seconds"`
+}
+
+// APIKey is a signing key of a client, a client can hold several keys at once to rotate them without downtime.
+// secret can be a secret:<name> reference, the rotated value is picked up on the next secret refresh
+type APIKey struct {
+	ID     string   `json:"id" yaml:"id" toml:"id"`
+	Client string   `json:"client" yaml:"client" toml:"client"`
+	Secret string   `json:"secret" yaml:"secret" toml:"secret"`
+	Roles  []string `json:"roles" yaml:"roles" toml:"roles"`
+}
+
+// RateLimit configure the per client token bucket of http routes, requests per second with burst, and the global limit
+// of FetchSourceTax running at once. up to source fetch queue size fetches wait for source fetch queue timeout ms
+// when every slot is taken. a limit is disabled when requests per second or source fetch concurrency is not set.
+// the client ip is the peer address, or the X-Forwarded-For address set by a proxy in trusted proxies (CIDRs)
+type RateLimit struct {
+	RequestsPerSecond         float64  `json:"requests_per_second" yaml:"requests_per_second" toml:"requests_per_second"`
+	Burst                     int      `json:"burst" yaml:"burst" toml:"burst"`
+	SourceFetchConcurrency    int      `json:"source_fetch_concurrency" yaml:"source_fetch_concurrency" toml:"source_fetch_concurrency"`
+	SourceFetchQueueSize      int      `json:"source_fetch_queue_size" yaml:"source_fetch_queue_size" toml:"source_fetch_queue_size"`
+	SourceFetchQueueTimeoutMs int64    `json:"source_fetch_queue_timeout_ms" yaml:"source_fetch_queue_timeout_ms" toml:"source_fetch_queue_timeout_ms"`
+	TrustedProxies            []string `json:"trusted_proxies" yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// Admin configure the optional admin listener serving pprof, the effective config, pool stats, build info and
+// maintenance actions, address should be bound to localhost or a port not exposed publicly
+type Admin struct {
+	Enabled bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Address string `json:"address" yaml:"address" toml:"address"`
 }
 
 type Config struct {
-	SourceDatabase  Database      `json:"source_database"`
-	ServiceDatabase Database      `json:"service_database"`
-	SecretManager   SecretManager `json:"secret_manager"`
-	PpnConfig       PpnConfig     `json:"ppn_config"`
+	SourceDatabase  Database      `json:"source_database" yaml:"source_database" toml:"source_database"`
+	ServiceDatabase Database      `json:"service_database" yaml:"service_database" toml:"service_database"`
+	SecretManager   SecretManager `json:"secret_manager" yaml:"secret_manager" toml:"secret_manager"`
+	PpnConfig       PpnConfig     `json:"ppn_config" yaml:"ppn_config" toml:"ppn_config"`
+	Aggregator      Aggregator    `json:"aggregator" yaml:"aggregator" toml:"aggregator"`
+	Timeout         Timeout       `json:"timeout" yaml:"timeout" toml:"timeout"`
+	SourceQuery     SourceQuery   `json:"source_query" yaml:"source_query" toml:"source_query"`
+	ResultCache     ResultCache   `json:"result_cache" yaml:"result_cache" toml:"result_cache"`
+	Resilience      Resilience    `json:"resilience" yaml:"resilience" toml:"resilience"`
+	Tracing         Tracing       `json:"tracing" yaml:"tracing" toml:"tracing"`
+	Logging         Logging       `json:"logging" yaml:"logging" toml:"logging"`
+	Auth            Auth          `json:"auth" yaml:"auth" toml:"auth"`
+	RateLimit       RateLimit     `json:"rate_limit" yaml:"rate_limit" toml:"rate_limit"`
+	Admin           Admin         `json:"admin" yaml:"admin" toml:"admin"`
+}
+
+// Default return the configuration applied before the config file and environment variables.
+func Default() *Config {
+	return &Config{
+		SourceDatabase: Database{
+			Driver: "mysql",
+			DBPort: "3306",
+		},
+		ServiceDatabase: Database{
+			Driver: "postgres",
+			DBPort: "5432",
+		},
+		SecretManager: SecretManager{
+			VaultMount:     "secret",
+			RefreshSeconds: 60,
+		},
+		Aggregator: Aggregator{
+			Schedule: "*/10 * * * *",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "tax-aggregator-service",
+			SampleRatio: 1,
+		},
+		Logging: Logging{
+			Format: "json",
+			Level:  "info",
+		},
+		Auth: Auth{
+			RolesClaim:             "roles",
+			SignatureWindowSeconds: 300,
+		},
+		Admin: Admin{
+			Address: "127.0.0.1:6060",
+		},
+	}
 }
 
+// LoadConfig layer the configuration from defaults, the config file (json, yaml or toml by extension)
+// and TAX_ prefixed environment variables, then validate it. unknown keys in the config file are rejected.
 func LoadConfig(path string) (*Config, error) {
+	slog.Debug("[config.LoadConfig]:: reading config file...", slog.String("path", path))
 	bytes, err := os.ReadFile(filepath.Clean(path))
-	log.Info("[config.LoadConfig]:: reading config file...")
 	if err != nil {
 		return nil, err
 	}
-	config := &Config{}
-	if err := json.Unmarshal(bytes, &config); err != nil {
+	config := Default()
+	if err := decode(path, bytes, config); err != nil {
 		return nil, err
 	}
-	if err != nil {
+	if err := applyEnv(config, EnvPrefix, os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 	return config, nil
 }
+
+func decode(path string, content []byte, config *Config) error {
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+	case ".toml":
+		decoder := toml.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	default:
+		return fmt.Errorf("config: unsupported config file extension %q, expected .json, .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config: decoding %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Hash return sha256 of the loaded configuration, stamped on audit logs to tell which configuration produced the numbers.
+func (c *Config) Hash() string {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// redacted replace a secret value, secret:<name> references are kept since they only name the secret.
+const redacted = "[REDACTED]"
+
+// Redacted return a copy of the config with passwords, tokens and signing secrets redacted, eg: to be displayed.
+func (c *Config) Redacted() *Config {
+	config := *c
+	redact := func(value *string) {
+		if *value != "" && !strings.HasPrefix(*value, SecretReferencePrefix) {
+			*value = redacted
+		}
+	}
+	for _, database := range []*Database{&config.SourceDatabase, &config.ServiceDatabase} {
+		redact(&database.DBPassword)
+		database.Replicas = append([]Database{}, database.Replicas...)
+		for i := range database.Replicas {
+			redact(&database.Replicas[i].DBPassword)
+		}
+	}
+	redact(&config.SecretManager.VaultToken)
+	redact(&config.Auth.HMACSecret)
+	config.Auth.APIKeys = append([]APIKey{}, config.Auth.APIKeys...)
+	for i := range config.Auth.APIKeys {
+		redact(&config.Auth.APIKeys[i].Secret)
+	}
+	return &config
+}
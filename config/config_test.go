@@ -2,6 +2,7 @@ package config
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +39,7 @@ func TestLoadConfig(t *testing.T) {
 			},
 			expectedConfig: &Config{
 				SourceDatabase: Database{
+					Driver:     "mysql",
 					DBUsername: "source-database-username",
 					DBPassword: "source-database-password",
 					DBHost:     "127.0.0.1",
@@ -45,6 +47,7 @@ func TestLoadConfig(t *testing.T) {
 					DBName:     "source-database-name",
 				},
 				ServiceDatabase: Database{
+					Driver:     "postgres",
 					DBUsername: "service-database-username",
 					DBPassword: "service-database-password",
 					DBHost:     "127.0.0.1",
@@ -52,7 +55,8 @@ func TestLoadConfig(t *testing.T) {
 					DBName:     "service-database-name",
 				},
 				SecretManager: SecretManager{
-					SecretString: "",
+					VaultMount:     "secret",
+					RefreshSeconds: 60,
 				},
 				PpnConfig: PpnConfig{
 					TimeStartPpn:    1478624400,
@@ -60,6 +64,25 @@ func TestLoadConfig(t *testing.T) {
 					TimeStartPpnNew: 1648746000,
 					TarifPpnNew:     11,
 				},
+				Aggregator: Aggregator{
+					Schedule: "*/10 * * * *",
+				},
+				Tracing: Tracing{
+					Exporter:    "none",
+					ServiceName: "tax-aggregator-service",
+					SampleRatio: 1,
+				},
+				Logging: Logging{
+					Format: "json",
+					Level:  "info",
+				},
+				Auth: Auth{
+					RolesClaim:             "roles",
+					SignatureWindowSeconds: 300,
+				},
+				Admin: Admin{
+					Address: "127.0.0.1:6060",
+				},
 			},
 			expectedError: false,
 		},
@@ -70,6 +93,7 @@ func TestLoadConfig(t *testing.T) {
 			},
 			expectedConfig: &Config{
 				SourceDatabase: Database{
+					Driver:     "mysql",
 					DBUsername: "root",
 					DBPassword: "root",
 					DBHost:     "127.0.0.1",
@@ -77,6 +101,7 @@ func TestLoadConfig(t *testing.T) {
 					DBName:     "source",
 				},
 				ServiceDatabase: Database{
+					Driver:     "postgres",
 					DBUsername: "postgres",
 					DBPassword: "postgres",
 					DBHost:     "127.0.0.1",
@@ -84,7 +109,8 @@ func TestLoadConfig(t *testing.T) {
 					DBName:     "tax-aggregator",
 				},
 				SecretManager: SecretManager{
-					SecretString: "",
+					VaultMount:     "secret",
+					RefreshSeconds: 60,
 				},
 				PpnConfig: PpnConfig{
 					TimeStartPpn:    1478624400,
@@ -92,6 +118,25 @@ func TestLoadConfig(t *testing.T) {
 					TimeStartPpnNew: 1648746000,
 					TarifPpnNew:     11,
 				},
+				Aggregator: Aggregator{
+					Schedule: "*/10 * * * *",
+				},
+				Tracing: Tracing{
+					Exporter:    "none",
+					ServiceName: "tax-aggregator-service",
+					SampleRatio: 1,
+				},
+				Logging: Logging{
+					Format: "json",
+					Level:  "info",
+				},
+				Auth: Auth{
+					RolesClaim:             "roles",
+					SignatureWindowSeconds: 300,
+				},
+				Admin: Admin{
+					Address: "127.0.0.1:6060",
+				},
 			},
 			expectedError: false,
 		},
@@ -109,3 +154,268 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_Hash(t *testing.T) {
+	config, err := LoadConfig("../config/test.json")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	hash := config.Hash()
+	if len(hash) != 64 {
+		t.Errorf("Hash() = %v, expected sha256 hex", hash)
+	}
+	config.PpnConfig.TarifPpnNew = 12
+	if config.Hash() == hash {
+		t.Errorf("Hash() expected to change when config changed")
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	config := Default()
+	config.SourceDatabase.DBPassword = "source-password"
+	config.SourceDatabase.Replicas = []Database{{DBPassword: "replica-password"}}
+	config.ServiceDatabase.DBPassword = "secret:tax-aggregator/service-database#password"
+	config.SecretManager.VaultToken = "vault-token"
+	config.Auth.HMACSecret = "hmac-secret"
+	config.Auth.APIKeys = []APIKey{{ID: "monolith-2024", Secret: "api-key-secret"}}
+
+	redactedConfig := config.Redacted()
+	for key, value := range map[string]string{
+		"source_database.password":             redactedConfig.SourceDatabase.DBPassword,
+		"source_database.replicas[0].password": redactedConfig.SourceDatabase.Replicas[0].DBPassword,
+		"secret_manager.vault_token":           redactedConfig.SecretManager.VaultToken,
+		"auth.hmac_secret":                     redactedConfig.Auth.HMACSecret,
+		"auth.api_keys[0].secret":              redactedConfig.Auth.APIKeys[0].Secret,
+	} {
+		if value != "[REDACTED]" {
+			t.Errorf("Redacted() %s = %v, expected redacted", key, value)
+		}
+	}
+	if redactedConfig.ServiceDatabase.DBPassword != "secret:tax-aggregator/service-database#password" {
+		t.Errorf("Redacted() service_database.password = %v, expected secret reference kept", redactedConfig.ServiceDatabase.DBPassword)
+	}
+	if config.SourceDatabase.Replicas[0].DBPassword != "replica-password" || config.Auth.APIKeys[0].Secret != "api-key-secret" {
+		t.Errorf("Redacted() modified the original config")
+	}
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	expectedConfig, err := LoadConfig("../config/test.json")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	tests := []struct {
+		name          string
+		path          string
+		expectedError bool
+	}{
+		{
+			name: "test passed when trying to open yaml config",
+			path: "../config/test.yaml",
+		},
+		{
+			name: "test passed when trying to open toml config",
+			path: "../config/test.toml",
+		},
+		{
+			name:          "test failed when config file extension is not supported",
+			path:          "../config/config.go",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := LoadConfig(tt.path)
+			if (err != nil) != tt.expectedError {
+				t.Errorf("LoadConfig() error = %v, expected error = %v", err, tt.expectedError)
+				return
+			}
+			if !tt.expectedError && !reflect.DeepEqual(result, expectedConfig) {
+				t.Errorf("LoadConfig() = %v, expected config = %v", result, expectedConfig)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Env(t *testing.T) {
+	t.Setenv("TAX_SOURCE_DATABASE_HOST", "source.internal")
+	t.Setenv("TAX_SERVICE_DATABASE_PASSWORD", "from-secret")
+	t.Setenv("TAX_AGGREGATOR_ENABLED", "true")
+	t.Setenv("TAX_TIMEOUT_REQUEST_MS", "5000")
+	t.Setenv("TAX_SOURCE_QUERY_CHUNK_DAYS", `{"fees":1}`)
+	config, err := LoadConfig("../config/config-sample.json")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.SourceDatabase.DBHost != "source.internal" || config.ServiceDatabase.DBPassword != "from-secret" {
+		t.Errorf("LoadConfig() database = %v, %v, expected overridden by env", config.SourceDatabase, config.ServiceDatabase)
+	}
+	if !config.Aggregator.Enabled || config.Timeout.RequestMs != 5000 {
+		t.Errorf("LoadConfig() aggregator = %v, timeout = %v, expected overridden by env", config.Aggregator, config.Timeout)
+	}
+	if !reflect.DeepEqual(config.SourceQuery.ChunkDays, map[string]int{"fees": 1}) {
+		t.Errorf("LoadConfig() chunk days = %v, expected replaced by env", config.SourceQuery.ChunkDays)
+	}
+
+	t.Setenv("TAX_SOURCE_DATABASE_MAX_REPLICA_LAG_SECONDS", "ten")
+	if _, err := LoadConfig("../config/config-sample.json"); err == nil || !strings.Contains(err.Error(), "source_database.max_replica_lag_seconds") {
+		t.Errorf("LoadConfig() error = %v, expected naming source_database.max_replica_lag_seconds", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		config, err := LoadConfig("../config/config-sample.json")
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		return config
+	}
+	tests := []struct {
+		name        string
+		modify      func(config *Config)
+		expectedKey string
+	}{
+		{
+			name:        "test failed when host is missing",
+			modify:      func(config *Config) { config.SourceDatabase.DBHost = "" },
+			expectedKey: "source_database.host",
+		},
+		{
+			name:        "test failed when port is out of range",
+			modify:      func(config *Config) { config.ServiceDatabase.DBPort = "70000" },
+			expectedKey: "service_database.port",
+		},
+		{
+			name:        "test failed when replica port is not a number",
+			modify:      func(config *Config) { config.SourceDatabase.Replicas[0].DBPort = "mysql" },
+			expectedKey: "source_database.replicas[0].port",
+		},
+		{
+			name:        "test failed when driver is unknown",
+			modify:      func(config *Config) { config.ServiceDatabase.Driver = "oracle" },
+			expectedKey: "service_database.driver",
+		},
+		{
+			name:        "test failed when ppn rate is above 100",
+			modify:      func(config *Config) { config.PpnConfig.TarifPpnNew = 110 },
+			expectedKey: "ppn_config.tarif_ppn_new",
+		},
+		{
+			name:        "test failed when new ppn starts before old ppn",
+			modify:      func(config *Config) { config.PpnConfig.TimeStartPpnNew = config.PpnConfig.TimeStartPpn - 1 },
+			expectedKey: "ppn_config.time_start_ppn_new",
+		},
+		{
+			name: "test failed when aggregator schedule is invalid",
+			modify: func(config *Config) {
+				config.Aggregator.Enabled = true
+				config.Aggregator.Schedule = "every day"
+			},
+			expectedKey: "aggregator.schedule",
+		},
+		{
+			name:        "test failed when max retry delay is below base delay",
+			modify:      func(config *Config) { config.Resilience.RetryMaxDelayMs = 10 },
+			expectedKey: "resilience.retry_max_delay_ms",
+		},
+		{
+			name:        "test failed when password reference secret without provider",
+			modify:      func(config *Config) { config.ServiceDatabase.DBPassword = "secret:service-database-password" },
+			expectedKey: "service_database.password",
+		},
+		{
+			name: "test failed when vault provider has no address",
+			modify: func(config *Config) {
+				config.SecretManager.Provider = "vault"
+				config.SecretManager.VaultToken = "token"
+			},
+			expectedKey: "secret_manager.vault_address",
+		},
+		{
+			name:        "test failed when tracing exporter is unknown",
+			modify:      func(config *Config) { config.Tracing.Exporter = "zipkin" },
+			expectedKey: "tracing.exporter",
+		},
+		{
+			name:        "test failed when tracing sample ratio is above 1",
+			modify:      func(config *Config) { config.Tracing.SampleRatio = 1.5 },
+			expectedKey: "tracing.sample_ratio",
+		},
+		{
+			name:        "test failed when logging level is unknown",
+			modify:      func(config *Config) { config.Logging.Level = "verbose" },
+			expectedKey: "logging.level",
+		},
+		{
+			name: "test failed when rs256 auth has no key",
+			modify: func(config *Config) {
+				config.Auth.Enabled = true
+				config.Auth.Algorithm = "RS256"
+				config.Auth.JWKSFile = ""
+			},
+			expectedKey: "auth.public_key_file",
+		},
+		{
+			name: "test failed when api key id is duplicated",
+			modify: func(config *Config) {
+				config.Auth.Enabled = true
+				config.Auth.APIKeys = []APIKey{
+					{ID: "monolith-2024", Client: "monolith", Secret: "secret", Roles: []string{"accountant"}},
+					{ID: "monolith-2024", Client: "reporting", Secret: "secret", Roles: []string{"viewer"}},
+				}
+			},
+			expectedKey: "auth.api_keys[1].id",
+		},
+		{
+			name: "test passed on api keys without jwt algorithm",
+			modify: func(config *Config) {
+				config.Auth = Auth{
+					Enabled:    true,
+					RolesClaim: "roles",
+					APIKeys:    []APIKey{{ID: "monolith-2024", Client: "monolith", Secret: "secret", Roles: []string{"accountant"}}},
+				}
+			},
+		},
+		{
+			name:        "test failed when rate limit requests per second is negative",
+			modify:      func(config *Config) { config.RateLimit.RequestsPerSecond = -1 },
+			expectedKey: "rate_limit.requests_per_second",
+		},
+		{
+			name:        "test failed when rate limit trusted proxy is not a cidr",
+			modify:      func(config *Config) { config.RateLimit.TrustedProxies = []string{"10.0.0.0/8", "10.0.0.1"} },
+			expectedKey: "rate_limit.trusted_proxies",
+		},
+		{
+			name: "test failed when admin address has no port",
+			modify: func(config *Config) {
+				config.Admin.Enabled = true
+				config.Admin.Address = "localhost"
+			},
+			expectedKey: "admin.address",
+		},
+		{
+			name: "test passed on sqlite without host",
+			modify: func(config *Config) {
+				config.ServiceDatabase = Database{Driver: "sqlite", DBName: "./service.db"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.modify(config)
+			err := config.Validate()
+			if tt.expectedKey == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, expected nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "config: "+tt.expectedKey+":") {
+				t.Errorf("Validate() error = %v, expected naming %s", err, tt.expectedKey)
+			}
+		})
+	}
+}
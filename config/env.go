@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix of environment variables overriding the config file, the variable name is the prefix and the path
+// of json keys upper-cased and joined with underscore, e.g. TAX_SOURCE_DATABASE_HOST for source_database.host.
+// maps and lists are given as json, e.g. TAX_SOURCE_QUERY_CHUNK_DAYS='{"fees":1}'.
+const EnvPrefix = "TAX"
+
+// applyEnv override every field of config which environment variable is set.
+func applyEnv(config *Config, prefix string, lookupEnv func(string) (string, bool)) error {
+	return applyEnvStruct(reflect.ValueOf(config).Elem(), prefix, "", lookupEnv)
+}
+
+func applyEnvStruct(v reflect.Value, envPrefix, keyPrefix string, lookupEnv func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := keyName(field)
+		env := envPrefix + "_" + strings.ToUpper(name)
+		key := keyPrefix + name
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnvStruct(v.Field(i), env, key+".", lookupEnv); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := lookupEnv(env)
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("config: %s (%s): %w", key, env, err)
+		}
+	}
+	return nil
+}
+
+// keyName return the json key of field, the lower-cased field name when there is no json tag.
+func keyName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func setField(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		v.SetInt(n)
+	default:
+		v.Set(reflect.Zero(v.Type())) // replace rather than merge into the file maps and lists
+		if err := json.Unmarshal([]byte(value), v.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid json %q: %w", value, err)
+		}
+	}
+	return nil
+}
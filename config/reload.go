@@ -0,0 +1,89 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+	"tax-aggregator-service-demo/pkg/logger"
+)
+
+// Reloader reload the config file and hand the validated config to subscribers, an invalid config is rejected
+// and the current one is kept. settings of sections in RestartSections are only applied after a restart,
+// until then the reloaded config keep their running values.
+type Reloader struct {
+	path        string
+	mu          sync.Mutex
+	current     *Config
+	subscribers []func(*Config)
+}
+
+// RestartSections are the config sections read once at startup, changing them on reload only log a warning.
+var RestartSections = []string{"source_database", "service_database", "secret_manager", "aggregator", "result_cache", "resilience", "tracing", "auth", "rate_limit", "admin"}
+
+func NewReloader(path string, current *Config) *Reloader {
+	return &Reloader{
+		path:    path,
+		current: current,
+	}
+}
+
+// Subscribe call fn with the new config after every successful reload, fn must not call the reloader.
+func (r *Reloader) Subscribe(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Current return the running config, the last successfully loaded config with the RestartSections read at startup.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload load and validate the config file again, then notify subscribers. reloads are serialized.
+func (r *Reloader) Reload() (*Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	config, err := LoadConfig(r.path)
+	if err != nil {
+		slog.Error("[config.Reloader.Reload]:: rejected invalid config, keeping the current one.", logger.Err(err))
+		return nil, err
+	}
+	for _, section := range changedSections(r.current, config, RestartSections) {
+		slog.Warn("[config.Reloader.Reload]:: section changed, it is applied after a restart.", slog.String("section", section))
+	}
+	keepSections(r.current, config, RestartSections)
+	for _, subscriber := range r.subscribers {
+		subscriber(config)
+	}
+	r.current = config
+	slog.Info("[config.Reloader.Reload]:: config reloaded.", slog.String("config_hash", config.Hash()))
+	return config, nil
+}
+
+// keepSections copy sections of previous into next, so next describe the settings actually running.
+func keepSections(previous, next *Config, sections []string) {
+	previousValue, nextValue := reflect.ValueOf(previous).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < previousValue.NumField(); i++ {
+		if slices.Contains(sections, keyName(previousValue.Type().Field(i))) {
+			nextValue.Field(i).Set(previousValue.Field(i))
+		}
+	}
+}
+
+// changedSections return the json keys of sections which differ between previous and next.
+func changedSections(previous, next *Config, sections []string) []string {
+	changed := []string{}
+	previousValue, nextValue := reflect.ValueOf(previous).Elem(), reflect.ValueOf(next).Elem()
+	for i := 0; i < previousValue.NumField(); i++ {
+		name := keyName(previousValue.Type().Field(i))
+		for _, section := range sections {
+			if name == section && !reflect.DeepEqual(previousValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+				changed = append(changed, name)
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	sample, err := os.ReadFile("../config/config-sample.json")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, sample, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	current, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	reloader := NewReloader(path, current)
+	reloaded := []*Config{}
+	reloader.Subscribe(func(config *Config) { reloaded = append(reloaded, config) })
+
+	tests := []struct {
+		name          string
+		content       string
+		expectedError bool
+		expectedTarif int64
+	}{
+		{
+			name:          "test passed when reloading new ppn rate",
+			content:       strings.Replace(string(sample), `"tarif_ppn_new": 11`, `"tarif_ppn_new": 12`, 1),
+			expectedTarif: 12,
+		},
+		{
+			name:          "test failed and keep current config when reloading invalid ppn rate",
+			content:       strings.Replace(string(sample), `"tarif_ppn_new": 11`, `"tarif_ppn_new": 120`, 1),
+			expectedError: true,
+			expectedTarif: 12,
+		},
+		{
+			name:          "test failed and keep current config when reloading malformed file",
+			content:       "{",
+			expectedError: true,
+			expectedTarif: 12,
+		},
+		{
+			name:          "test passed and keep running source database when reloading restart section",
+			content:       strings.Replace(string(sample), `"host": "127.0.0.1"`, `"host": "10.0.0.1"`, 1),
+			expectedTarif: 11,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			notified := len(reloaded)
+			_, err := reloader.Reload()
+			if (err != nil) != tt.expectedError {
+				t.Errorf("Reload() error = %v, expected error = %v", err, tt.expectedError)
+			}
+			if tt.expectedError && len(reloaded) != notified {
+				t.Errorf("Reload() notified subscribers of an invalid config")
+			}
+			if reloader.Current().PpnConfig.TarifPpnNew != tt.expectedTarif {
+				t.Errorf("Current() tarif_ppn_new = %d, expected %d", reloader.Current().PpnConfig.TarifPpnNew, tt.expectedTarif)
+			}
+			if reloader.Current().SourceDatabase.DBHost != current.SourceDatabase.DBHost {
+				t.Errorf("Current() source_database.host = %s, expected running %s", reloader.Current().SourceDatabase.DBHost, current.SourceDatabase.DBHost)
+			}
+		})
+	}
+}
+
+func TestChangedSections(t *testing.T) {
+	previous, err := LoadConfig("../config/config-sample.json")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	next := *previous
+	next.ResultCache.Size = 10
+	next.PpnConfig.TarifPpnNew = 12
+	if changed := changedSections(previous, &next, RestartSections); len(changed) != 1 || changed[0] != "result_cache" {
+		t.Errorf("changedSections() = %v, expected [result_cache]", changed)
+	}
+}
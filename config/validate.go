@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"tax-aggregator-service-demo/pkg/scheduler"
+)
+
+var drivers = map[string]bool{"mysql": true, "postgres": true, "sqlite": true}
+
+// validator collect every invalid key instead of stopping at the first one.
+type validator struct {
+	errs    []error
+	secrets bool
+}
+
+func (v *validator) failf(key, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("config: %s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) required(key, value string) {
+	if value == "" {
+		v.failf(key, "is required")
+	}
+}
+
+func (v *validator) nonNegative(key string, value int64) {
+	if value < 0 {
+		v.failf(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) percentage(key string, value int64) {
+	if value < 0 || value > 100 {
+		v.failf(key, "must be a percentage between 0 and 100, got %d", value)
+	}
+}
+
+// Validate check required keys and value ranges, the error list every offending key.
+func (c *Config) Validate() error {
+	v := &validator{secrets: c.SecretManager.Provider != ""}
+	c.SourceDatabase.validate(v, "source_database", true)
+	c.ServiceDatabase.validate(v, "service_database", false)
+	c.SecretManager.validate(v)
+	c.PpnConfig.validate(v)
+
+	if c.Aggregator.Enabled {
+		if _, err := scheduler.ParseSchedule(c.Aggregator.Schedule); err != nil {
+			v.failf("aggregator.schedule", "%v", err)
+		}
+	}
+	v.nonNegative("aggregator.close_delay_seconds", c.Aggregator.CloseDelaySeconds)
+	v.nonNegative("aggregator.start_date", c.Aggregator.StartDate)
+
+	v.nonNegative("timeout.request_ms", c.Timeout.RequestMs)
+	v.nonNegative("timeout.source_query_ms", c.Timeout.SourceQueryMs)
+	v.nonNegative("timeout.service_query_ms", c.Timeout.ServiceQueryMs)
+	v.nonNegative("timeout.health_check_ms", c.Timeout.HealthCheckMs)
+	v.nonNegative("timeout.shutdown_ms", c.Timeout.ShutdownMs)
+
+	v.nonNegative("source_query.concurrency", int64(c.SourceQuery.Concurrency))
+	v.nonNegative("source_query.chunk_concurrency", int64(c.SourceQuery.ChunkConcurrency))
+	for table, days := range c.SourceQuery.ChunkDays {
+		v.nonNegative("source_query.chunk_days."+table, int64(days))
+	}
+
+	v.nonNegative("result_cache.size", int64(c.ResultCache.Size))
+	v.nonNegative("result_cache.ttl_seconds", c.ResultCache.TTLSeconds)
+
+	v.nonNegative("resilience.retry_attempts", int64(c.Resilience.RetryAttempts))
+	v.nonNegative("resilience.retry_base_delay_ms", c.Resilience.RetryBaseDelayMs)
+	v.nonNegative("resilience.retry_max_delay_ms", c.Resilience.RetryMaxDelayMs)
+	if c.Resilience.RetryMaxDelayMs > 0 && c.Resilience.RetryMaxDelayMs < c.Resilience.RetryBaseDelayMs {
+		v.failf("resilience.retry_max_delay_ms", "must not be less than retry_base_delay_ms (%d), got %d", c.Resilience.RetryBaseDelayMs, c.Resilience.RetryMaxDelayMs)
+	}
+	v.nonNegative("resilience.breaker_failure_threshold", int64(c.Resilience.BreakerFailureThreshold))
+	v.nonNegative("resilience.breaker_open_timeout_ms", c.Resilience.BreakerOpenTimeoutMs)
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		v.failf("tracing.exporter", "must be one of none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	v.required("tracing.service_name", c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.failf("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	if c.Logging.Format != "json" && c.Logging.Format != "text" {
+		v.failf("logging.format", "must be json or text, got %q", c.Logging.Format)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		v.failf("logging.level", "must be one of debug, info, warn or error, got %q", c.Logging.Level)
+	}
+
+	if c.RateLimit.RequestsPerSecond < 0 {
+		v.failf("rate_limit.requests_per_second", "must not be negative, got %v", c.RateLimit.RequestsPerSecond)
+	}
+	v.nonNegative("rate_limit.burst", int64(c.RateLimit.Burst))
+	v.nonNegative("rate_limit.source_fetch_concurrency", int64(c.RateLimit.SourceFetchConcurrency))
+	v.nonNegative("rate_limit.source_fetch_queue_size", int64(c.RateLimit.SourceFetchQueueSize))
+	v.nonNegative("rate_limit.source_fetch_queue_timeout_ms", c.RateLimit.SourceFetchQueueTimeoutMs)
+	for _, trustedProxy := range c.RateLimit.TrustedProxies {
+		if _, _, err := net.ParseCIDR(trustedProxy); err != nil {
+			v.failf("rate_limit.trusted_proxies", "must be CIDRs, got %q", trustedProxy)
+		}
+	}
+
+	if c.Admin.Enabled {
+		if _, _, err := net.SplitHostPort(c.Admin.Address); err != nil {
+			v.failf("admin.address", "must be host:port, got %q", c.Admin.Address)
+		}
+	}
+
+	if c.Auth.Enabled {
+		switch c.Auth.Algorithm {
+		case "":
+			if len(c.Auth.APIKeys) == 0 {
+				v.failf("auth.algorithm", "is required when auth.api_keys is empty")
+			}
+		case "HS256":
+			v.required("auth.hmac_secret", c.Auth.HMACSecret)
+			if strings.HasPrefix(c.Auth.HMACSecret, SecretReferencePrefix) && !v.secrets {
+				v.failf("auth.hmac_secret", "reference %s but secret_manager.provider is not set", c.Auth.HMACSecret)
+			}
+		case "RS256":
+			if c.Auth.PublicKeyFile == "" && c.Auth.JWKSFile == "" {
+				v.failf("auth.public_key_file", "public_key_file or jwks_file is required for RS256")
+			}
+		default:
+			v.failf("auth.algorithm", "must be HS256 or RS256, got %q", c.Auth.Algorithm)
+		}
+		v.required("auth.roles_claim", c.Auth.RolesClaim)
+		v.nonNegative("auth.signature_window_seconds", c.Auth.SignatureWindowSeconds)
+		keyIDs := map[string]bool{}
+		for i, apiKey := range c.Auth.APIKeys {
+			key := fmt.Sprintf("auth.api_keys[%d]", i)
+			v.required(key+".id", apiKey.ID)
+			if keyIDs[apiKey.ID] {
+				v.failf(key+".id", "is duplicated, got %q", apiKey.ID)
+			}
+			keyIDs[apiKey.ID] = true
+			v.required(key+".client", apiKey.Client)
+			v.required(key+".secret", apiKey.Secret)
+			if strings.HasPrefix(apiKey.Secret, SecretReferencePrefix) && !v.secrets {
+				v.failf(key+".secret", "reference %s but secret_manager.provider is not set", apiKey.Secret)
+			}
+			if len(apiKey.Roles) == 0 {
+				v.failf(key+".roles", "at least one role is required")
+			}
+		}
+	}
+	return errors.Join(v.errs...)
+}
+
+func (d *Database) validate(v *validator, key string, allowReplicas bool) {
+	if !drivers[d.Driver] {
+		v.failf(key+".driver", "must be one of mysql, postgres or sqlite, got %q", d.Driver)
+	}
+	v.required(key+".database_name", d.DBName)
+	if d.Driver != "sqlite" {
+		v.required(key+".username", d.DBUsername)
+		if strings.HasPrefix(d.DBPassword, SecretReferencePrefix) && !v.secrets {
+			v.failf(key+".password", "reference %s but secret_manager.provider is not set", d.DBPassword)
+		}
+		v.required(key+".host", d.DBHost)
+		if port, err := strconv.Atoi(d.DBPort); err != nil || port < 1 || port > 65535 {
+			v.failf(key+".port", "must be between 1 and 65535, got %q", d.DBPort)
+		}
+	}
+	v.nonNegative(key+".max_replica_lag_seconds", d.MaxReplicaLagSeconds)
+	v.nonNegative(key+".replica_check_interval_seconds", d.ReplicaCheckIntervalSeconds)
+	if len(d.Replicas) > 0 && (!allowReplicas || d.Driver == "sqlite") {
+		v.failf(key+".replicas", "are not supported on %s %s", d.Driver, key)
+	}
+	for i := range d.Replicas {
+		replica := d.Replicas[i]
+		if replica.Driver == "" {
+			replica.Driver = d.Driver
+		}
+		replica.validate(v, fmt.Sprintf("%s.replicas[%d]", key, i), false)
+	}
+}
+
+func (s *SecretManager) validate(v *validator) {
+	switch s.Provider {
+	case "", "env":
+	case "file":
+		v.required("secret_manager.dir", s.Dir)
+	case "vault":
+		v.required("secret_manager.vault_address", s.VaultAddress)
+		v.required("secret_manager.vault_token", s.VaultToken)
+		v.required("secret_manager.vault_mount", s.VaultMount)
+	default:
+		v.failf("secret_manager.provider", "must be one of file, env or vault, got %q", s.Provider)
+	}
+	v.nonNegative("secret_manager.refresh_seconds", s.RefreshSeconds)
+}
+
+func (p *PpnConfig) validate(v *validator) {
+	v.percentage("ppn_config.tarif_ppn", p.TarifPpn)
+	v.percentage("ppn_config.tarif_ppn_new", p.TarifPpnNew)
+	if p.TimeStartPpn <= 0 {
+		v.failf("ppn_config.time_start_ppn", "is required")
+	}
+	if p.TimeStartPpnNew <= p.TimeStartPpn {
+		v.failf("ppn_config.time_start_ppn_new", "must be after time_start_ppn (%d), got %d", p.TimeStartPpn, p.TimeStartPpnNew)
+	}
+}
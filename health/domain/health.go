@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"tax-aggregator-service-demo/health/entity"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dependency check status
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// names of the readiness checks, a check per source replica is named SourceReplicaCheck + replica name
+const (
+	SourceDatabaseCheck  = "source_database"
+	SourceReplicaCheck   = "source_replica:"
+	ServiceDatabaseCheck = "service_database"
+	ServiceSchemaCheck   = "service_schema"
+)
+
+// ServiceTables are the tables created by the service schema, readiness fail until every one of them exist.
+var ServiceTables = []string{"tax_transaction", "backfill_checkpoint", "aggregation_watermark", "job_run", "audit_log"}
+
+// health configuration from service config, timeout bound every dependency check
+type HealthConfig struct {
+	Timeout           time.Duration
+	AggregatorEnabled bool
+	CloseDelay        time.Duration
+}
+
+// health handler interface
+type HealthHandler interface {
+	Routes(route *echo.Echo)
+	Liveness(ctx echo.Context) error
+	Readiness(ctx echo.Context) error
+}
+
+// health usecase interface contract for readiness of the service dependencies
+type HealthUsecase interface {
+	Readiness(ctx context.Context) *entity.Readiness
+}
+
+// health repository interface contract for pinging the source and service databases
+type HealthRepository interface {
+	PingSource(ctx context.Context) error
+	PingSourceReplicas(ctx context.Context) map[string]error
+	PingService(ctx context.Context) error
+	GetMissingTables(ctx context.Context, tables []string) ([]string, error)
+}
@@ -0,0 +1,25 @@
+package entity
+
+// DependencyCheck is the result of checking a single dependency, an optional dependency being down doesn't fail readiness.
+type DependencyCheck struct {
+	Status        string   `json:"status"`
+	Required      bool     `json:"required"`
+	LatencyMs     int64    `json:"latency_ms"`
+	Error         string   `json:"error,omitempty"`
+	MissingTables []string `json:"missing_tables,omitempty"`
+}
+
+// AggregationLag is the distance between the last closed day and the daily aggregation watermark.
+type AggregationLag struct {
+	Enabled       bool   `json:"enabled"`
+	Watermark     int64  `json:"watermark"`
+	LastClosedDay int64  `json:"last_closed_day"`
+	LagSeconds    int64  `json:"lag_seconds"`
+	Error         string `json:"error,omitempty"`
+}
+
+type Readiness struct {
+	Status      string                     `json:"status"`
+	Checks      map[string]DependencyCheck `json:"checks"`
+	Aggregation AggregationLag             `json:"aggregation"`
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"tax-aggregator-service-demo/health/domain"
+
+	taxDomain "tax-aggregator-service-demo/tax/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+type healthHandler struct {
+	healthUsecase domain.HealthUsecase
+}
+
+func NewHealthHandler(healthUsecase domain.HealthUsecase) domain.HealthHandler {
+	return &healthHandler{
+		healthUsecase: healthUsecase,
+	}
+}
+
+func (hh *healthHandler) Routes(echo *echo.Echo) {
+	echo.GET("/healthz", hh.Liveness)
+	echo.GET("/readyz", hh.Readiness)
+}
+
+// Liveness only tell the process is serving requests, dependencies are checked by Readiness
+// so a database outage doesn't restart every replica.
+func (hh *healthHandler) Liveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, &taxDomain.Response{
+		Code:    http.StatusOK,
+		Message: "alive",
+	})
+}
+
+// Readiness return 503 when a required dependency is down, with the detail of every check.
+func (hh *healthHandler) Readiness(ctx echo.Context) error {
+	readiness := hh.healthUsecase.Readiness(ctx.Request().Context())
+	if readiness.Status != domain.HealthStatusUp {
+		return ctx.JSON(http.StatusServiceUnavailable, &taxDomain.Response{
+			Code:    http.StatusServiceUnavailable,
+			Message: "not ready",
+			Data:    readiness,
+		})
+	}
+	return ctx.JSON(http.StatusOK, &taxDomain.Response{
+		Code:    http.StatusOK,
+		Message: "ready",
+		Data:    readiness,
+	})
+}
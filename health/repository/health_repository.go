@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"tax-aggregator-service-demo/health/domain"
+	"tax-aggregator-service-demo/pkg/dbconn"
+)
+
+type healthRepository struct {
+	sourceRouter *dbconn.Router
+	serviceConn  *sql.DB
+}
+
+func NewHealthRepository(sourceRouter *dbconn.Router, serviceConn *sql.DB) domain.HealthRepository {
+	return &healthRepository{
+		sourceRouter: sourceRouter,
+		serviceConn:  serviceConn,
+	}
+}
+
+func (hr *healthRepository) PingSource(ctx context.Context) error {
+	return hr.sourceRouter.Primary().PingContext(ctx)
+}
+
+// PingSourceReplicas ping every source replica, the error is nil for a replica answering the ping.
+func (hr *healthRepository) PingSourceReplicas(ctx context.Context) map[string]error {
+	replicas := map[string]error{}
+	for _, replica := range hr.sourceRouter.Replicas() {
+		replicas[replica.Name] = replica.DB.PingContext(ctx)
+	}
+	return replicas
+}
+
+func (hr *healthRepository) PingService(ctx context.Context) error {
+	return hr.serviceConn.PingContext(ctx)
+}
+
+// check table existence query from service database, valid on every service database driver.
+const checkTable = `SELECT 1 FROM %s WHERE 1 = 0`
+
+// GetMissingTables return the tables which can't be queried on service database, eg: the schema was not applied yet.
+func (hr *healthRepository) GetMissingTables(ctx context.Context, tables []string) ([]string, error) {
+	missingTables := []string{}
+	for _, table := range tables {
+		r, err := hr.serviceConn.QueryContext(ctx, fmt.Sprintf(checkTable, table))
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			missingTables = append(missingTables, table)
+			continue
+		}
+		r.Close()
+	}
+	return missingTables, nil
+}
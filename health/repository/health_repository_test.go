@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRepository_GetMissingTables(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceMock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(checkTable, "tax_transaction"))).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+	serviceMock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(checkTable, "audit_log"))).
+		WillReturnError(errors.New(`relation "audit_log" does not exist`))
+	healthRepository := NewHealthRepository(nil, serviceConn)
+	missingTables, err := healthRepository.GetMissingTables(context.Background(), []string{"tax_transaction", "audit_log"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"audit_log"}, missingTables)
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
+
+func TestHealthRepository_Ping(t *testing.T) {
+	sourceConn, sourceMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	serviceConn, serviceMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	sourceMock.ExpectPing()
+	serviceMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	healthRepository := NewHealthRepository(dbconn.NewRouter(sourceConn, nil), serviceConn)
+	assert.NoError(t, healthRepository.PingSource(context.Background()))
+	assert.Error(t, healthRepository.PingService(context.Background()))
+	assert.Empty(t, healthRepository.PingSourceReplicas(context.Background()))
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"tax-aggregator-service-demo/health/domain"
+	"tax-aggregator-service-demo/health/entity"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/tax"
+	"time"
+
+	taxDomain "tax-aggregator-service-demo/tax/domain"
+)
+
+// default timeout of a single dependency check
+const defaultCheckTimeout = 2 * time.Second
+
+type healthUsecase struct {
+	healthRepository     domain.HealthRepository
+	aggregatorRepository taxDomain.AggregatorRepository
+	healthConfig         *domain.HealthConfig
+	now                  func() time.Time
+}
+
+func NewHealthUsecase(healthRepository domain.HealthRepository, aggregatorRepository taxDomain.AggregatorRepository, healthConfig *domain.HealthConfig) domain.HealthUsecase {
+	return &healthUsecase{
+		healthRepository:     healthRepository,
+		aggregatorRepository: aggregatorRepository,
+		healthConfig:         healthConfig,
+		now:                  time.Now,
+	}
+}
+
+// Readiness check every dependency in parallel, each bounded by the check timeout. the service is ready when the source primary,
+// the service database and the service schema are up, source replicas are optional since queries fail over to the primary.
+// the aggregation lag is only reported, a lagging aggregation is caught up by GetTax reading the source database.
+func (hu *healthUsecase) Readiness(ctx context.Context) *entity.Readiness {
+	timeout := hu.healthConfig.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	readiness := &entity.Readiness{
+		Status: domain.HealthStatusUp,
+		Checks: map[string]entity.DependencyCheck{},
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	record := func(name string, check entity.DependencyCheck) {
+		mu.Lock()
+		defer mu.Unlock()
+		readiness.Checks[name] = check
+		if check.Required && check.Status != domain.HealthStatusUp {
+			readiness.Status = domain.HealthStatusDown
+		}
+	}
+	run := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	run(func() {
+		start := hu.now()
+		err := hu.healthRepository.PingSource(ctx)
+		record(domain.SourceDatabaseCheck, newDependencyCheck(true, hu.now().Sub(start), err))
+	})
+	run(func() {
+		start := hu.now()
+		replicas := hu.healthRepository.PingSourceReplicas(ctx)
+		latency := hu.now().Sub(start)
+		for name, err := range replicas {
+			record(domain.SourceReplicaCheck+name, newDependencyCheck(false, latency, err))
+		}
+	})
+	run(func() {
+		start := hu.now()
+		err := hu.healthRepository.PingService(ctx)
+		record(domain.ServiceDatabaseCheck, newDependencyCheck(true, hu.now().Sub(start), err))
+		if err != nil {
+			record(domain.ServiceSchemaCheck, newDependencyCheck(true, 0, err))
+			return
+		}
+		start = hu.now()
+		missingTables, err := hu.healthRepository.GetMissingTables(ctx, domain.ServiceTables)
+		schemaCheck := newDependencyCheck(true, hu.now().Sub(start), err)
+		if len(missingTables) > 0 {
+			schemaCheck.Status = domain.HealthStatusDown
+			schemaCheck.Error = "service schema is not applied"
+			schemaCheck.MissingTables = missingTables
+		}
+		record(domain.ServiceSchemaCheck, schemaCheck)
+	})
+	run(func() {
+		readiness.Aggregation = hu.aggregationLag(ctx)
+	})
+	wg.Wait()
+	if readiness.Status != domain.HealthStatusUp {
+		slog.WarnContext(ctx, "[HealthUsecase.Readiness]:: service is not ready.", slog.Any("checks", readiness.Checks))
+	}
+	return readiness
+}
+
+// aggregationLag return the lag of the daily aggregation watermark behind the last closed day,
+// watermark and lag are left at 0 when the job never ran.
+func (hu *healthUsecase) aggregationLag(ctx context.Context) entity.AggregationLag {
+	aggregationLag := entity.AggregationLag{Enabled: hu.healthConfig.AggregatorEnabled}
+	if !aggregationLag.Enabled {
+		return aggregationLag
+	}
+	aggregationLag.LastClosedDay = tax.LastClosedDay(hu.now(), hu.healthConfig.CloseDelay)
+	watermark, err := hu.aggregatorRepository.GetAggregationWatermark(ctx, taxDomain.DailyAggregationWatermark)
+	if err != nil {
+		slog.WarnContext(ctx, "[HealthUsecase.aggregationLag]:: error getting aggregation watermark.", logger.Err(err))
+		aggregationLag.Error = err.Error()
+		return aggregationLag
+	}
+	if watermark != nil {
+		aggregationLag.Watermark = watermark.Watermark
+		aggregationLag.LagSeconds = aggregationLag.LastClosedDay - watermark.Watermark
+	}
+	return aggregationLag
+}
+
+func newDependencyCheck(required bool, latency time.Duration, err error) entity.DependencyCheck {
+	check := entity.DependencyCheck{
+		Status:    domain.HealthStatusUp,
+		Required:  required,
+		LatencyMs: latency.Milliseconds(),
+	}
+	if err != nil {
+		check.Status = domain.HealthStatusDown
+		check.Error = err.Error()
+	}
+	return check
+}
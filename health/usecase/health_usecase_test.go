@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/health/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/health/domain"
+	taxMocks "tax-aggregator-service-demo/mocks/tax/domain"
+	taxDomain "tax-aggregator-service-demo/tax/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthUsecase_Readiness(t *testing.T) {
+	now := func() time.Time { return time.Unix(1683658800, 0) } // 2023-05-10 02:00 Asia/Jakarta, last closed day 2023-05-09
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test ready when every required dependency is up and report aggregation lag",
+			testFunction: func(t *testing.T) {
+				healthRepository := mocks.NewHealthRepository(t)
+				aggregatorRepository := taxMocks.NewAggregatorRepository(t)
+				healthRepository.EXPECT().PingSource(mock.Anything).Return(nil)
+				healthRepository.EXPECT().PingSourceReplicas(mock.Anything).Return(map[string]error{"replica:3306": errors.New("connection refused")})
+				healthRepository.EXPECT().PingService(mock.Anything).Return(nil)
+				healthRepository.EXPECT().GetMissingTables(mock.Anything, domain.ServiceTables).Return([]string{}, nil)
+				aggregatorRepository.EXPECT().GetAggregationWatermark(mock.Anything, taxDomain.DailyAggregationWatermark).
+					Return(&entity.AggregationWatermark{Watermark: 1683417600}, nil)
+
+				healthUsecase := &healthUsecase{
+					healthRepository:     healthRepository,
+					aggregatorRepository: aggregatorRepository,
+					healthConfig:         &domain.HealthConfig{AggregatorEnabled: true},
+					now:                  now,
+				}
+				readiness := healthUsecase.Readiness(context.Background())
+				assert.Equal(t, domain.HealthStatusUp, readiness.Status)
+				assert.Equal(t, domain.HealthStatusDown, readiness.Checks["source_replica:replica:3306"].Status)
+				assert.Equal(t, "connection refused", readiness.Checks["source_replica:replica:3306"].Error)
+				assert.Equal(t, int64(1683590400), readiness.Aggregation.LastClosedDay)
+				assert.Equal(t, int64(2*86400), readiness.Aggregation.LagSeconds)
+			},
+		},
+		{
+			name: "test not ready when source database is down",
+			testFunction: func(t *testing.T) {
+				healthRepository := mocks.NewHealthRepository(t)
+				healthRepository.EXPECT().PingSource(mock.Anything).Return(errors.New("connection refused"))
+				healthRepository.EXPECT().PingSourceReplicas(mock.Anything).Return(map[string]error{})
+				healthRepository.EXPECT().PingService(mock.Anything).Return(nil)
+				healthRepository.EXPECT().GetMissingTables(mock.Anything, domain.ServiceTables).Return([]string{}, nil)
+
+				readiness := NewHealthUsecase(healthRepository, nil, &domain.HealthConfig{}).Readiness(context.Background())
+				assert.Equal(t, domain.HealthStatusDown, readiness.Status)
+				assert.Equal(t, domain.HealthStatusDown, readiness.Checks[domain.SourceDatabaseCheck].Status)
+				assert.Equal(t, domain.HealthStatusUp, readiness.Checks[domain.ServiceSchemaCheck].Status)
+				assert.False(t, readiness.Aggregation.Enabled)
+			},
+		},
+		{
+			name: "test not ready when service schema is not applied",
+			testFunction: func(t *testing.T) {
+				healthRepository := mocks.NewHealthRepository(t)
+				healthRepository.EXPECT().PingSource(mock.Anything).Return(nil)
+				healthRepository.EXPECT().PingSourceReplicas(mock.Anything).Return(map[string]error{})
+				healthRepository.EXPECT().PingService(mock.Anything).Return(nil)
+				healthRepository.EXPECT().GetMissingTables(mock.Anything, domain.ServiceTables).Return([]string{"audit_log"}, nil)
+
+				readiness := NewHealthUsecase(healthRepository, nil, &domain.HealthConfig{}).Readiness(context.Background())
+				assert.Equal(t, domain.HealthStatusDown, readiness.Status)
+				assert.Equal(t, []string{"audit_log"}, readiness.Checks[domain.ServiceSchemaCheck].MissingTables)
+			},
+		},
+		{
+			name: "test not ready without checking schema when service database is down",
+			testFunction: func(t *testing.T) {
+				healthRepository := mocks.NewHealthRepository(t)
+				healthRepository.EXPECT().PingSource(mock.Anything).Return(nil)
+				healthRepository.EXPECT().PingSourceReplicas(mock.Anything).Return(map[string]error{})
+				healthRepository.EXPECT().PingService(mock.Anything).Return(errors.New("connection refused"))
+
+				readiness := NewHealthUsecase(healthRepository, nil, &domain.HealthConfig{}).Readiness(context.Background())
+				assert.Equal(t, domain.HealthStatusDown, readiness.Status)
+				assert.Equal(t, domain.HealthStatusDown, readiness.Checks[domain.ServiceDatabaseCheck].Status)
+				assert.Equal(t, domain.HealthStatusDown, readiness.Checks[domain.ServiceSchemaCheck].Status)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
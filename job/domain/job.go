@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/job/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// job run status stored in service database
+const (
+	JobStatusRunning = "running"
+	JobStatusSuccess = "success"
+	JobStatusFailed  = "failed"
+)
+
+// ErrNoLocker is returned when registering a job without a distributed lock, the job would run on every replica.
+var ErrNoLocker = errors.New("no distributed lock to elect a single replica")
+
+// periodic job registered into scheduler, schedule is a cron expression or "@every <duration>"
+type Job struct {
+	Name     string
+	Schedule string
+	Run      func(ctx context.Context) error
+}
+
+// job handler interface
+type JobHandler interface {
+	Routes(route *echo.Echo)
+	GetJobRuns(ctx echo.Context) error
+}
+
+// job scheduler interface contract, every job is run by a single elected replica
+type JobScheduler interface {
+	Register(job Job) error
+	Start()
+	Stop(ctx context.Context) error
+	GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error)
+}
+
+// job repository interface contract for job run history in service database
+type JobRepository interface {
+	InsertJobRun(ctx context.Context, jobRun *entity.JobRun) (int64, error)
+	UpdateJobRun(ctx context.Context, jobRun *entity.JobRun) error
+	GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error)
+}
@@ -0,0 +1,13 @@
+package entity
+
+// Value Objects and Entities that will be mapped into service_database
+
+type JobRun struct {
+	ID         int64  `json:"id"`
+	JobName    string `json:"job_name"`
+	Instance   string `json:"instance"`
+	StartedAt  int64  `json:"started_at"`
+	FinishedAt int64  `json:"finished_at"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+}
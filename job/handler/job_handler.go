@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"tax-aggregator-service-demo/audit"
+	"tax-aggregator-service-demo/job/domain"
+	"tax-aggregator-service-demo/pkg/auth"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+
+	auditDomain "tax-aggregator-service-demo/audit/domain"
+	auditEntity "tax-aggregator-service-demo/audit/entity"
+	taxDomain "tax-aggregator-service-demo/tax/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+type jobHandler struct {
+	jobScheduler  domain.JobScheduler
+	auditUsecase  auditDomain.AuditUsecase
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
+}
+
+func NewJobHandler(jobScheduler domain.JobScheduler, auditUsecase auditDomain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) domain.JobHandler {
+	return &jobHandler{
+		jobScheduler:  jobScheduler,
+		auditUsecase:  auditUsecase,
+		authenticator: authenticator,
+		limiter:       limiter,
+	}
+}
+
+func (jh *jobHandler) Routes(echo *echo.Echo) {
+	echo.GET("/admin/jobs", jh.GetJobRuns, jh.authenticator.Require(auth.RoleAdmin), jh.limiter.Middleware())
+}
+
+func (jh *jobHandler) GetJobRuns(ctx echo.Context) error {
+	var (
+		jobName string
+		limit   int
+	)
+	err := echo.QueryParamsBinder(ctx).
+		String("job_name", &jobName).
+		Int("limit", &limit).
+		BindError()
+	if err != nil {
+		slog.WarnContext(ctx.Request().Context(), "[JobHandler.GetJobRuns]:: error bind query params", logger.Err(err))
+		return ctx.JSON(http.StatusBadRequest, &taxDomain.Response{
+			Code:    http.StatusBadRequest,
+			Message: "bad request",
+		})
+	}
+	auditLog := &auditEntity.AuditLog{
+		Action:       auditDomain.AuditActionGetJobRuns,
+		Actor:        audit.Actor(ctx),
+		ClaimedActor: audit.ClaimedActor(ctx),
+		Params:       audit.Params(ctx.QueryParams()),
+	}
+	jobRuns, err := jh.jobScheduler.GetJobRuns(ctx.Request().Context(), jobName, limit)
+	if err != nil {
+		auditLog.Status = auditDomain.AuditStatusFailed
+		auditLog.Error = err.Error()
+	}
+	if err := jh.auditUsecase.Record(context.WithoutCancel(ctx.Request().Context()), auditLog); err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "[JobHandler.GetJobRuns]:: error recording audit log.", logger.Err(err))
+	}
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, &taxDomain.Response{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+	}
+	return ctx.JSON(http.StatusOK, &taxDomain.Response{
+		Code:    http.StatusOK,
+		Message: "success get job runs",
+		Data:    jobRuns,
+	})
+}
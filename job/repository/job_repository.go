@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"tax-aggregator-service-demo/job/domain"
+	"tax-aggregator-service-demo/job/entity"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
+)
+
+type jobRepository struct {
+	serviceConn *sql.DB
+	dialect     dbconn.Dialect
+}
+
+func NewJobRepository(serviceConn *sql.DB) domain.JobRepository {
+	return &jobRepository{
+		serviceConn: serviceConn,
+		dialect:     dbconn.DialectOf(serviceConn),
+	}
+}
+
+// insert job run query from service database, the id is read with RETURNING id or LastInsertId on mysql.
+const insertJobRun = `
+	INSERT INTO
+		job_run(job_name, instance, started_at, status)
+	VALUES
+		($1, $2, $3, $4)
+`
+
+func (jr *jobRepository) InsertJobRun(ctx context.Context, jobRun *entity.JobRun) (int64, error) {
+	serviceConn := jr.serviceConn
+	var id int64
+	query, args := jr.dialect.Rebind(insertJobRun,
+		jobRun.JobName,
+		jobRun.Instance,
+		jobRun.StartedAt,
+		jobRun.Status,
+	)
+	if !jr.dialect.Returning() {
+		res, err := serviceConn.ExecContext(ctx, query, args...)
+		if err == nil {
+			id, err = res.LastInsertId()
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "[JobRepository.InsertJobRun]:: error insert job_run into service database.", logger.Err(err))
+			return 0, err
+		}
+		return id, nil
+	}
+	if err := serviceConn.QueryRowContext(ctx, query+"\tRETURNING id\n", args...).Scan(&id); err != nil {
+		slog.ErrorContext(ctx, "[JobRepository.InsertJobRun]:: error insert job_run into service database.", logger.Err(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+// update job run query from service database.
+const updateJobRun = `
+	UPDATE
+		job_run
+	SET
+		finished_at = $1,
+		status = $2,
+		error = $3
+	WHERE
+		id = $4
+`
+
+func (jr *jobRepository) UpdateJobRun(ctx context.Context, jobRun *entity.JobRun) error {
+	serviceConn := jr.serviceConn
+	query, args := jr.dialect.Rebind(updateJobRun,
+		jobRun.FinishedAt,
+		jobRun.Status,
+		jobRun.Error,
+		jobRun.ID,
+	)
+	if _, err := serviceConn.ExecContext(ctx, query, args...); err != nil {
+		slog.ErrorContext(ctx, "[JobRepository.UpdateJobRun]:: error update job_run in service database.", logger.Err(err))
+		return err
+	}
+	return nil
+}
+
+// get job runs query from service database, empty job name return every job.
+const getJobRuns = `
+	SELECT
+		j.id,
+		j.job_name,
+		j.instance,
+		j.started_at,
+		COALESCE(j.finished_at, 0),
+		j.status,
+		COALESCE(j.error, '')
+	FROM
+		job_run AS j
+	WHERE
+		$1 = '' OR j.job_name = $1
+	ORDER BY
+		j.started_at
+	DESC
+	LIMIT $2
+`
+
+func (jr *jobRepository) GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error) {
+	serviceConn := jr.serviceConn
+	jobRuns := []entity.JobRun{}
+	query, args := jr.dialect.Rebind(getJobRuns, jobName, limit)
+	r, err := serviceConn.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, "[JobRepository.GetJobRuns]:: error getting job_run from service database.", logger.Err(err))
+		return nil, err
+	}
+	defer r.Close()
+	jobRun := &entity.JobRun{}
+	for r.Next() {
+		if err := r.Scan(
+			&jobRun.ID,
+			&jobRun.JobName,
+			&jobRun.Instance,
+			&jobRun.StartedAt,
+			&jobRun.FinishedAt,
+			&jobRun.Status,
+			&jobRun.Error,
+		); err != nil {
+			slog.ErrorContext(ctx, "[JobRepository.GetJobRuns]:: error scanning job_run from service database.", logger.Err(err))
+			return nil, err
+		}
+		jobRuns = append(jobRuns, *jobRun)
+	}
+	return jobRuns, r.Err()
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/job/entity"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository_InsertJobRun(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceMock.ExpectQuery(regexp.QuoteMeta(insertJobRun)).
+		WithArgs("daily_aggregation", "replica-1", int64(1683658800), "running").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	jobRepository := NewJobRepository(serviceConn)
+	id, err := jobRepository.InsertJobRun(context.Background(), &entity.JobRun{
+		JobName:   "daily_aggregation",
+		Instance:  "replica-1",
+		StartedAt: 1683658800,
+		Status:    "running",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
+
+func TestJobRepository_UpdateJobRun(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceMock.ExpectExec(regexp.QuoteMeta(updateJobRun)).
+		WithArgs(int64(1683658860), "failed", "source database down", int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	jobRepository := NewJobRepository(serviceConn)
+	assert.NoError(t, jobRepository.UpdateJobRun(context.Background(), &entity.JobRun{
+		ID:         7,
+		FinishedAt: 1683658860,
+		Status:     "failed",
+		Error:      "source database down",
+	}))
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
+
+func TestJobRepository_GetJobRuns(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	rows := sqlmock.NewRows([]string{"id", "job_name", "instance", "started_at", "finished_at", "status", "error"})
+	rows.AddRow(8, "daily_aggregation", "replica-1", 1683659400, 0, "running", "")
+	rows.AddRow(7, "daily_aggregation", "replica-2", 1683658800, 1683658860, "success", "")
+	serviceMock.ExpectQuery(regexp.QuoteMeta(getJobRuns)).WithArgs("daily_aggregation", 20).WillReturnRows(rows)
+	jobRepository := NewJobRepository(serviceConn)
+	jobRuns, err := jobRepository.GetJobRuns(context.Background(), "daily_aggregation", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.JobRun{
+		{ID: 8, JobName: "daily_aggregation", Instance: "replica-1", StartedAt: 1683659400, Status: "running"},
+		{ID: 7, JobName: "daily_aggregation", Instance: "replica-2", StartedAt: 1683658800, FinishedAt: 1683658860, Status: "success"},
+	}, jobRuns)
+}
+
+func TestJobRepository_SQLite(t *testing.T) {
+	serviceConn, err := dbconn.NewSQLiteDBConn(&config.Database{DBName: filepath.Join(t.TempDir(), "service.db")})
+	assert.NoError(t, err)
+	defer serviceConn.Close()
+	schema, err := os.ReadFile("../../schema_sqlite.sql")
+	assert.NoError(t, err)
+	_, err = serviceConn.Exec(string(schema))
+	assert.NoError(t, err)
+	jobRepository := NewJobRepository(serviceConn)
+	ctx := context.Background()
+	id, err := jobRepository.InsertJobRun(ctx, &entity.JobRun{JobName: "daily_aggregation", Instance: "replica-1", StartedAt: 1683658800, Status: "running"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+	assert.NoError(t, jobRepository.UpdateJobRun(ctx, &entity.JobRun{ID: id, FinishedAt: 1683658860, Status: "success"}))
+	jobRuns, err := jobRepository.GetJobRuns(ctx, "daily_aggregation", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.JobRun{
+		{ID: 1, JobName: "daily_aggregation", Instance: "replica-1", StartedAt: 1683658800, FinishedAt: 1683658860, Status: "success"},
+	}, jobRuns)
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"tax-aggregator-service-demo/job/domain"
+	"tax-aggregator-service-demo/job/entity"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/scheduler"
+	"time"
+)
+
+const (
+	defaultJobRunsLimit = 50
+	maxJobRunsLimit     = 500
+)
+
+type scheduledJob struct {
+	job      domain.Job
+	schedule scheduler.Schedule
+}
+
+type jobScheduler struct {
+	jobRepository domain.JobRepository
+	locker        scheduler.Locker
+	instance      string
+	jobs          []scheduledJob
+	ctx           context.Context
+	cancel        context.CancelFunc
+	stop          chan struct{}
+	stopOnce      sync.Once
+	wg            sync.WaitGroup
+	now           func() time.Time
+}
+
+// NewJobScheduler create in-process scheduler, instance is recorded on job run history to show which replica was the leader.
+// locker is nil when service database has no distributed lock, jobs can't be registered then.
+func NewJobScheduler(jobRepository domain.JobRepository, locker scheduler.Locker, instance string) domain.JobScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobScheduler{
+		jobRepository: jobRepository,
+		locker:        locker,
+		instance:      instance,
+		ctx:           ctx,
+		cancel:        cancel,
+		stop:          make(chan struct{}),
+		now:           time.Now,
+	}
+}
+
+func (js *jobScheduler) Register(job domain.Job) error {
+	if js.locker == nil {
+		return fmt.Errorf("job %s: %w", job.Name, domain.ErrNoLocker)
+	}
+	schedule, err := scheduler.ParseSchedule(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	js.jobs = append(js.jobs, scheduledJob{
+		job:      job,
+		schedule: schedule,
+	})
+	return nil
+}
+
+func (js *jobScheduler) Start() {
+	for _, scheduledJob := range js.jobs {
+		js.wg.Add(1)
+		go js.loop(scheduledJob)
+	}
+}
+
+// Stop stop scheduling new runs and wait for the running jobs to finish, the jobs still running when ctx is done
+// are cancelled. the leadership is released afterwards so other replica can take over, it can be called more than once.
+func (js *jobScheduler) Stop(ctx context.Context) error {
+	js.stopOnce.Do(func() { close(js.stop) })
+	done := make(chan struct{})
+	go func() {
+		js.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "[JobScheduler.Stop]:: cancelling jobs still running after the shutdown deadline.")
+		err = ctx.Err()
+	}
+	js.cancel()
+	<-done
+	for _, scheduledJob := range js.jobs {
+		if err := js.locker.Unlock(lockName(scheduledJob.job.Name)); err != nil {
+			slog.Error("[JobScheduler.Stop]:: error releasing lock of job.", slog.String("job", scheduledJob.job.Name), logger.Err(err))
+		}
+	}
+	return err
+}
+
+func (js *jobScheduler) GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error) {
+	if limit <= 0 {
+		limit = defaultJobRunsLimit
+	}
+	if limit > maxJobRunsLimit {
+		limit = maxJobRunsLimit
+	}
+	return js.jobRepository.GetJobRuns(ctx, jobName, limit)
+}
+
+func (js *jobScheduler) loop(scheduledJob scheduledJob) {
+	defer js.wg.Done()
+	for {
+		next := scheduledJob.schedule.Next(js.now())
+		if next.IsZero() {
+			slog.Warn("[JobScheduler.loop]:: job will never be scheduled.", slog.String("job", scheduledJob.job.Name))
+			return
+		}
+		timer := time.NewTimer(next.Sub(js.now()))
+		select {
+		case <-js.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		js.run(js.ctx, scheduledJob.job)
+	}
+}
+
+// run execute the job only when this replica hold the job's lock, the run is recorded into job run history.
+func (js *jobScheduler) run(ctx context.Context, job domain.Job) {
+	leader, err := js.locker.TryLock(lockName(job.Name))
+	if err != nil {
+		slog.ErrorContext(ctx, "[JobScheduler.run]:: error electing leader of job.", slog.String("job", job.Name), logger.Err(err))
+		return
+	}
+	if !leader {
+		return
+	}
+	jobRun := &entity.JobRun{
+		JobName:   job.Name,
+		Instance:  js.instance,
+		StartedAt: js.now().Unix(),
+		Status:    domain.JobStatusRunning,
+	}
+	if jobRun.ID, err = js.jobRepository.InsertJobRun(ctx, jobRun); err != nil {
+		slog.ErrorContext(ctx, "[JobScheduler.run]:: error recording start of job.", slog.String("job", job.Name), logger.Err(err))
+	}
+
+	err = runJob(ctx, job)
+	jobRun.FinishedAt = js.now().Unix()
+	jobRun.Status = domain.JobStatusSuccess
+	if err != nil {
+		slog.ErrorContext(ctx, "[JobScheduler.run]:: job failed.", slog.String("job", job.Name), logger.Err(err))
+		jobRun.Status = domain.JobStatusFailed
+		jobRun.Error = err.Error()
+	}
+	if jobRun.ID == 0 {
+		return
+	}
+	if err := js.jobRepository.UpdateJobRun(context.WithoutCancel(ctx), jobRun); err != nil {
+		slog.ErrorContext(ctx, "[JobScheduler.run]:: error recording end of job.", slog.String("job", job.Name), logger.Err(err))
+	}
+}
+
+func runJob(ctx context.Context, job domain.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func lockName(jobName string) string {
+	return "job:" + jobName
+}
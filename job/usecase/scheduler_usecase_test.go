@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/job/domain"
+	"tax-aggregator-service-demo/job/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/job/domain"
+	schedulerMocks "tax-aggregator-service-demo/mocks/pkg/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJobScheduler_Run(t *testing.T) {
+	now := func() time.Time { return time.Unix(1683658800, 0) }
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test run job on leader and record success",
+			testFunction: func(t *testing.T) {
+				jobRepository := mocks.NewJobRepository(t)
+				locker := schedulerMocks.NewLocker(t)
+				locker.EXPECT().TryLock("job:daily_aggregation").Return(true, nil)
+				jobRepository.EXPECT().InsertJobRun(mock.Anything, mock.MatchedBy(func(jobRun *entity.JobRun) bool {
+					return jobRun.Status == domain.JobStatusRunning && jobRun.Instance == "replica-1"
+				})).Return(int64(1), nil).Once()
+				jobRepository.EXPECT().UpdateJobRun(mock.Anything, mock.MatchedBy(func(jobRun *entity.JobRun) bool {
+					return jobRun.ID == 1 && jobRun.Status == domain.JobStatusSuccess && jobRun.FinishedAt == 1683658800
+				})).Return(nil).Once()
+
+				ran := 0
+				jobScheduler := &jobScheduler{
+					jobRepository: jobRepository,
+					locker:        locker,
+					instance:      "replica-1",
+					now:           now,
+				}
+				jobScheduler.run(context.Background(), domain.Job{
+					Name: "daily_aggregation",
+					Run: func(ctx context.Context) error {
+						ran++
+						return nil
+					},
+				})
+				assert.Equal(t, 1, ran)
+			},
+		},
+		{
+			name: "test skip job when other replica is the leader",
+			testFunction: func(t *testing.T) {
+				jobRepository := mocks.NewJobRepository(t)
+				locker := schedulerMocks.NewLocker(t)
+				locker.EXPECT().TryLock("job:daily_aggregation").Return(false, nil)
+
+				jobScheduler := &jobScheduler{
+					jobRepository: jobRepository,
+					locker:        locker,
+					instance:      "replica-2",
+					now:           now,
+				}
+				jobScheduler.run(context.Background(), domain.Job{
+					Name: "daily_aggregation",
+					Run: func(ctx context.Context) error {
+						t.Fatal("job must not run on follower")
+						return nil
+					},
+				})
+			},
+		},
+		{
+			name: "test record failed job with panic",
+			testFunction: func(t *testing.T) {
+				jobRepository := mocks.NewJobRepository(t)
+				locker := schedulerMocks.NewLocker(t)
+				locker.EXPECT().TryLock("job:daily_aggregation").Return(true, nil)
+				jobRepository.EXPECT().InsertJobRun(mock.Anything, mock.Anything).Return(int64(2), nil).Once()
+				jobRepository.EXPECT().UpdateJobRun(mock.Anything, mock.MatchedBy(func(jobRun *entity.JobRun) bool {
+					return jobRun.ID == 2 && jobRun.Status == domain.JobStatusFailed && jobRun.Error == "panic: source database down"
+				})).Return(nil).Once()
+
+				jobScheduler := &jobScheduler{
+					jobRepository: jobRepository,
+					locker:        locker,
+					instance:      "replica-1",
+					now:           now,
+				}
+				jobScheduler.run(context.Background(), domain.Job{
+					Name: "daily_aggregation",
+					Run: func(ctx context.Context) error {
+						panic("source database down")
+					},
+				})
+			},
+		},
+		{
+			name: "test skip job when leader election failed",
+			testFunction: func(t *testing.T) {
+				jobRepository := mocks.NewJobRepository(t)
+				locker := schedulerMocks.NewLocker(t)
+				locker.EXPECT().TryLock("job:daily_aggregation").Return(false, errors.New("connection refused"))
+
+				jobScheduler := &jobScheduler{
+					jobRepository: jobRepository,
+					locker:        locker,
+					instance:      "replica-1",
+					now:           now,
+				}
+				jobScheduler.run(context.Background(), domain.Job{
+					Name: "daily_aggregation",
+					Run: func(ctx context.Context) error {
+						t.Fatal("job must not run without leadership")
+						return nil
+					},
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
+
+func TestJobScheduler_Register(t *testing.T) {
+	jobScheduler := NewJobScheduler(mocks.NewJobRepository(t), schedulerMocks.NewLocker(t), "replica-1")
+	assert.NoError(t, jobScheduler.Register(domain.Job{Name: "daily_aggregation", Schedule: "*/10 * * * *"}))
+	assert.Error(t, jobScheduler.Register(domain.Job{Name: "report_mailing", Schedule: "every day"}))
+
+	jobScheduler = NewJobScheduler(mocks.NewJobRepository(t), nil, "replica-1")
+	assert.ErrorIs(t, jobScheduler.Register(domain.Job{Name: "daily_aggregation", Schedule: "*/10 * * * *"}), domain.ErrNoLocker)
+}
+
+func TestJobScheduler_GetJobRuns(t *testing.T) {
+	jobRepository := mocks.NewJobRepository(t)
+	jobRepository.EXPECT().GetJobRuns(mock.Anything, "", defaultJobRunsLimit).Return([]entity.JobRun{}, nil).Once()
+	jobRepository.EXPECT().GetJobRuns(mock.Anything, "daily_aggregation", maxJobRunsLimit).Return([]entity.JobRun{}, nil).Once()
+	jobScheduler := NewJobScheduler(jobRepository, schedulerMocks.NewLocker(t), "replica-1")
+	_, err := jobScheduler.GetJobRuns(context.Background(), "", 0)
+	assert.NoError(t, err)
+	_, err = jobScheduler.GetJobRuns(context.Background(), "daily_aggregation", 10000)
+	assert.NoError(t, err)
+}
+
+func TestJobScheduler_Stop(t *testing.T) {
+	// runningJob start job on js as if it was scheduled, started is closed once the job is running
+	runningJob := func(js *jobScheduler, run func(ctx context.Context) error) (started chan struct{}) {
+		started = make(chan struct{})
+		job := domain.Job{Name: "daily_aggregation", Schedule: "*/10 * * * *", Run: func(ctx context.Context) error {
+			close(started)
+			return run(ctx)
+		}}
+		assert.NoError(t, js.Register(job))
+		js.wg.Add(1)
+		go func() {
+			defer js.wg.Done()
+			js.run(js.ctx, job)
+		}()
+		return started
+	}
+	newJobScheduler := func(expectedStatus string) *jobScheduler {
+		jobRepository := mocks.NewJobRepository(t)
+		jobRepository.EXPECT().InsertJobRun(mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+		jobRepository.EXPECT().UpdateJobRun(mock.Anything, mock.MatchedBy(func(jobRun *entity.JobRun) bool {
+			return jobRun.Status == expectedStatus
+		})).Return(nil).Once()
+		locker := schedulerMocks.NewLocker(t)
+		locker.EXPECT().TryLock("job:daily_aggregation").Return(true, nil).Once()
+		locker.EXPECT().Unlock("job:daily_aggregation").Return(nil).Once()
+		return NewJobScheduler(jobRepository, locker, "replica-1").(*jobScheduler)
+	}
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test stop wait for running job to finish",
+			testFunction: func(t *testing.T) {
+				jobScheduler := newJobScheduler(domain.JobStatusSuccess)
+				finish := make(chan struct{})
+				started := runningJob(jobScheduler, func(ctx context.Context) error {
+					<-finish
+					return ctx.Err()
+				})
+				<-started
+				time.AfterFunc(10*time.Millisecond, func() { close(finish) })
+				assert.NoError(t, jobScheduler.Stop(context.Background()))
+			},
+		},
+		{
+			name: "test stop cancel running job after deadline",
+			testFunction: func(t *testing.T) {
+				jobScheduler := newJobScheduler(domain.JobStatusFailed)
+				started := runningJob(jobScheduler, func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+				<-started
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				assert.ErrorIs(t, jobScheduler.Stop(ctx), context.DeadlineExceeded)
+			},
+		},
+		{
+			name: "test stop called twice doesn't panic",
+			testFunction: func(t *testing.T) {
+				jobScheduler := newJobScheduler(domain.JobStatusSuccess)
+				started := runningJob(jobScheduler, func(ctx context.Context) error { return nil })
+				<-started
+				jobScheduler.locker.(*schedulerMocks.Locker).EXPECT().Unlock("job:daily_aggregation").Return(nil).Once()
+				assert.NoError(t, jobScheduler.Stop(context.Background()))
+				assert.NotPanics(t, func() { assert.NoError(t, jobScheduler.Stop(context.Background())) })
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditHandler is an autogenerated mock type for the AuditHandler type
+type AuditHandler struct {
+	mock.Mock
+}
+
+type AuditHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditHandler) EXPECT() *AuditHandler_Expecter {
+	return &AuditHandler_Expecter{mock: &_m.Mock}
+}
+
+// GetAuditLogs provides a mock function with given fields: ctx
+func (_m *AuditHandler) GetAuditLogs(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuditHandler_GetAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditLogs'
+type AuditHandler_GetAuditLogs_Call struct {
+	*mock.Call
+}
+
+// GetAuditLogs is a helper method to define mock.On call
+//   - ctx echo.Context
+func (_e *AuditHandler_Expecter) GetAuditLogs(ctx interface{}) *AuditHandler_GetAuditLogs_Call {
+	return &AuditHandler_GetAuditLogs_Call{Call: _e.mock.On("GetAuditLogs", ctx)}
+}
+
+func (_c *AuditHandler_GetAuditLogs_Call) Run(run func(ctx echo.Context)) *AuditHandler_GetAuditLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(echo.Context))
+	})
+	return _c
+}
+
+func (_c *AuditHandler_GetAuditLogs_Call) Return(_a0 error) *AuditHandler_GetAuditLogs_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuditHandler_GetAuditLogs_Call) RunAndReturn(run func(echo.Context) error) *AuditHandler_GetAuditLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Routes provides a mock function with given fields: route
+func (_m *AuditHandler) Routes(route *echo.Echo) {
+	_m.Called(route)
+}
+
+// AuditHandler_Routes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Routes'
+type AuditHandler_Routes_Call struct {
+	*mock.Call
+}
+
+// Routes is a helper method to define mock.On call
+//   - route *echo.Echo
+func (_e *AuditHandler_Expecter) Routes(route interface{}) *AuditHandler_Routes_Call {
+	return &AuditHandler_Routes_Call{Call: _e.mock.On("Routes", route)}
+}
+
+func (_c *AuditHandler_Routes_Call) Run(run func(route *echo.Echo)) *AuditHandler_Routes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*echo.Echo))
+	})
+	return _c
+}
+
+func (_c *AuditHandler_Routes_Call) Return() *AuditHandler_Routes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *AuditHandler_Routes_Call) RunAndReturn(run func(*echo.Echo)) *AuditHandler_Routes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditHandler creates a new instance of AuditHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditHandler {
+	mock := &AuditHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
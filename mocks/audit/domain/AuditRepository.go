@@ -0,0 +1,136 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "tax-aggregator-service-demo/audit/domain"
+	entity "tax-aggregator-service-demo/audit/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+type AuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditRepository) EXPECT() *AuditRepository_Expecter {
+	return &AuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetAuditLogs provides a mock function with given fields: ctx, auditFilter
+func (_m *AuditRepository) GetAuditLogs(ctx context.Context, auditFilter *domain.AuditFilter) ([]entity.AuditLog, error) {
+	ret := _m.Called(ctx, auditFilter)
+
+	var r0 []entity.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter) ([]entity.AuditLog, error)); ok {
+		return rf(ctx, auditFilter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter) []entity.AuditLog); ok {
+		r0 = rf(ctx, auditFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuditFilter) error); ok {
+		r1 = rf(ctx, auditFilter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditRepository_GetAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditLogs'
+type AuditRepository_GetAuditLogs_Call struct {
+	*mock.Call
+}
+
+// GetAuditLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - auditFilter *domain.AuditFilter
+func (_e *AuditRepository_Expecter) GetAuditLogs(ctx interface{}, auditFilter interface{}) *AuditRepository_GetAuditLogs_Call {
+	return &AuditRepository_GetAuditLogs_Call{Call: _e.mock.On("GetAuditLogs", ctx, auditFilter)}
+}
+
+func (_c *AuditRepository_GetAuditLogs_Call) Run(run func(ctx context.Context, auditFilter *domain.AuditFilter)) *AuditRepository_GetAuditLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_GetAuditLogs_Call) Return(_a0 []entity.AuditLog, _a1 error) *AuditRepository_GetAuditLogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditRepository_GetAuditLogs_Call) RunAndReturn(run func(context.Context, *domain.AuditFilter) ([]entity.AuditLog, error)) *AuditRepository_GetAuditLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertAuditLog provides a mock function with given fields: ctx, auditLog
+func (_m *AuditRepository) InsertAuditLog(ctx context.Context, auditLog *entity.AuditLog) error {
+	ret := _m.Called(ctx, auditLog)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AuditLog) error); ok {
+		r0 = rf(ctx, auditLog)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuditRepository_InsertAuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertAuditLog'
+type AuditRepository_InsertAuditLog_Call struct {
+	*mock.Call
+}
+
+// InsertAuditLog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - auditLog *entity.AuditLog
+func (_e *AuditRepository_Expecter) InsertAuditLog(ctx interface{}, auditLog interface{}) *AuditRepository_InsertAuditLog_Call {
+	return &AuditRepository_InsertAuditLog_Call{Call: _e.mock.On("InsertAuditLog", ctx, auditLog)}
+}
+
+func (_c *AuditRepository_InsertAuditLog_Call) Run(run func(ctx context.Context, auditLog *entity.AuditLog)) *AuditRepository_InsertAuditLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.AuditLog))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_InsertAuditLog_Call) Return(_a0 error) *AuditRepository_InsertAuditLog_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuditRepository_InsertAuditLog_Call) RunAndReturn(run func(context.Context, *entity.AuditLog) error) *AuditRepository_InsertAuditLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
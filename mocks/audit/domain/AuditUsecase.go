@@ -0,0 +1,169 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "tax-aggregator-service-demo/audit/domain"
+	entity "tax-aggregator-service-demo/audit/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditUsecase is an autogenerated mock type for the AuditUsecase type
+type AuditUsecase struct {
+	mock.Mock
+}
+
+type AuditUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditUsecase) EXPECT() *AuditUsecase_Expecter {
+	return &AuditUsecase_Expecter{mock: &_m.Mock}
+}
+
+// GetAuditLogs provides a mock function with given fields: ctx, auditFilter
+func (_m *AuditUsecase) GetAuditLogs(ctx context.Context, auditFilter *domain.AuditFilter) ([]entity.AuditLog, error) {
+	ret := _m.Called(ctx, auditFilter)
+
+	var r0 []entity.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter) ([]entity.AuditLog, error)); ok {
+		return rf(ctx, auditFilter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditFilter) []entity.AuditLog); ok {
+		r0 = rf(ctx, auditFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.AuditFilter) error); ok {
+		r1 = rf(ctx, auditFilter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditUsecase_GetAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditLogs'
+type AuditUsecase_GetAuditLogs_Call struct {
+	*mock.Call
+}
+
+// GetAuditLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - auditFilter *domain.AuditFilter
+func (_e *AuditUsecase_Expecter) GetAuditLogs(ctx interface{}, auditFilter interface{}) *AuditUsecase_GetAuditLogs_Call {
+	return &AuditUsecase_GetAuditLogs_Call{Call: _e.mock.On("GetAuditLogs", ctx, auditFilter)}
+}
+
+func (_c *AuditUsecase_GetAuditLogs_Call) Run(run func(ctx context.Context, auditFilter *domain.AuditFilter)) *AuditUsecase_GetAuditLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *AuditUsecase_GetAuditLogs_Call) Return(_a0 []entity.AuditLog, _a1 error) *AuditUsecase_GetAuditLogs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditUsecase_GetAuditLogs_Call) RunAndReturn(run func(context.Context, *domain.AuditFilter) ([]entity.AuditLog, error)) *AuditUsecase_GetAuditLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function with given fields: ctx, auditLog
+func (_m *AuditUsecase) Record(ctx context.Context, auditLog *entity.AuditLog) error {
+	ret := _m.Called(ctx, auditLog)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AuditLog) error); ok {
+		r0 = rf(ctx, auditLog)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuditUsecase_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type AuditUsecase_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - auditLog *entity.AuditLog
+func (_e *AuditUsecase_Expecter) Record(ctx interface{}, auditLog interface{}) *AuditUsecase_Record_Call {
+	return &AuditUsecase_Record_Call{Call: _e.mock.On("Record", ctx, auditLog)}
+}
+
+func (_c *AuditUsecase_Record_Call) Run(run func(ctx context.Context, auditLog *entity.AuditLog)) *AuditUsecase_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.AuditLog))
+	})
+	return _c
+}
+
+func (_c *AuditUsecase_Record_Call) Return(_a0 error) *AuditUsecase_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuditUsecase_Record_Call) RunAndReturn(run func(context.Context, *entity.AuditLog) error) *AuditUsecase_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetConfigHash provides a mock function with given fields: configHash
+func (_m *AuditUsecase) SetConfigHash(configHash string) {
+	_m.Called(configHash)
+}
+
+// AuditUsecase_SetConfigHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetConfigHash'
+type AuditUsecase_SetConfigHash_Call struct {
+	*mock.Call
+}
+
+// SetConfigHash is a helper method to define mock.On call
+//   - configHash string
+func (_e *AuditUsecase_Expecter) SetConfigHash(configHash interface{}) *AuditUsecase_SetConfigHash_Call {
+	return &AuditUsecase_SetConfigHash_Call{Call: _e.mock.On("SetConfigHash", configHash)}
+}
+
+func (_c *AuditUsecase_SetConfigHash_Call) Run(run func(configHash string)) *AuditUsecase_SetConfigHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *AuditUsecase_SetConfigHash_Call) Return() *AuditUsecase_SetConfigHash_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *AuditUsecase_SetConfigHash_Call) RunAndReturn(run func(string)) *AuditUsecase_SetConfigHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditUsecase creates a new instance of AuditUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditUsecase {
+	mock := &AuditUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
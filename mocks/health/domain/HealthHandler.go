@@ -0,0 +1,152 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthHandler is an autogenerated mock type for the HealthHandler type
+type HealthHandler struct {
+	mock.Mock
+}
+
+type HealthHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HealthHandler) EXPECT() *HealthHandler_Expecter {
+	return &HealthHandler_Expecter{mock: &_m.Mock}
+}
+
+// Liveness provides a mock function with given fields: ctx
+func (_m *HealthHandler) Liveness(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HealthHandler_Liveness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Liveness'
+type HealthHandler_Liveness_Call struct {
+	*mock.Call
+}
+
+// Liveness is a helper method to define mock.On call
+//   - ctx echo.Context
+func (_e *HealthHandler_Expecter) Liveness(ctx interface{}) *HealthHandler_Liveness_Call {
+	return &HealthHandler_Liveness_Call{Call: _e.mock.On("Liveness", ctx)}
+}
+
+func (_c *HealthHandler_Liveness_Call) Run(run func(ctx echo.Context)) *HealthHandler_Liveness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(echo.Context))
+	})
+	return _c
+}
+
+func (_c *HealthHandler_Liveness_Call) Return(_a0 error) *HealthHandler_Liveness_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthHandler_Liveness_Call) RunAndReturn(run func(echo.Context) error) *HealthHandler_Liveness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Readiness provides a mock function with given fields: ctx
+func (_m *HealthHandler) Readiness(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HealthHandler_Readiness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Readiness'
+type HealthHandler_Readiness_Call struct {
+	*mock.Call
+}
+
+// Readiness is a helper method to define mock.On call
+//   - ctx echo.Context
+func (_e *HealthHandler_Expecter) Readiness(ctx interface{}) *HealthHandler_Readiness_Call {
+	return &HealthHandler_Readiness_Call{Call: _e.mock.On("Readiness", ctx)}
+}
+
+func (_c *HealthHandler_Readiness_Call) Run(run func(ctx echo.Context)) *HealthHandler_Readiness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(echo.Context))
+	})
+	return _c
+}
+
+func (_c *HealthHandler_Readiness_Call) Return(_a0 error) *HealthHandler_Readiness_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthHandler_Readiness_Call) RunAndReturn(run func(echo.Context) error) *HealthHandler_Readiness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Routes provides a mock function with given fields: route
+func (_m *HealthHandler) Routes(route *echo.Echo) {
+	_m.Called(route)
+}
+
+// HealthHandler_Routes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Routes'
+type HealthHandler_Routes_Call struct {
+	*mock.Call
+}
+
+// Routes is a helper method to define mock.On call
+//   - route *echo.Echo
+func (_e *HealthHandler_Expecter) Routes(route interface{}) *HealthHandler_Routes_Call {
+	return &HealthHandler_Routes_Call{Call: _e.mock.On("Routes", route)}
+}
+
+func (_c *HealthHandler_Routes_Call) Run(run func(route *echo.Echo)) *HealthHandler_Routes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*echo.Echo))
+	})
+	return _c
+}
+
+func (_c *HealthHandler_Routes_Call) Return() *HealthHandler_Routes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *HealthHandler_Routes_Call) RunAndReturn(run func(*echo.Echo)) *HealthHandler_Routes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewHealthHandler creates a new instance of HealthHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthHandler {
+	mock := &HealthHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
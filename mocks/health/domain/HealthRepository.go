@@ -0,0 +1,219 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthRepository is an autogenerated mock type for the HealthRepository type
+type HealthRepository struct {
+	mock.Mock
+}
+
+type HealthRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HealthRepository) EXPECT() *HealthRepository_Expecter {
+	return &HealthRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetMissingTables provides a mock function with given fields: ctx, tables
+func (_m *HealthRepository) GetMissingTables(ctx context.Context, tables []string) ([]string, error) {
+	ret := _m.Called(ctx, tables)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]string, error)); ok {
+		return rf(ctx, tables)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
+		r0 = rf(ctx, tables)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, tables)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HealthRepository_GetMissingTables_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMissingTables'
+type HealthRepository_GetMissingTables_Call struct {
+	*mock.Call
+}
+
+// GetMissingTables is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tables []string
+func (_e *HealthRepository_Expecter) GetMissingTables(ctx interface{}, tables interface{}) *HealthRepository_GetMissingTables_Call {
+	return &HealthRepository_GetMissingTables_Call{Call: _e.mock.On("GetMissingTables", ctx, tables)}
+}
+
+func (_c *HealthRepository_GetMissingTables_Call) Run(run func(ctx context.Context, tables []string)) *HealthRepository_GetMissingTables_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *HealthRepository_GetMissingTables_Call) Return(_a0 []string, _a1 error) *HealthRepository_GetMissingTables_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *HealthRepository_GetMissingTables_Call) RunAndReturn(run func(context.Context, []string) ([]string, error)) *HealthRepository_GetMissingTables_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PingService provides a mock function with given fields: ctx
+func (_m *HealthRepository) PingService(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HealthRepository_PingService_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PingService'
+type HealthRepository_PingService_Call struct {
+	*mock.Call
+}
+
+// PingService is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthRepository_Expecter) PingService(ctx interface{}) *HealthRepository_PingService_Call {
+	return &HealthRepository_PingService_Call{Call: _e.mock.On("PingService", ctx)}
+}
+
+func (_c *HealthRepository_PingService_Call) Run(run func(ctx context.Context)) *HealthRepository_PingService_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthRepository_PingService_Call) Return(_a0 error) *HealthRepository_PingService_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthRepository_PingService_Call) RunAndReturn(run func(context.Context) error) *HealthRepository_PingService_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PingSource provides a mock function with given fields: ctx
+func (_m *HealthRepository) PingSource(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HealthRepository_PingSource_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PingSource'
+type HealthRepository_PingSource_Call struct {
+	*mock.Call
+}
+
+// PingSource is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthRepository_Expecter) PingSource(ctx interface{}) *HealthRepository_PingSource_Call {
+	return &HealthRepository_PingSource_Call{Call: _e.mock.On("PingSource", ctx)}
+}
+
+func (_c *HealthRepository_PingSource_Call) Run(run func(ctx context.Context)) *HealthRepository_PingSource_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthRepository_PingSource_Call) Return(_a0 error) *HealthRepository_PingSource_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthRepository_PingSource_Call) RunAndReturn(run func(context.Context) error) *HealthRepository_PingSource_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PingSourceReplicas provides a mock function with given fields: ctx
+func (_m *HealthRepository) PingSourceReplicas(ctx context.Context) map[string]error {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]error
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]error); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]error)
+		}
+	}
+
+	return r0
+}
+
+// HealthRepository_PingSourceReplicas_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PingSourceReplicas'
+type HealthRepository_PingSourceReplicas_Call struct {
+	*mock.Call
+}
+
+// PingSourceReplicas is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthRepository_Expecter) PingSourceReplicas(ctx interface{}) *HealthRepository_PingSourceReplicas_Call {
+	return &HealthRepository_PingSourceReplicas_Call{Call: _e.mock.On("PingSourceReplicas", ctx)}
+}
+
+func (_c *HealthRepository_PingSourceReplicas_Call) Run(run func(ctx context.Context)) *HealthRepository_PingSourceReplicas_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthRepository_PingSourceReplicas_Call) Return(_a0 map[string]error) *HealthRepository_PingSourceReplicas_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthRepository_PingSourceReplicas_Call) RunAndReturn(run func(context.Context) map[string]error) *HealthRepository_PingSourceReplicas_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewHealthRepository creates a new instance of HealthRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthRepository {
+	mock := &HealthRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "tax-aggregator-service-demo/health/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// HealthUsecase is an autogenerated mock type for the HealthUsecase type
+type HealthUsecase struct {
+	mock.Mock
+}
+
+type HealthUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *HealthUsecase) EXPECT() *HealthUsecase_Expecter {
+	return &HealthUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Readiness provides a mock function with given fields: ctx
+func (_m *HealthUsecase) Readiness(ctx context.Context) *entity.Readiness {
+	ret := _m.Called(ctx)
+
+	var r0 *entity.Readiness
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.Readiness); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Readiness)
+		}
+	}
+
+	return r0
+}
+
+// HealthUsecase_Readiness_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Readiness'
+type HealthUsecase_Readiness_Call struct {
+	*mock.Call
+}
+
+// Readiness is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *HealthUsecase_Expecter) Readiness(ctx interface{}) *HealthUsecase_Readiness_Call {
+	return &HealthUsecase_Readiness_Call{Call: _e.mock.On("Readiness", ctx)}
+}
+
+func (_c *HealthUsecase_Readiness_Call) Run(run func(ctx context.Context)) *HealthUsecase_Readiness_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *HealthUsecase_Readiness_Call) Return(_a0 *entity.Readiness) *HealthUsecase_Readiness_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *HealthUsecase_Readiness_Call) RunAndReturn(run func(context.Context) *entity.Readiness) *HealthUsecase_Readiness_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewHealthUsecase creates a new instance of HealthUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHealthUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HealthUsecase {
+	mock := &HealthUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
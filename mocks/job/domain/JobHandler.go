@@ -0,0 +1,110 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobHandler is an autogenerated mock type for the JobHandler type
+type JobHandler struct {
+	mock.Mock
+}
+
+type JobHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *JobHandler) EXPECT() *JobHandler_Expecter {
+	return &JobHandler_Expecter{mock: &_m.Mock}
+}
+
+// GetJobRuns provides a mock function with given fields: ctx
+func (_m *JobHandler) GetJobRuns(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// JobHandler_GetJobRuns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobRuns'
+type JobHandler_GetJobRuns_Call struct {
+	*mock.Call
+}
+
+// GetJobRuns is a helper method to define mock.On call
+//   - ctx echo.Context
+func (_e *JobHandler_Expecter) GetJobRuns(ctx interface{}) *JobHandler_GetJobRuns_Call {
+	return &JobHandler_GetJobRuns_Call{Call: _e.mock.On("GetJobRuns", ctx)}
+}
+
+func (_c *JobHandler_GetJobRuns_Call) Run(run func(ctx echo.Context)) *JobHandler_GetJobRuns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(echo.Context))
+	})
+	return _c
+}
+
+func (_c *JobHandler_GetJobRuns_Call) Return(_a0 error) *JobHandler_GetJobRuns_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *JobHandler_GetJobRuns_Call) RunAndReturn(run func(echo.Context) error) *JobHandler_GetJobRuns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Routes provides a mock function with given fields: route
+func (_m *JobHandler) Routes(route *echo.Echo) {
+	_m.Called(route)
+}
+
+// JobHandler_Routes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Routes'
+type JobHandler_Routes_Call struct {
+	*mock.Call
+}
+
+// Routes is a helper method to define mock.On call
+//   - route *echo.Echo
+func (_e *JobHandler_Expecter) Routes(route interface{}) *JobHandler_Routes_Call {
+	return &JobHandler_Routes_Call{Call: _e.mock.On("Routes", route)}
+}
+
+func (_c *JobHandler_Routes_Call) Run(run func(route *echo.Echo)) *JobHandler_Routes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*echo.Echo))
+	})
+	return _c
+}
+
+func (_c *JobHandler_Routes_Call) Return() *JobHandler_Routes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *JobHandler_Routes_Call) RunAndReturn(run func(*echo.Echo)) *JobHandler_Routes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewJobHandler creates a new instance of JobHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobHandler {
+	mock := &JobHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,190 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "tax-aggregator-service-demo/job/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobRepository is an autogenerated mock type for the JobRepository type
+type JobRepository struct {
+	mock.Mock
+}
+
+type JobRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *JobRepository) EXPECT() *JobRepository_Expecter {
+	return &JobRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetJobRuns provides a mock function with given fields: ctx, jobName, limit
+func (_m *JobRepository) GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error) {
+	ret := _m.Called(ctx, jobName, limit)
+
+	var r0 []entity.JobRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.JobRun, error)); ok {
+		return rf(ctx, jobName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.JobRun); ok {
+		r0 = rf(ctx, jobName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.JobRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, jobName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JobRepository_GetJobRuns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobRuns'
+type JobRepository_GetJobRuns_Call struct {
+	*mock.Call
+}
+
+// GetJobRuns is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobName string
+//   - limit int
+func (_e *JobRepository_Expecter) GetJobRuns(ctx interface{}, jobName interface{}, limit interface{}) *JobRepository_GetJobRuns_Call {
+	return &JobRepository_GetJobRuns_Call{Call: _e.mock.On("GetJobRuns", ctx, jobName, limit)}
+}
+
+func (_c *JobRepository_GetJobRuns_Call) Run(run func(ctx context.Context, jobName string, limit int)) *JobRepository_GetJobRuns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *JobRepository_GetJobRuns_Call) Return(_a0 []entity.JobRun, _a1 error) *JobRepository_GetJobRuns_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JobRepository_GetJobRuns_Call) RunAndReturn(run func(context.Context, string, int) ([]entity.JobRun, error)) *JobRepository_GetJobRuns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InsertJobRun provides a mock function with given fields: ctx, jobRun
+func (_m *JobRepository) InsertJobRun(ctx context.Context, jobRun *entity.JobRun) (int64, error) {
+	ret := _m.Called(ctx, jobRun)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.JobRun) (int64, error)); ok {
+		return rf(ctx, jobRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.JobRun) int64); ok {
+		r0 = rf(ctx, jobRun)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.JobRun) error); ok {
+		r1 = rf(ctx, jobRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JobRepository_InsertJobRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertJobRun'
+type JobRepository_InsertJobRun_Call struct {
+	*mock.Call
+}
+
+// InsertJobRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobRun *entity.JobRun
+func (_e *JobRepository_Expecter) InsertJobRun(ctx interface{}, jobRun interface{}) *JobRepository_InsertJobRun_Call {
+	return &JobRepository_InsertJobRun_Call{Call: _e.mock.On("InsertJobRun", ctx, jobRun)}
+}
+
+func (_c *JobRepository_InsertJobRun_Call) Run(run func(ctx context.Context, jobRun *entity.JobRun)) *JobRepository_InsertJobRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.JobRun))
+	})
+	return _c
+}
+
+func (_c *JobRepository_InsertJobRun_Call) Return(_a0 int64, _a1 error) *JobRepository_InsertJobRun_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JobRepository_InsertJobRun_Call) RunAndReturn(run func(context.Context, *entity.JobRun) (int64, error)) *JobRepository_InsertJobRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateJobRun provides a mock function with given fields: ctx, jobRun
+func (_m *JobRepository) UpdateJobRun(ctx context.Context, jobRun *entity.JobRun) error {
+	ret := _m.Called(ctx, jobRun)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.JobRun) error); ok {
+		r0 = rf(ctx, jobRun)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// JobRepository_UpdateJobRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateJobRun'
+type JobRepository_UpdateJobRun_Call struct {
+	*mock.Call
+}
+
+// UpdateJobRun is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobRun *entity.JobRun
+func (_e *JobRepository_Expecter) UpdateJobRun(ctx interface{}, jobRun interface{}) *JobRepository_UpdateJobRun_Call {
+	return &JobRepository_UpdateJobRun_Call{Call: _e.mock.On("UpdateJobRun", ctx, jobRun)}
+}
+
+func (_c *JobRepository_UpdateJobRun_Call) Run(run func(ctx context.Context, jobRun *entity.JobRun)) *JobRepository_UpdateJobRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.JobRun))
+	})
+	return _c
+}
+
+func (_c *JobRepository_UpdateJobRun_Call) Return(_a0 error) *JobRepository_UpdateJobRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *JobRepository_UpdateJobRun_Call) RunAndReturn(run func(context.Context, *entity.JobRun) error) *JobRepository_UpdateJobRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewJobRepository creates a new instance of JobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobRepository {
+	mock := &JobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
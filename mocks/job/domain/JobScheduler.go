@@ -0,0 +1,210 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "tax-aggregator-service-demo/job/domain"
+	entity "tax-aggregator-service-demo/job/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobScheduler is an autogenerated mock type for the JobScheduler type
+type JobScheduler struct {
+	mock.Mock
+}
+
+type JobScheduler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *JobScheduler) EXPECT() *JobScheduler_Expecter {
+	return &JobScheduler_Expecter{mock: &_m.Mock}
+}
+
+// GetJobRuns provides a mock function with given fields: ctx, jobName, limit
+func (_m *JobScheduler) GetJobRuns(ctx context.Context, jobName string, limit int) ([]entity.JobRun, error) {
+	ret := _m.Called(ctx, jobName, limit)
+
+	var r0 []entity.JobRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.JobRun, error)); ok {
+		return rf(ctx, jobName, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.JobRun); ok {
+		r0 = rf(ctx, jobName, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.JobRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, jobName, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JobScheduler_GetJobRuns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobRuns'
+type JobScheduler_GetJobRuns_Call struct {
+	*mock.Call
+}
+
+// GetJobRuns is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobName string
+//   - limit int
+func (_e *JobScheduler_Expecter) GetJobRuns(ctx interface{}, jobName interface{}, limit interface{}) *JobScheduler_GetJobRuns_Call {
+	return &JobScheduler_GetJobRuns_Call{Call: _e.mock.On("GetJobRuns", ctx, jobName, limit)}
+}
+
+func (_c *JobScheduler_GetJobRuns_Call) Run(run func(ctx context.Context, jobName string, limit int)) *JobScheduler_GetJobRuns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *JobScheduler_GetJobRuns_Call) Return(_a0 []entity.JobRun, _a1 error) *JobScheduler_GetJobRuns_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JobScheduler_GetJobRuns_Call) RunAndReturn(run func(context.Context, string, int) ([]entity.JobRun, error)) *JobScheduler_GetJobRuns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function with given fields: job
+func (_m *JobScheduler) Register(job domain.Job) error {
+	ret := _m.Called(job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Job) error); ok {
+		r0 = rf(job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// JobScheduler_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type JobScheduler_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - job domain.Job
+func (_e *JobScheduler_Expecter) Register(job interface{}) *JobScheduler_Register_Call {
+	return &JobScheduler_Register_Call{Call: _e.mock.On("Register", job)}
+}
+
+func (_c *JobScheduler_Register_Call) Run(run func(job domain.Job)) *JobScheduler_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(domain.Job))
+	})
+	return _c
+}
+
+func (_c *JobScheduler_Register_Call) Return(_a0 error) *JobScheduler_Register_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *JobScheduler_Register_Call) RunAndReturn(run func(domain.Job) error) *JobScheduler_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Start provides a mock function with given fields:
+func (_m *JobScheduler) Start() {
+	_m.Called()
+}
+
+// JobScheduler_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type JobScheduler_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+func (_e *JobScheduler_Expecter) Start() *JobScheduler_Start_Call {
+	return &JobScheduler_Start_Call{Call: _e.mock.On("Start")}
+}
+
+func (_c *JobScheduler_Start_Call) Run(run func()) *JobScheduler_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *JobScheduler_Start_Call) Return() *JobScheduler_Start_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *JobScheduler_Start_Call) RunAndReturn(run func()) *JobScheduler_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stop provides a mock function with given fields: ctx
+func (_m *JobScheduler) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// JobScheduler_Stop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stop'
+type JobScheduler_Stop_Call struct {
+	*mock.Call
+}
+
+// Stop is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *JobScheduler_Expecter) Stop(ctx interface{}) *JobScheduler_Stop_Call {
+	return &JobScheduler_Stop_Call{Call: _e.mock.On("Stop", ctx)}
+}
+
+func (_c *JobScheduler_Stop_Call) Run(run func(ctx context.Context)) *JobScheduler_Stop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *JobScheduler_Stop_Call) Return(_a0 error) *JobScheduler_Stop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *JobScheduler_Stop_Call) RunAndReturn(run func(context.Context) error) *JobScheduler_Stop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewJobScheduler creates a new instance of JobScheduler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobScheduler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobScheduler {
+	mock := &JobScheduler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
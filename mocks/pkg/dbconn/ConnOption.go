@@ -0,0 +1,69 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	dbconn "tax-aggregator-service-demo/pkg/dbconn"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ConnOption is an autogenerated mock type for the ConnOption type
+type ConnOption struct {
+	mock.Mock
+}
+
+type ConnOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ConnOption) EXPECT() *ConnOption_Expecter {
+	return &ConnOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *ConnOption) Execute(_a0 *dbconn.ConnOptions) {
+	_m.Called(_a0)
+}
+
+// ConnOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ConnOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *dbconn.ConnOptions
+func (_e *ConnOption_Expecter) Execute(_a0 interface{}) *ConnOption_Execute_Call {
+	return &ConnOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *ConnOption_Execute_Call) Run(run func(_a0 *dbconn.ConnOptions)) *ConnOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dbconn.ConnOptions))
+	})
+	return _c
+}
+
+func (_c *ConnOption_Execute_Call) Return() *ConnOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ConnOption_Execute_Call) RunAndReturn(run func(*dbconn.ConnOptions)) *ConnOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewConnOption creates a new instance of ConnOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConnOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConnOption {
+	mock := &ConnOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
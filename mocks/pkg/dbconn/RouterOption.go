@@ -0,0 +1,69 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	dbconn "tax-aggregator-service-demo/pkg/dbconn"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RouterOption is an autogenerated mock type for the RouterOption type
+type RouterOption struct {
+	mock.Mock
+}
+
+type RouterOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RouterOption) EXPECT() *RouterOption_Expecter {
+	return &RouterOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *RouterOption) Execute(_a0 *dbconn.RouterOptions) {
+	_m.Called(_a0)
+}
+
+// RouterOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type RouterOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *dbconn.RouterOptions
+func (_e *RouterOption_Expecter) Execute(_a0 interface{}) *RouterOption_Execute_Call {
+	return &RouterOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *RouterOption_Execute_Call) Run(run func(_a0 *dbconn.RouterOptions)) *RouterOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*dbconn.RouterOptions))
+	})
+	return _c
+}
+
+func (_c *RouterOption_Execute_Call) Return() *RouterOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RouterOption_Execute_Call) RunAndReturn(run func(*dbconn.RouterOptions)) *RouterOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRouterOption creates a new instance of RouterOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRouterOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RouterOption {
+	mock := &RouterOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	lifecycle "tax-aggregator-service-demo/pkg/lifecycle"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ManagerOption is an autogenerated mock type for the ManagerOption type
+type ManagerOption struct {
+	mock.Mock
+}
+
+type ManagerOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ManagerOption) EXPECT() *ManagerOption_Expecter {
+	return &ManagerOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *ManagerOption) Execute(_a0 *lifecycle.ManagerOptions) {
+	_m.Called(_a0)
+}
+
+// ManagerOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ManagerOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *lifecycle.ManagerOptions
+func (_e *ManagerOption_Expecter) Execute(_a0 interface{}) *ManagerOption_Execute_Call {
+	return &ManagerOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *ManagerOption_Execute_Call) Run(run func(_a0 *lifecycle.ManagerOptions)) *ManagerOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*lifecycle.ManagerOptions))
+	})
+	return _c
+}
+
+func (_c *ManagerOption_Execute_Call) Return() *ManagerOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ManagerOption_Execute_Call) RunAndReturn(run func(*lifecycle.ManagerOptions)) *ManagerOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewManagerOption creates a new instance of ManagerOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewManagerOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ManagerOption {
+	mock := &ManagerOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
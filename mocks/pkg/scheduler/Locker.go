@@ -0,0 +1,126 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Locker is an autogenerated mock type for the Locker type
+type Locker struct {
+	mock.Mock
+}
+
+type Locker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Locker) EXPECT() *Locker_Expecter {
+	return &Locker_Expecter{mock: &_m.Mock}
+}
+
+// TryLock provides a mock function with given fields: name
+func (_m *Locker) TryLock(name string) (bool, error) {
+	ret := _m.Called(name)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Locker_TryLock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TryLock'
+type Locker_TryLock_Call struct {
+	*mock.Call
+}
+
+// TryLock is a helper method to define mock.On call
+//   - name string
+func (_e *Locker_Expecter) TryLock(name interface{}) *Locker_TryLock_Call {
+	return &Locker_TryLock_Call{Call: _e.mock.On("TryLock", name)}
+}
+
+func (_c *Locker_TryLock_Call) Run(run func(name string)) *Locker_TryLock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Locker_TryLock_Call) Return(_a0 bool, _a1 error) *Locker_TryLock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Locker_TryLock_Call) RunAndReturn(run func(string) (bool, error)) *Locker_TryLock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unlock provides a mock function with given fields: name
+func (_m *Locker) Unlock(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Locker_Unlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unlock'
+type Locker_Unlock_Call struct {
+	*mock.Call
+}
+
+// Unlock is a helper method to define mock.On call
+//   - name string
+func (_e *Locker_Expecter) Unlock(name interface{}) *Locker_Unlock_Call {
+	return &Locker_Unlock_Call{Call: _e.mock.On("Unlock", name)}
+}
+
+func (_c *Locker_Unlock_Call) Run(run func(name string)) *Locker_Unlock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Locker_Unlock_Call) Return(_a0 error) *Locker_Unlock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Locker_Unlock_Call) RunAndReturn(run func(string) error) *Locker_Unlock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLocker creates a new instance of Locker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Locker {
+	mock := &Locker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Schedule is an autogenerated mock type for the Schedule type
+type Schedule struct {
+	mock.Mock
+}
+
+type Schedule_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Schedule) EXPECT() *Schedule_Expecter {
+	return &Schedule_Expecter{mock: &_m.Mock}
+}
+
+// Next provides a mock function with given fields: t
+func (_m *Schedule) Next(t time.Time) time.Time {
+	ret := _m.Called(t)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(time.Time) time.Time); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// Schedule_Next_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Next'
+type Schedule_Next_Call struct {
+	*mock.Call
+}
+
+// Next is a helper method to define mock.On call
+//   - t time.Time
+func (_e *Schedule_Expecter) Next(t interface{}) *Schedule_Next_Call {
+	return &Schedule_Next_Call{Call: _e.mock.On("Next", t)}
+}
+
+func (_c *Schedule_Next_Call) Run(run func(t time.Time)) *Schedule_Next_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Schedule_Next_Call) Return(_a0 time.Time) *Schedule_Next_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Schedule_Next_Call) RunAndReturn(run func(time.Time) time.Time) *Schedule_Next_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSchedule creates a new instance of Schedule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedule(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Schedule {
+	mock := &Schedule{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,136 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "tax-aggregator-service-demo/tax/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AggregatorRepository is an autogenerated mock type for the AggregatorRepository type
+type AggregatorRepository struct {
+	mock.Mock
+}
+
+type AggregatorRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AggregatorRepository) EXPECT() *AggregatorRepository_Expecter {
+	return &AggregatorRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetAggregationWatermark provides a mock function with given fields: ctx, name
+func (_m *AggregatorRepository) GetAggregationWatermark(ctx context.Context, name string) (*entity.AggregationWatermark, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *entity.AggregationWatermark
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.AggregationWatermark, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.AggregationWatermark); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AggregationWatermark)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AggregatorRepository_GetAggregationWatermark_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAggregationWatermark'
+type AggregatorRepository_GetAggregationWatermark_Call struct {
+	*mock.Call
+}
+
+// GetAggregationWatermark is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *AggregatorRepository_Expecter) GetAggregationWatermark(ctx interface{}, name interface{}) *AggregatorRepository_GetAggregationWatermark_Call {
+	return &AggregatorRepository_GetAggregationWatermark_Call{Call: _e.mock.On("GetAggregationWatermark", ctx, name)}
+}
+
+func (_c *AggregatorRepository_GetAggregationWatermark_Call) Run(run func(ctx context.Context, name string)) *AggregatorRepository_GetAggregationWatermark_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AggregatorRepository_GetAggregationWatermark_Call) Return(_a0 *entity.AggregationWatermark, _a1 error) *AggregatorRepository_GetAggregationWatermark_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AggregatorRepository_GetAggregationWatermark_Call) RunAndReturn(run func(context.Context, string) (*entity.AggregationWatermark, error)) *AggregatorRepository_GetAggregationWatermark_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertAggregationWatermark provides a mock function with given fields: ctx, watermark
+func (_m *AggregatorRepository) UpsertAggregationWatermark(ctx context.Context, watermark *entity.AggregationWatermark) error {
+	ret := _m.Called(ctx, watermark)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.AggregationWatermark) error); ok {
+		r0 = rf(ctx, watermark)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AggregatorRepository_UpsertAggregationWatermark_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertAggregationWatermark'
+type AggregatorRepository_UpsertAggregationWatermark_Call struct {
+	*mock.Call
+}
+
+// UpsertAggregationWatermark is a helper method to define mock.On call
+//   - ctx context.Context
+//   - watermark *entity.AggregationWatermark
+func (_e *AggregatorRepository_Expecter) UpsertAggregationWatermark(ctx interface{}, watermark interface{}) *AggregatorRepository_UpsertAggregationWatermark_Call {
+	return &AggregatorRepository_UpsertAggregationWatermark_Call{Call: _e.mock.On("UpsertAggregationWatermark", ctx, watermark)}
+}
+
+func (_c *AggregatorRepository_UpsertAggregationWatermark_Call) Run(run func(ctx context.Context, watermark *entity.AggregationWatermark)) *AggregatorRepository_UpsertAggregationWatermark_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.AggregationWatermark))
+	})
+	return _c
+}
+
+func (_c *AggregatorRepository_UpsertAggregationWatermark_Call) Return(_a0 error) *AggregatorRepository_UpsertAggregationWatermark_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AggregatorRepository_UpsertAggregationWatermark_Call) RunAndReturn(run func(context.Context, *entity.AggregationWatermark) error) *AggregatorRepository_UpsertAggregationWatermark_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAggregatorRepository creates a new instance of AggregatorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAggregatorRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AggregatorRepository {
+	mock := &AggregatorRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AggregatorUsecase is an autogenerated mock type for the AggregatorUsecase type
+type AggregatorUsecase struct {
+	mock.Mock
+}
+
+type AggregatorUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AggregatorUsecase) EXPECT() *AggregatorUsecase_Expecter {
+	return &AggregatorUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Aggregate provides a mock function with given fields: ctx
+func (_m *AggregatorUsecase) Aggregate(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AggregatorUsecase_Aggregate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Aggregate'
+type AggregatorUsecase_Aggregate_Call struct {
+	*mock.Call
+}
+
+// Aggregate is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AggregatorUsecase_Expecter) Aggregate(ctx interface{}) *AggregatorUsecase_Aggregate_Call {
+	return &AggregatorUsecase_Aggregate_Call{Call: _e.mock.On("Aggregate", ctx)}
+}
+
+func (_c *AggregatorUsecase_Aggregate_Call) Run(run func(ctx context.Context)) *AggregatorUsecase_Aggregate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AggregatorUsecase_Aggregate_Call) Return(_a0 error) *AggregatorUsecase_Aggregate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AggregatorUsecase_Aggregate_Call) RunAndReturn(run func(context.Context) error) *AggregatorUsecase_Aggregate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAggregatorUsecase creates a new instance of AggregatorUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAggregatorUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AggregatorUsecase {
+	mock := &AggregatorUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "tax-aggregator-service-demo/tax/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BackfillRepository is an autogenerated mock type for the BackfillRepository type
+type BackfillRepository struct {
+	mock.Mock
+}
+
+type BackfillRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BackfillRepository) EXPECT() *BackfillRepository_Expecter {
+	return &BackfillRepository_Expecter{mock: &_m.Mock}
+}
+
+// GetBackfillCheckpoints provides a mock function with given fields: ctx, startDate, endDate
+func (_m *BackfillRepository) GetBackfillCheckpoints(ctx context.Context, startDate int64, endDate int64) ([]entity.BackfillCheckpoint, error) {
+	ret := _m.Called(ctx, startDate, endDate)
+
+	var r0 []entity.BackfillCheckpoint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.BackfillCheckpoint, error)); ok {
+		return rf(ctx, startDate, endDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.BackfillCheckpoint); ok {
+		r0 = rf(ctx, startDate, endDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.BackfillCheckpoint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BackfillRepository_GetBackfillCheckpoints_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBackfillCheckpoints'
+type BackfillRepository_GetBackfillCheckpoints_Call struct {
+	*mock.Call
+}
+
+// GetBackfillCheckpoints is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate int64
+//   - endDate int64
+func (_e *BackfillRepository_Expecter) GetBackfillCheckpoints(ctx interface{}, startDate interface{}, endDate interface{}) *BackfillRepository_GetBackfillCheckpoints_Call {
+	return &BackfillRepository_GetBackfillCheckpoints_Call{Call: _e.mock.On("GetBackfillCheckpoints", ctx, startDate, endDate)}
+}
+
+func (_c *BackfillRepository_GetBackfillCheckpoints_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *BackfillRepository_GetBackfillCheckpoints_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *BackfillRepository_GetBackfillCheckpoints_Call) Return(_a0 []entity.BackfillCheckpoint, _a1 error) *BackfillRepository_GetBackfillCheckpoints_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BackfillRepository_GetBackfillCheckpoints_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.BackfillCheckpoint, error)) *BackfillRepository_GetBackfillCheckpoints_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertBackfillCheckpoint provides a mock function with given fields: ctx, checkpoint
+func (_m *BackfillRepository) UpsertBackfillCheckpoint(ctx context.Context, checkpoint *entity.BackfillCheckpoint) error {
+	ret := _m.Called(ctx, checkpoint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.BackfillCheckpoint) error); ok {
+		r0 = rf(ctx, checkpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackfillRepository_UpsertBackfillCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertBackfillCheckpoint'
+type BackfillRepository_UpsertBackfillCheckpoint_Call struct {
+	*mock.Call
+}
+
+// UpsertBackfillCheckpoint is a helper method to define mock.On call
+//   - ctx context.Context
+//   - checkpoint *entity.BackfillCheckpoint
+func (_e *BackfillRepository_Expecter) UpsertBackfillCheckpoint(ctx interface{}, checkpoint interface{}) *BackfillRepository_UpsertBackfillCheckpoint_Call {
+	return &BackfillRepository_UpsertBackfillCheckpoint_Call{Call: _e.mock.On("UpsertBackfillCheckpoint", ctx, checkpoint)}
+}
+
+func (_c *BackfillRepository_UpsertBackfillCheckpoint_Call) Run(run func(ctx context.Context, checkpoint *entity.BackfillCheckpoint)) *BackfillRepository_UpsertBackfillCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.BackfillCheckpoint))
+	})
+	return _c
+}
+
+func (_c *BackfillRepository_UpsertBackfillCheckpoint_Call) Return(_a0 error) *BackfillRepository_UpsertBackfillCheckpoint_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackfillRepository_UpsertBackfillCheckpoint_Call) RunAndReturn(run func(context.Context, *entity.BackfillCheckpoint) error) *BackfillRepository_UpsertBackfillCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBackfillRepository creates a new instance of BackfillRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackfillRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BackfillRepository {
+	mock := &BackfillRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
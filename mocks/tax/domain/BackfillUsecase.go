@@ -0,0 +1,80 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BackfillUsecase is an autogenerated mock type for the BackfillUsecase type
+type BackfillUsecase struct {
+	mock.Mock
+}
+
+type BackfillUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BackfillUsecase) EXPECT() *BackfillUsecase_Expecter {
+	return &BackfillUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Backfill provides a mock function with given fields: ctx, from, to
+func (_m *BackfillUsecase) Backfill(ctx context.Context, from int64, to int64) error {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackfillUsecase_Backfill_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backfill'
+type BackfillUsecase_Backfill_Call struct {
+	*mock.Call
+}
+
+// Backfill is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from int64
+//   - to int64
+func (_e *BackfillUsecase_Expecter) Backfill(ctx interface{}, from interface{}, to interface{}) *BackfillUsecase_Backfill_Call {
+	return &BackfillUsecase_Backfill_Call{Call: _e.mock.On("Backfill", ctx, from, to)}
+}
+
+func (_c *BackfillUsecase_Backfill_Call) Run(run func(ctx context.Context, from int64, to int64)) *BackfillUsecase_Backfill_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *BackfillUsecase_Backfill_Call) Return(_a0 error) *BackfillUsecase_Backfill_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackfillUsecase_Backfill_Call) RunAndReturn(run func(context.Context, int64, int64) error) *BackfillUsecase_Backfill_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBackfillUsecase creates a new instance of BackfillUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackfillUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BackfillUsecase {
+	mock := &BackfillUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
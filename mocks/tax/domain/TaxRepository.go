@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+	domain "tax-aggregator-service-demo/tax/domain"
 	entity "tax-aggregator-service-demo/tax/entity"
 
 	mock "github.com/stretchr/testify/mock"
@@ -21,25 +23,25 @@ func (_m *TaxRepository) EXPECT() *TaxRepository_Expecter {
 	return &TaxRepository_Expecter{mock: &_m.Mock}
 }
 
-// GetCounterFees provides a mock function with given fields: startDate, endDate
-func (_m *TaxRepository) GetCounterFees(startDate int64, endDate int64) ([]entity.CounterFee, error) {
-	ret := _m.Called(startDate, endDate)
+// GetCounterFees provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxRepository) GetCounterFees(ctx context.Context, startDate int64, endDate int64) ([]entity.CounterFee, error) {
+	ret := _m.Called(ctx, startDate, endDate)
 
 	var r0 []entity.CounterFee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.CounterFee, error)); ok {
-		return rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.CounterFee, error)); ok {
+		return rf(ctx, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.CounterFee); ok {
-		r0 = rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.CounterFee); ok {
+		r0 = rf(ctx, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.CounterFee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,15 +55,16 @@ type TaxRepository_GetCounterFees_Call struct {
 }
 
 // GetCounterFees is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - endDate int64
-func (_e *TaxRepository_Expecter) GetCounterFees(startDate interface{}, endDate interface{}) *TaxRepository_GetCounterFees_Call {
-	return &TaxRepository_GetCounterFees_Call{Call: _e.mock.On("GetCounterFees", startDate, endDate)}
+func (_e *TaxRepository_Expecter) GetCounterFees(ctx interface{}, startDate interface{}, endDate interface{}) *TaxRepository_GetCounterFees_Call {
+	return &TaxRepository_GetCounterFees_Call{Call: _e.mock.On("GetCounterFees", ctx, startDate, endDate)}
 }
 
-func (_c *TaxRepository_GetCounterFees_Call) Run(run func(startDate int64, endDate int64)) *TaxRepository_GetCounterFees_Call {
+func (_c *TaxRepository_GetCounterFees_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxRepository_GetCounterFees_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -71,30 +74,30 @@ func (_c *TaxRepository_GetCounterFees_Call) Return(_a0 []entity.CounterFee, _a1
 	return _c
 }
 
-func (_c *TaxRepository_GetCounterFees_Call) RunAndReturn(run func(int64, int64) ([]entity.CounterFee, error)) *TaxRepository_GetCounterFees_Call {
+func (_c *TaxRepository_GetCounterFees_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.CounterFee, error)) *TaxRepository_GetCounterFees_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetDepositRpTotalAmount provides a mock function with given fields: startDate, endDate
-func (_m *TaxRepository) GetDepositRpTotalAmount(startDate int64, endDate int64) ([]entity.DepositRpTotalAmount, error) {
-	ret := _m.Called(startDate, endDate)
+// GetDepositRpTotalAmount provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxRepository) GetDepositRpTotalAmount(ctx context.Context, startDate int64, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+	ret := _m.Called(ctx, startDate, endDate)
 
 	var r0 []entity.DepositRpTotalAmount
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.DepositRpTotalAmount, error)); ok {
-		return rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.DepositRpTotalAmount, error)); ok {
+		return rf(ctx, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.DepositRpTotalAmount); ok {
-		r0 = rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.DepositRpTotalAmount); ok {
+		r0 = rf(ctx, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.DepositRpTotalAmount)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,15 +111,16 @@ type TaxRepository_GetDepositRpTotalAmount_Call struct {
 }
 
 // GetDepositRpTotalAmount is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - endDate int64
-func (_e *TaxRepository_Expecter) GetDepositRpTotalAmount(startDate interface{}, endDate interface{}) *TaxRepository_GetDepositRpTotalAmount_Call {
-	return &TaxRepository_GetDepositRpTotalAmount_Call{Call: _e.mock.On("GetDepositRpTotalAmount", startDate, endDate)}
+func (_e *TaxRepository_Expecter) GetDepositRpTotalAmount(ctx interface{}, startDate interface{}, endDate interface{}) *TaxRepository_GetDepositRpTotalAmount_Call {
+	return &TaxRepository_GetDepositRpTotalAmount_Call{Call: _e.mock.On("GetDepositRpTotalAmount", ctx, startDate, endDate)}
 }
 
-func (_c *TaxRepository_GetDepositRpTotalAmount_Call) Run(run func(startDate int64, endDate int64)) *TaxRepository_GetDepositRpTotalAmount_Call {
+func (_c *TaxRepository_GetDepositRpTotalAmount_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxRepository_GetDepositRpTotalAmount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -126,30 +130,30 @@ func (_c *TaxRepository_GetDepositRpTotalAmount_Call) Return(_a0 []entity.Deposi
 	return _c
 }
 
-func (_c *TaxRepository_GetDepositRpTotalAmount_Call) RunAndReturn(run func(int64, int64) ([]entity.DepositRpTotalAmount, error)) *TaxRepository_GetDepositRpTotalAmount_Call {
+func (_c *TaxRepository_GetDepositRpTotalAmount_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.DepositRpTotalAmount, error)) *TaxRepository_GetDepositRpTotalAmount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFees provides a mock function with given fields: startDate, endDate
-func (_m *TaxRepository) GetFees(startDate int64, endDate int64) ([]entity.TotalFee, error) {
-	ret := _m.Called(startDate, endDate)
+// GetFees provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxRepository) GetFees(ctx context.Context, startDate int64, endDate int64) ([]entity.TotalFee, error) {
+	ret := _m.Called(ctx, startDate, endDate)
 
 	var r0 []entity.TotalFee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.TotalFee, error)); ok {
-		return rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.TotalFee, error)); ok {
+		return rf(ctx, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.TotalFee); ok {
-		r0 = rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.TotalFee); ok {
+		r0 = rf(ctx, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.TotalFee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -163,15 +167,16 @@ type TaxRepository_GetFees_Call struct {
 }
 
 // GetFees is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - endDate int64
-func (_e *TaxRepository_Expecter) GetFees(startDate interface{}, endDate interface{}) *TaxRepository_GetFees_Call {
-	return &TaxRepository_GetFees_Call{Call: _e.mock.On("GetFees", startDate, endDate)}
+func (_e *TaxRepository_Expecter) GetFees(ctx interface{}, startDate interface{}, endDate interface{}) *TaxRepository_GetFees_Call {
+	return &TaxRepository_GetFees_Call{Call: _e.mock.On("GetFees", ctx, startDate, endDate)}
 }
 
-func (_c *TaxRepository_GetFees_Call) Run(run func(startDate int64, endDate int64)) *TaxRepository_GetFees_Call {
+func (_c *TaxRepository_GetFees_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxRepository_GetFees_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -181,30 +186,30 @@ func (_c *TaxRepository_GetFees_Call) Return(_a0 []entity.TotalFee, _a1 error) *
 	return _c
 }
 
-func (_c *TaxRepository_GetFees_Call) RunAndReturn(run func(int64, int64) ([]entity.TotalFee, error)) *TaxRepository_GetFees_Call {
+func (_c *TaxRepository_GetFees_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.TotalFee, error)) *TaxRepository_GetFees_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetFeesPerDay provides a mock function with given fields: startTime, endTime
-func (_m *TaxRepository) GetFeesPerDay(startTime int64, endTime int64) (*entity.TotalFee, error) {
-	ret := _m.Called(startTime, endTime)
+// GetFeesPerDay provides a mock function with given fields: ctx, startTime, endTime
+func (_m *TaxRepository) GetFeesPerDay(ctx context.Context, startTime int64, endTime int64) (*entity.TotalFee, error) {
+	ret := _m.Called(ctx, startTime, endTime)
 
 	var r0 *entity.TotalFee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.TotalFee, error)); ok {
-		return rf(startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*entity.TotalFee, error)); ok {
+		return rf(ctx, startTime, endTime)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) *entity.TotalFee); ok {
-		r0 = rf(startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *entity.TotalFee); ok {
+		r0 = rf(ctx, startTime, endTime)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.TotalFee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startTime, endTime)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startTime, endTime)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -218,15 +223,16 @@ type TaxRepository_GetFeesPerDay_Call struct {
 }
 
 // GetFeesPerDay is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startTime int64
 //   - endTime int64
-func (_e *TaxRepository_Expecter) GetFeesPerDay(startTime interface{}, endTime interface{}) *TaxRepository_GetFeesPerDay_Call {
-	return &TaxRepository_GetFeesPerDay_Call{Call: _e.mock.On("GetFeesPerDay", startTime, endTime)}
+func (_e *TaxRepository_Expecter) GetFeesPerDay(ctx interface{}, startTime interface{}, endTime interface{}) *TaxRepository_GetFeesPerDay_Call {
+	return &TaxRepository_GetFeesPerDay_Call{Call: _e.mock.On("GetFeesPerDay", ctx, startTime, endTime)}
 }
 
-func (_c *TaxRepository_GetFeesPerDay_Call) Run(run func(startTime int64, endTime int64)) *TaxRepository_GetFeesPerDay_Call {
+func (_c *TaxRepository_GetFeesPerDay_Call) Run(run func(ctx context.Context, startTime int64, endTime int64)) *TaxRepository_GetFeesPerDay_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -236,30 +242,30 @@ func (_c *TaxRepository_GetFeesPerDay_Call) Return(_a0 *entity.TotalFee, _a1 err
 	return _c
 }
 
-func (_c *TaxRepository_GetFeesPerDay_Call) RunAndReturn(run func(int64, int64) (*entity.TotalFee, error)) *TaxRepository_GetFeesPerDay_Call {
+func (_c *TaxRepository_GetFeesPerDay_Call) RunAndReturn(run func(context.Context, int64, int64) (*entity.TotalFee, error)) *TaxRepository_GetFeesPerDay_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOldFees provides a mock function with given fields: startDate, endDate
-func (_m *TaxRepository) GetOldFees(startDate int64, endDate int64) ([]entity.TotalFee, error) {
-	ret := _m.Called(startDate, endDate)
+// GetOldFees provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxRepository) GetOldFees(ctx context.Context, startDate int64, endDate int64) ([]entity.TotalFee, error) {
+	ret := _m.Called(ctx, startDate, endDate)
 
 	var r0 []entity.TotalFee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.TotalFee, error)); ok {
-		return rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.TotalFee, error)); ok {
+		return rf(ctx, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.TotalFee); ok {
-		r0 = rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.TotalFee); ok {
+		r0 = rf(ctx, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.TotalFee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -273,15 +279,16 @@ type TaxRepository_GetOldFees_Call struct {
 }
 
 // GetOldFees is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - endDate int64
-func (_e *TaxRepository_Expecter) GetOldFees(startDate interface{}, endDate interface{}) *TaxRepository_GetOldFees_Call {
-	return &TaxRepository_GetOldFees_Call{Call: _e.mock.On("GetOldFees", startDate, endDate)}
+func (_e *TaxRepository_Expecter) GetOldFees(ctx interface{}, startDate interface{}, endDate interface{}) *TaxRepository_GetOldFees_Call {
+	return &TaxRepository_GetOldFees_Call{Call: _e.mock.On("GetOldFees", ctx, startDate, endDate)}
 }
 
-func (_c *TaxRepository_GetOldFees_Call) Run(run func(startDate int64, endDate int64)) *TaxRepository_GetOldFees_Call {
+func (_c *TaxRepository_GetOldFees_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxRepository_GetOldFees_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -291,30 +298,30 @@ func (_c *TaxRepository_GetOldFees_Call) Return(_a0 []entity.TotalFee, _a1 error
 	return _c
 }
 
-func (_c *TaxRepository_GetOldFees_Call) RunAndReturn(run func(int64, int64) ([]entity.TotalFee, error)) *TaxRepository_GetOldFees_Call {
+func (_c *TaxRepository_GetOldFees_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.TotalFee, error)) *TaxRepository_GetOldFees_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetOldFeesPerDay provides a mock function with given fields: startTime, endTime
-func (_m *TaxRepository) GetOldFeesPerDay(startTime int64, endTime int64) (*entity.TotalFee, error) {
-	ret := _m.Called(startTime, endTime)
+// GetOldFeesPerDay provides a mock function with given fields: ctx, startTime, endTime
+func (_m *TaxRepository) GetOldFeesPerDay(ctx context.Context, startTime int64, endTime int64) (*entity.TotalFee, error) {
+	ret := _m.Called(ctx, startTime, endTime)
 
 	var r0 *entity.TotalFee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.TotalFee, error)); ok {
-		return rf(startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*entity.TotalFee, error)); ok {
+		return rf(ctx, startTime, endTime)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) *entity.TotalFee); ok {
-		r0 = rf(startTime, endTime)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *entity.TotalFee); ok {
+		r0 = rf(ctx, startTime, endTime)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.TotalFee)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startTime, endTime)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startTime, endTime)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -328,15 +335,16 @@ type TaxRepository_GetOldFeesPerDay_Call struct {
 }
 
 // GetOldFeesPerDay is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startTime int64
 //   - endTime int64
-func (_e *TaxRepository_Expecter) GetOldFeesPerDay(startTime interface{}, endTime interface{}) *TaxRepository_GetOldFeesPerDay_Call {
-	return &TaxRepository_GetOldFeesPerDay_Call{Call: _e.mock.On("GetOldFeesPerDay", startTime, endTime)}
+func (_e *TaxRepository_Expecter) GetOldFeesPerDay(ctx interface{}, startTime interface{}, endTime interface{}) *TaxRepository_GetOldFeesPerDay_Call {
+	return &TaxRepository_GetOldFeesPerDay_Call{Call: _e.mock.On("GetOldFeesPerDay", ctx, startTime, endTime)}
 }
 
-func (_c *TaxRepository_GetOldFeesPerDay_Call) Run(run func(startTime int64, endTime int64)) *TaxRepository_GetOldFeesPerDay_Call {
+func (_c *TaxRepository_GetOldFeesPerDay_Call) Run(run func(ctx context.Context, startTime int64, endTime int64)) *TaxRepository_GetOldFeesPerDay_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -346,30 +354,30 @@ func (_c *TaxRepository_GetOldFeesPerDay_Call) Return(_a0 *entity.TotalFee, _a1
 	return _c
 }
 
-func (_c *TaxRepository_GetOldFeesPerDay_Call) RunAndReturn(run func(int64, int64) (*entity.TotalFee, error)) *TaxRepository_GetOldFeesPerDay_Call {
+func (_c *TaxRepository_GetOldFeesPerDay_Call) RunAndReturn(run func(context.Context, int64, int64) (*entity.TotalFee, error)) *TaxRepository_GetOldFeesPerDay_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTaxTransactions provides a mock function with given fields: startDate, endDate
-func (_m *TaxRepository) GetTaxTransactions(startDate int64, endDate int64) ([]entity.TaxTransactionSummary, error) {
-	ret := _m.Called(startDate, endDate)
+// GetTaxTransactions provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxRepository) GetTaxTransactions(ctx context.Context, startDate int64, endDate int64) ([]entity.TaxTransactionSummary, error) {
+	ret := _m.Called(ctx, startDate, endDate)
 
 	var r0 []entity.TaxTransactionSummary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.TaxTransactionSummary, error)); ok {
-		return rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.TaxTransactionSummary, error)); ok {
+		return rf(ctx, startDate, endDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.TaxTransactionSummary); ok {
-		r0 = rf(startDate, endDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.TaxTransactionSummary); ok {
+		r0 = rf(ctx, startDate, endDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.TaxTransactionSummary)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, endDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, endDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -383,15 +391,16 @@ type TaxRepository_GetTaxTransactions_Call struct {
 }
 
 // GetTaxTransactions is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - endDate int64
-func (_e *TaxRepository_Expecter) GetTaxTransactions(startDate interface{}, endDate interface{}) *TaxRepository_GetTaxTransactions_Call {
-	return &TaxRepository_GetTaxTransactions_Call{Call: _e.mock.On("GetTaxTransactions", startDate, endDate)}
+func (_e *TaxRepository_Expecter) GetTaxTransactions(ctx interface{}, startDate interface{}, endDate interface{}) *TaxRepository_GetTaxTransactions_Call {
+	return &TaxRepository_GetTaxTransactions_Call{Call: _e.mock.On("GetTaxTransactions", ctx, startDate, endDate)}
 }
 
-func (_c *TaxRepository_GetTaxTransactions_Call) Run(run func(startDate int64, endDate int64)) *TaxRepository_GetTaxTransactions_Call {
+func (_c *TaxRepository_GetTaxTransactions_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxRepository_GetTaxTransactions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -401,30 +410,30 @@ func (_c *TaxRepository_GetTaxTransactions_Call) Return(_a0 []entity.TaxTransact
 	return _c
 }
 
-func (_c *TaxRepository_GetTaxTransactions_Call) RunAndReturn(run func(int64, int64) ([]entity.TaxTransactionSummary, error)) *TaxRepository_GetTaxTransactions_Call {
+func (_c *TaxRepository_GetTaxTransactions_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.TaxTransactionSummary, error)) *TaxRepository_GetTaxTransactions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTotalWithdrawRp provides a mock function with given fields: startDate, calculationDate
-func (_m *TaxRepository) GetTotalWithdrawRp(startDate int64, calculationDate int64) ([]entity.TotalWithdrawRp, error) {
-	ret := _m.Called(startDate, calculationDate)
+// GetTotalWithdrawRp provides a mock function with given fields: ctx, startDate, calculationDate
+func (_m *TaxRepository) GetTotalWithdrawRp(ctx context.Context, startDate int64, calculationDate int64) ([]entity.TotalWithdrawRp, error) {
+	ret := _m.Called(ctx, startDate, calculationDate)
 
 	var r0 []entity.TotalWithdrawRp
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) ([]entity.TotalWithdrawRp, error)); ok {
-		return rf(startDate, calculationDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]entity.TotalWithdrawRp, error)); ok {
+		return rf(ctx, startDate, calculationDate)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) []entity.TotalWithdrawRp); ok {
-		r0 = rf(startDate, calculationDate)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []entity.TotalWithdrawRp); ok {
+		r0 = rf(ctx, startDate, calculationDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.TotalWithdrawRp)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(startDate, calculationDate)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, startDate, calculationDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -438,15 +447,16 @@ type TaxRepository_GetTotalWithdrawRp_Call struct {
 }
 
 // GetTotalWithdrawRp is a helper method to define mock.On call
+//   - ctx context.Context
 //   - startDate int64
 //   - calculationDate int64
-func (_e *TaxRepository_Expecter) GetTotalWithdrawRp(startDate interface{}, calculationDate interface{}) *TaxRepository_GetTotalWithdrawRp_Call {
-	return &TaxRepository_GetTotalWithdrawRp_Call{Call: _e.mock.On("GetTotalWithdrawRp", startDate, calculationDate)}
+func (_e *TaxRepository_Expecter) GetTotalWithdrawRp(ctx interface{}, startDate interface{}, calculationDate interface{}) *TaxRepository_GetTotalWithdrawRp_Call {
+	return &TaxRepository_GetTotalWithdrawRp_Call{Call: _e.mock.On("GetTotalWithdrawRp", ctx, startDate, calculationDate)}
 }
 
-func (_c *TaxRepository_GetTotalWithdrawRp_Call) Run(run func(startDate int64, calculationDate int64)) *TaxRepository_GetTotalWithdrawRp_Call {
+func (_c *TaxRepository_GetTotalWithdrawRp_Call) Run(run func(ctx context.Context, startDate int64, calculationDate int64)) *TaxRepository_GetTotalWithdrawRp_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(int64))
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
 	})
 	return _c
 }
@@ -456,23 +466,33 @@ func (_c *TaxRepository_GetTotalWithdrawRp_Call) Return(_a0 []entity.TotalWithdr
 	return _c
 }
 
-func (_c *TaxRepository_GetTotalWithdrawRp_Call) RunAndReturn(run func(int64, int64) ([]entity.TotalWithdrawRp, error)) *TaxRepository_GetTotalWithdrawRp_Call {
+func (_c *TaxRepository_GetTotalWithdrawRp_Call) RunAndReturn(run func(context.Context, int64, int64) ([]entity.TotalWithdrawRp, error)) *TaxRepository_GetTotalWithdrawRp_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// InsertTaxTransactions provides a mock function with given fields: transactionDate, taxTransactions
-func (_m *TaxRepository) InsertTaxTransactions(transactionDate int64, taxTransactions []entity.TaxTransaction) error {
-	ret := _m.Called(transactionDate, taxTransactions)
+// InsertTaxTransactions provides a mock function with given fields: ctx, transactionDate, taxTransactions
+func (_m *TaxRepository) InsertTaxTransactions(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error) {
+	ret := _m.Called(ctx, transactionDate, taxTransactions)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, []entity.TaxTransaction) error); ok {
-		r0 = rf(transactionDate, taxTransactions)
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []entity.TaxTransaction) (int64, error)); ok {
+		return rf(ctx, transactionDate, taxTransactions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []entity.TaxTransaction) int64); ok {
+		r0 = rf(ctx, transactionDate, taxTransactions)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []entity.TaxTransaction) error); ok {
+		r1 = rf(ctx, transactionDate, taxTransactions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // TaxRepository_InsertTaxTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertTaxTransactions'
@@ -481,25 +501,59 @@ type TaxRepository_InsertTaxTransactions_Call struct {
 }
 
 // InsertTaxTransactions is a helper method to define mock.On call
+//   - ctx context.Context
 //   - transactionDate int64
 //   - taxTransactions []entity.TaxTransaction
-func (_e *TaxRepository_Expecter) InsertTaxTransactions(transactionDate interface{}, taxTransactions interface{}) *TaxRepository_InsertTaxTransactions_Call {
-	return &TaxRepository_InsertTaxTransactions_Call{Call: _e.mock.On("InsertTaxTransactions", transactionDate, taxTransactions)}
+func (_e *TaxRepository_Expecter) InsertTaxTransactions(ctx interface{}, transactionDate interface{}, taxTransactions interface{}) *TaxRepository_InsertTaxTransactions_Call {
+	return &TaxRepository_InsertTaxTransactions_Call{Call: _e.mock.On("InsertTaxTransactions", ctx, transactionDate, taxTransactions)}
+}
+
+func (_c *TaxRepository_InsertTaxTransactions_Call) Run(run func(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction)) *TaxRepository_InsertTaxTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]entity.TaxTransaction))
+	})
+	return _c
+}
+
+func (_c *TaxRepository_InsertTaxTransactions_Call) Return(_a0 int64, _a1 error) *TaxRepository_InsertTaxTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TaxRepository_InsertTaxTransactions_Call) RunAndReturn(run func(context.Context, int64, []entity.TaxTransaction) (int64, error)) *TaxRepository_InsertTaxTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reload provides a mock function with given fields: taxSettings
+func (_m *TaxRepository) Reload(taxSettings *domain.TaxSettings) {
+	_m.Called(taxSettings)
+}
+
+// TaxRepository_Reload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reload'
+type TaxRepository_Reload_Call struct {
+	*mock.Call
+}
+
+// Reload is a helper method to define mock.On call
+//   - taxSettings *domain.TaxSettings
+func (_e *TaxRepository_Expecter) Reload(taxSettings interface{}) *TaxRepository_Reload_Call {
+	return &TaxRepository_Reload_Call{Call: _e.mock.On("Reload", taxSettings)}
 }
 
-func (_c *TaxRepository_InsertTaxTransactions_Call) Run(run func(transactionDate int64, taxTransactions []entity.TaxTransaction)) *TaxRepository_InsertTaxTransactions_Call {
+func (_c *TaxRepository_Reload_Call) Run(run func(taxSettings *domain.TaxSettings)) *TaxRepository_Reload_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].([]entity.TaxTransaction))
+		run(args[0].(*domain.TaxSettings))
 	})
 	return _c
 }
 
-func (_c *TaxRepository_InsertTaxTransactions_Call) Return(_a0 error) *TaxRepository_InsertTaxTransactions_Call {
-	_c.Call.Return(_a0)
+func (_c *TaxRepository_Reload_Call) Return() *TaxRepository_Reload_Call {
+	_c.Call.Return()
 	return _c
 }
 
-func (_c *TaxRepository_InsertTaxTransactions_Call) RunAndReturn(run func(int64, []entity.TaxTransaction) error) *TaxRepository_InsertTaxTransactions_Call {
+func (_c *TaxRepository_Reload_Call) RunAndReturn(run func(*domain.TaxSettings)) *TaxRepository_Reload_Call {
 	_c.Call.Return(run)
 	return _c
 }
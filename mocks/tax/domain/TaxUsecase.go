@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	context "context"
 	domain "tax-aggregator-service-demo/tax/domain"
 
 	mock "github.com/stretchr/testify/mock"
@@ -21,25 +22,68 @@ func (_m *TaxUsecase) EXPECT() *TaxUsecase_Expecter {
 	return &TaxUsecase_Expecter{mock: &_m.Mock}
 }
 
-// FetchSourceTax provides a mock function with given fields: taxSourceDate
-func (_m *TaxUsecase) FetchSourceTax(taxSourceDate *domain.TaxSourceDate) (*domain.TaxResponse, error) {
-	ret := _m.Called(taxSourceDate)
+// AggregateDay provides a mock function with given fields: ctx, transactionDate
+func (_m *TaxUsecase) AggregateDay(ctx context.Context, transactionDate int64) error {
+	ret := _m.Called(ctx, transactionDate)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, transactionDate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TaxUsecase_AggregateDay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AggregateDay'
+type TaxUsecase_AggregateDay_Call struct {
+	*mock.Call
+}
+
+// AggregateDay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionDate int64
+func (_e *TaxUsecase_Expecter) AggregateDay(ctx interface{}, transactionDate interface{}) *TaxUsecase_AggregateDay_Call {
+	return &TaxUsecase_AggregateDay_Call{Call: _e.mock.On("AggregateDay", ctx, transactionDate)}
+}
+
+func (_c *TaxUsecase_AggregateDay_Call) Run(run func(ctx context.Context, transactionDate int64)) *TaxUsecase_AggregateDay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *TaxUsecase_AggregateDay_Call) Return(_a0 error) *TaxUsecase_AggregateDay_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TaxUsecase_AggregateDay_Call) RunAndReturn(run func(context.Context, int64) error) *TaxUsecase_AggregateDay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchSourceTax provides a mock function with given fields: ctx, taxSourceDate
+func (_m *TaxUsecase) FetchSourceTax(ctx context.Context, taxSourceDate *domain.TaxSourceDate) (*domain.TaxResponse, error) {
+	ret := _m.Called(ctx, taxSourceDate)
 
 	var r0 *domain.TaxResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*domain.TaxSourceDate) (*domain.TaxResponse, error)); ok {
-		return rf(taxSourceDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaxSourceDate) (*domain.TaxResponse, error)); ok {
+		return rf(ctx, taxSourceDate)
 	}
-	if rf, ok := ret.Get(0).(func(*domain.TaxSourceDate) *domain.TaxResponse); ok {
-		r0 = rf(taxSourceDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaxSourceDate) *domain.TaxResponse); ok {
+		r0 = rf(ctx, taxSourceDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.TaxResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*domain.TaxSourceDate) error); ok {
-		r1 = rf(taxSourceDate)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.TaxSourceDate) error); ok {
+		r1 = rf(ctx, taxSourceDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -53,14 +97,15 @@ type TaxUsecase_FetchSourceTax_Call struct {
 }
 
 // FetchSourceTax is a helper method to define mock.On call
+//   - ctx context.Context
 //   - taxSourceDate *domain.TaxSourceDate
-func (_e *TaxUsecase_Expecter) FetchSourceTax(taxSourceDate interface{}) *TaxUsecase_FetchSourceTax_Call {
-	return &TaxUsecase_FetchSourceTax_Call{Call: _e.mock.On("FetchSourceTax", taxSourceDate)}
+func (_e *TaxUsecase_Expecter) FetchSourceTax(ctx interface{}, taxSourceDate interface{}) *TaxUsecase_FetchSourceTax_Call {
+	return &TaxUsecase_FetchSourceTax_Call{Call: _e.mock.On("FetchSourceTax", ctx, taxSourceDate)}
 }
 
-func (_c *TaxUsecase_FetchSourceTax_Call) Run(run func(taxSourceDate *domain.TaxSourceDate)) *TaxUsecase_FetchSourceTax_Call {
+func (_c *TaxUsecase_FetchSourceTax_Call) Run(run func(ctx context.Context, taxSourceDate *domain.TaxSourceDate)) *TaxUsecase_FetchSourceTax_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*domain.TaxSourceDate))
+		run(args[0].(context.Context), args[1].(*domain.TaxSourceDate))
 	})
 	return _c
 }
@@ -70,30 +115,30 @@ func (_c *TaxUsecase_FetchSourceTax_Call) Return(_a0 *domain.TaxResponse, _a1 er
 	return _c
 }
 
-func (_c *TaxUsecase_FetchSourceTax_Call) RunAndReturn(run func(*domain.TaxSourceDate) (*domain.TaxResponse, error)) *TaxUsecase_FetchSourceTax_Call {
+func (_c *TaxUsecase_FetchSourceTax_Call) RunAndReturn(run func(context.Context, *domain.TaxSourceDate) (*domain.TaxResponse, error)) *TaxUsecase_FetchSourceTax_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTax provides a mock function with given fields: taxDate
-func (_m *TaxUsecase) GetTax(taxDate *domain.TaxDate) (*domain.TaxResponse, error) {
-	ret := _m.Called(taxDate)
+// GetTax provides a mock function with given fields: ctx, taxDate
+func (_m *TaxUsecase) GetTax(ctx context.Context, taxDate *domain.TaxDate) (*domain.TaxResponse, error) {
+	ret := _m.Called(ctx, taxDate)
 
 	var r0 *domain.TaxResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*domain.TaxDate) (*domain.TaxResponse, error)); ok {
-		return rf(taxDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaxDate) (*domain.TaxResponse, error)); ok {
+		return rf(ctx, taxDate)
 	}
-	if rf, ok := ret.Get(0).(func(*domain.TaxDate) *domain.TaxResponse); ok {
-		r0 = rf(taxDate)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TaxDate) *domain.TaxResponse); ok {
+		r0 = rf(ctx, taxDate)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.TaxResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*domain.TaxDate) error); ok {
-		r1 = rf(taxDate)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.TaxDate) error); ok {
+		r1 = rf(ctx, taxDate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,14 +152,15 @@ type TaxUsecase_GetTax_Call struct {
 }
 
 // GetTax is a helper method to define mock.On call
+//   - ctx context.Context
 //   - taxDate *domain.TaxDate
-func (_e *TaxUsecase_Expecter) GetTax(taxDate interface{}) *TaxUsecase_GetTax_Call {
-	return &TaxUsecase_GetTax_Call{Call: _e.mock.On("GetTax", taxDate)}
+func (_e *TaxUsecase_Expecter) GetTax(ctx interface{}, taxDate interface{}) *TaxUsecase_GetTax_Call {
+	return &TaxUsecase_GetTax_Call{Call: _e.mock.On("GetTax", ctx, taxDate)}
 }
 
-func (_c *TaxUsecase_GetTax_Call) Run(run func(taxDate *domain.TaxDate)) *TaxUsecase_GetTax_Call {
+func (_c *TaxUsecase_GetTax_Call) Run(run func(ctx context.Context, taxDate *domain.TaxDate)) *TaxUsecase_GetTax_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*domain.TaxDate))
+		run(args[0].(context.Context), args[1].(*domain.TaxDate))
 	})
 	return _c
 }
@@ -124,7 +170,84 @@ func (_c *TaxUsecase_GetTax_Call) Return(_a0 *domain.TaxResponse, _a1 error) *Ta
 	return _c
 }
 
-func (_c *TaxUsecase_GetTax_Call) RunAndReturn(run func(*domain.TaxDate) (*domain.TaxResponse, error)) *TaxUsecase_GetTax_Call {
+func (_c *TaxUsecase_GetTax_Call) RunAndReturn(run func(context.Context, *domain.TaxDate) (*domain.TaxResponse, error)) *TaxUsecase_GetTax_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidateTax provides a mock function with given fields: ctx, startDate, endDate
+func (_m *TaxUsecase) InvalidateTax(ctx context.Context, startDate int64, endDate int64) int {
+	ret := _m.Called(ctx, startDate, endDate)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int); ok {
+		r0 = rf(ctx, startDate, endDate)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// TaxUsecase_InvalidateTax_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateTax'
+type TaxUsecase_InvalidateTax_Call struct {
+	*mock.Call
+}
+
+// InvalidateTax is a helper method to define mock.On call
+//   - ctx context.Context
+//   - startDate int64
+//   - endDate int64
+func (_e *TaxUsecase_Expecter) InvalidateTax(ctx interface{}, startDate interface{}, endDate interface{}) *TaxUsecase_InvalidateTax_Call {
+	return &TaxUsecase_InvalidateTax_Call{Call: _e.mock.On("InvalidateTax", ctx, startDate, endDate)}
+}
+
+func (_c *TaxUsecase_InvalidateTax_Call) Run(run func(ctx context.Context, startDate int64, endDate int64)) *TaxUsecase_InvalidateTax_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *TaxUsecase_InvalidateTax_Call) Return(_a0 int) *TaxUsecase_InvalidateTax_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TaxUsecase_InvalidateTax_Call) RunAndReturn(run func(context.Context, int64, int64) int) *TaxUsecase_InvalidateTax_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reload provides a mock function with given fields: taxSettings
+func (_m *TaxUsecase) Reload(taxSettings *domain.TaxSettings) {
+	_m.Called(taxSettings)
+}
+
+// TaxUsecase_Reload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reload'
+type TaxUsecase_Reload_Call struct {
+	*mock.Call
+}
+
+// Reload is a helper method to define mock.On call
+//   - taxSettings *domain.TaxSettings
+func (_e *TaxUsecase_Expecter) Reload(taxSettings interface{}) *TaxUsecase_Reload_Call {
+	return &TaxUsecase_Reload_Call{Call: _e.mock.On("Reload", taxSettings)}
+}
+
+func (_c *TaxUsecase_Reload_Call) Run(run func(taxSettings *domain.TaxSettings)) *TaxUsecase_Reload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.TaxSettings))
+	})
+	return _c
+}
+
+func (_c *TaxUsecase_Reload_Call) Return() *TaxUsecase_Reload_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TaxUsecase_Reload_Call) RunAndReturn(run func(*domain.TaxSettings)) *TaxUsecase_Reload_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.35.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	usecase "tax-aggregator-service-demo/tax/usecase"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaxUsecaseOption is an autogenerated mock type for the TaxUsecaseOption type
+type TaxUsecaseOption struct {
+	mock.Mock
+}
+
+type TaxUsecaseOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TaxUsecaseOption) EXPECT() *TaxUsecaseOption_Expecter {
+	return &TaxUsecaseOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *TaxUsecaseOption) Execute(_a0 *usecase.TaxUsecaseOptions) {
+	_m.Called(_a0)
+}
+
+// TaxUsecaseOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type TaxUsecaseOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *usecase.TaxUsecaseOptions
+func (_e *TaxUsecaseOption_Expecter) Execute(_a0 interface{}) *TaxUsecaseOption_Execute_Call {
+	return &TaxUsecaseOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *TaxUsecaseOption_Execute_Call) Run(run func(_a0 *usecase.TaxUsecaseOptions)) *TaxUsecaseOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*usecase.TaxUsecaseOptions))
+	})
+	return _c
+}
+
+func (_c *TaxUsecaseOption_Execute_Call) Return() *TaxUsecaseOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TaxUsecaseOption_Execute_Call) RunAndReturn(run func(*usecase.TaxUsecaseOptions)) *TaxUsecaseOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTaxUsecaseOption creates a new instance of TaxUsecaseOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaxUsecaseOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaxUsecaseOption {
+	mock := &TaxUsecaseOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/secret"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// roles granted by the roles claim, a role is granted every permission of the roles below it.
+const (
+	RoleViewer     = "viewer"     // read tax already persisted in service database
+	RoleAccountant = "accountant" // exports and fetching tax from source database
+	RoleAdmin      = "admin"      // admin endpoints, eg: job runs, audit trail and config reload
+)
+
+var roleRanks = map[string]int{
+	RoleViewer:     1,
+	RoleAccountant: 2,
+	RoleAdmin:      3,
+}
+
+var ErrUnauthenticated = errors.New("missing or invalid bearer token")
+
+// Claims of an authenticated request, key id is set when the request is signed with an api key.
+type Claims struct {
+	Subject string
+	KeyID   string
+	Roles   []string
+}
+
+// HasRole report whether one of the claims roles is at least required.
+func (c *Claims) HasRole(required string) bool {
+	for _, role := range c.Roles {
+		if roleRanks[role] >= roleRanks[required] && roleRanks[role] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator validate bearer tokens against the configured key and hmac signed requests against the api keys,
+// a nil authenticator allow every request.
+type Authenticator struct {
+	parser     *jwt.Parser
+	keyfunc    jwt.Keyfunc
+	rolesClaim string
+	signatures *signatureVerifier
+	secrets    *secret.Store
+}
+
+// NewAuthenticator return nil when auth is not enabled. a hmac secret referencing a secret is resolved through secrets,
+// api key secrets are resolved on every signed request so rotated secrets are used without restart.
+func NewAuthenticator(ctx context.Context, auth *config.Auth, secrets *secret.Store) (*Authenticator, error) {
+	if !auth.Enabled {
+		return nil, nil
+	}
+	authenticator := &Authenticator{
+		rolesClaim: auth.RolesClaim,
+		signatures: newSignatureVerifier(auth.APIKeys, time.Duration(auth.SignatureWindowSeconds)*time.Second),
+		secrets:    secrets,
+	}
+	var keyfunc jwt.Keyfunc
+	switch auth.Algorithm {
+	case "": // api keys only
+		return authenticator, nil
+	case "HS256":
+		hmacSecret, err := secrets.Resolve(ctx, auth.HMACSecret)
+		if err != nil {
+			return nil, err
+		}
+		keyfunc = func(*jwt.Token) (any, error) { return []byte(hmacSecret), nil }
+	case "RS256":
+		var err error
+		if auth.JWKSFile != "" {
+			keyfunc, err = jwksKeyfunc(auth.JWKSFile)
+		} else {
+			keyfunc, err = publicKeyKeyfunc(auth.PublicKeyFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", auth.Algorithm)
+	}
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{auth.Algorithm}), jwt.WithExpirationRequired()}
+	if auth.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(auth.Issuer))
+	}
+	if auth.Audience != "" {
+		opts = append(opts, jwt.WithAudience(auth.Audience))
+	}
+	authenticator.parser = jwt.NewParser(opts...)
+	authenticator.keyfunc = keyfunc
+	return authenticator, nil
+}
+
+// Authenticate validate the signature, expiry, issuer and audience of token and return its claims.
+// roles are read from the roles claim as a list or a space separated string.
+func (a *Authenticator) Authenticate(token string) (*Claims, error) {
+	if a.parser == nil {
+		return nil, errors.Join(ErrUnauthenticated, errors.New("bearer tokens are not enabled"))
+	}
+	mapClaims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(token, mapClaims, a.keyfunc); err != nil {
+		return nil, errors.Join(ErrUnauthenticated, err)
+	}
+	claims := &Claims{}
+	claims.Subject, _ = mapClaims.GetSubject()
+	switch roles := mapClaims[a.rolesClaim].(type) {
+	case string:
+		claims.Roles = splitRoles(roles)
+	case []any:
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				claims.Roles = append(claims.Roles, role)
+			}
+		}
+	}
+	return claims, nil
+}
+
+// AuthenticateSignature validate the api key, timestamp and signature headers of request and return the claims
+// of the api key client.
+func (a *Authenticator) AuthenticateSignature(request *http.Request) (*Claims, error) {
+	return a.signatures.verify(request, a.secrets.Resolve)
+}
+
+// Allowed report whether the request of ctx is granted role, every request is allowed when auth is not enabled.
+func (a *Authenticator) Allowed(ctx context.Context, role string) bool {
+	if a == nil {
+		return true
+	}
+	claims, ok := FromContext(ctx)
+	return ok && claims.HasRole(role)
+}
+
+type claimsKey struct{}
+
+// WithClaims return a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext return the claims of the authenticated request of ctx.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+func splitRoles(roles string) []string {
+	return strings.FieldsFunc(roles, func(r rune) bool { return r == ' ' || r == ',' })
+}
@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"tax-aggregator-service-demo/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func signHS256(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("hmac-secret"))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	authenticator, err := NewAuthenticator(context.Background(), &config.Auth{
+		Enabled:    true,
+		Algorithm:  "HS256",
+		HMACSecret: "hmac-secret",
+		Issuer:     "monolith",
+		RolesClaim: "roles",
+	}, nil)
+	assert.NoError(t, err)
+	tests := []struct {
+		name          string
+		token         string
+		expectedRoles []string
+		expectedError bool
+	}{
+		{
+			name:          "test passed when token is valid with roles list",
+			token:         signHS256(t, jwt.MapClaims{"sub": "finance-1", "iss": "monolith", "exp": expiresAt, "roles": []string{"accountant"}}),
+			expectedRoles: []string{"accountant"},
+		},
+		{
+			name:          "test passed when roles are a space separated string",
+			token:         signHS256(t, jwt.MapClaims{"sub": "finance-1", "iss": "monolith", "exp": expiresAt, "roles": "viewer admin"}),
+			expectedRoles: []string{"viewer", "admin"},
+		},
+		{
+			name:          "test failed when token is expired",
+			token:         signHS256(t, jwt.MapClaims{"iss": "monolith", "exp": time.Now().Add(-time.Minute).Unix()}),
+			expectedError: true,
+		},
+		{
+			name:          "test failed when token has no expiry",
+			token:         signHS256(t, jwt.MapClaims{"iss": "monolith"}),
+			expectedError: true,
+		},
+		{
+			name:          "test failed when issuer doesn't match",
+			token:         signHS256(t, jwt.MapClaims{"iss": "other", "exp": expiresAt}),
+			expectedError: true,
+		},
+		{
+			name: "test failed when token is not signed",
+			token: func() string {
+				token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"iss": "monolith", "exp": expiresAt}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return token
+			}(),
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := authenticator.Authenticate(tt.token)
+			if tt.expectedError {
+				assert.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "finance-1", claims.Subject)
+			assert.Equal(t, tt.expectedRoles, claims.Roles)
+		})
+	}
+}
+
+func TestAuthenticator_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	assert.NoError(t, err)
+	publicKeyFile := filepath.Join(dir, "public.pem")
+	assert.NoError(t, os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0o600))
+	jwksFile := filepath.Join(dir, "jwks.json")
+	jwksBytes, err := json.Marshal(jwks{Keys: []jwk{{
+		Kty: "RSA",
+		Kid: "key-1",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+	}}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(jwksFile, jwksBytes, 0o600))
+
+	sign := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "finance-1", "exp": time.Now().Add(time.Hour).Unix(), "roles": []string{"viewer"}})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(privateKey)
+		assert.NoError(t, err)
+		return signed
+	}
+	tests := []struct {
+		name          string
+		auth          config.Auth
+		token         string
+		expectedError bool
+	}{
+		{
+			name:  "test passed when token is verified by pem public key",
+			auth:  config.Auth{Enabled: true, Algorithm: "RS256", PublicKeyFile: publicKeyFile, RolesClaim: "roles"},
+			token: sign("key-1"),
+		},
+		{
+			name:  "test passed when token is verified by jwks key id",
+			auth:  config.Auth{Enabled: true, Algorithm: "RS256", JWKSFile: jwksFile, RolesClaim: "roles"},
+			token: sign("key-1"),
+		},
+		{
+			name:          "test failed when jwks has no key of token key id",
+			auth:          config.Auth{Enabled: true, Algorithm: "RS256", JWKSFile: jwksFile, RolesClaim: "roles"},
+			token:         sign("key-2"),
+			expectedError: true,
+		},
+		{
+			name:          "test failed when hs256 token is given to rs256 authenticator",
+			auth:          config.Auth{Enabled: true, Algorithm: "RS256", PublicKeyFile: publicKeyFile, RolesClaim: "roles"},
+			token:         signHS256(t, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}),
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := NewAuthenticator(context.Background(), &tt.auth, nil)
+			assert.NoError(t, err)
+			claims, err := authenticator.Authenticate(tt.token)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, claims.HasRole(RoleViewer))
+			assert.False(t, claims.HasRole(RoleAccountant))
+		})
+	}
+}
+
+func TestAuthenticator_Require(t *testing.T) {
+	authenticator, err := NewAuthenticator(context.Background(), &config.Auth{Enabled: true, Algorithm: "HS256", HMACSecret: "hmac-secret", RolesClaim: "roles"}, nil)
+	assert.NoError(t, err)
+	newServer := func(authenticator *Authenticator) *echo.Echo {
+		e := echo.New()
+		e.GET("/tax", func(ctx echo.Context) error {
+			if !authenticator.Allowed(ctx.Request().Context(), RoleAccountant) {
+				return ctx.String(http.StatusOK, "persisted only")
+			}
+			return ctx.String(http.StatusOK, "source fetch")
+		}, authenticator.Require(RoleViewer))
+		e.GET("/admin/audit", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }, authenticator.Require(RoleAdmin))
+		return e
+	}
+	bearer := func(roles ...string) string {
+		return "Bearer " + signHS256(t, jwt.MapClaims{"sub": "finance-1", "exp": time.Now().Add(time.Hour).Unix(), "roles": roles})
+	}
+	tests := []struct {
+		name          string
+		authenticator *Authenticator
+		method        string
+		path          string
+		authorization string
+		expectedCode  int
+		expectedBody  string
+	}{
+		{
+			name:          "test unauthorized without bearer token",
+			authenticator: authenticator,
+			method:        http.MethodGet,
+			path:          "/tax",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "test viewer only read persisted tax",
+			authenticator: authenticator,
+			method:        http.MethodGet,
+			path:          "/tax",
+			authorization: bearer(RoleViewer),
+			expectedCode:  http.StatusOK,
+			expectedBody:  "persisted only",
+		},
+		{
+			name:          "test admin inherit accountant source fetch",
+			authenticator: authenticator,
+			method:        http.MethodGet,
+			path:          "/tax",
+			authorization: bearer(RoleAdmin),
+			expectedCode:  http.StatusOK,
+			expectedBody:  "source fetch",
+		},
+		{
+			name:          "test forbidden when accountant call admin route",
+			authenticator: authenticator,
+			method:        http.MethodGet,
+			path:          "/admin/audit",
+			authorization: bearer(RoleAccountant),
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:         "test every request allowed when auth is not enabled",
+			method:       http.MethodGet,
+			path:         "/tax",
+			expectedCode: http.StatusOK,
+			expectedBody: "source fetch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				request.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			newServer(tt.authenticator).ServeHTTP(rec, request)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// publicKeyKeyfunc return the rsa public key of the pem file for every token.
+func publicKeyKeyfunc(path string) (jwt.Keyfunc, error) {
+	bytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(bytes)
+	if err != nil {
+		return nil, err
+	}
+	return func(*jwt.Token) (any, error) { return publicKey, nil }, nil
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jwksKeyfunc return the rsa key of the jwks file matching the token kid, a token without kid is only accepted
+// when the file hold a single key.
+func jwksKeyfunc(path string) (jwt.Keyfunc, error) {
+	bytes, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	keySet := jwks{}
+	if err := json.Unmarshal(bytes, &keySet); err != nil {
+		return nil, fmt.Errorf("decoding jwks %s: %w", path, err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		publicKey, err := rsaPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("decoding jwks %s key %q: %w", path, key.Kid, err)
+		}
+		keys[key.Kid] = publicKey
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks %s has no rsa signing key", path)
+	}
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		if publicKey, ok := keys[kid]; ok {
+			return publicKey, nil
+		}
+		if kid == "" && len(keys) == 1 {
+			for _, publicKey := range keys {
+				return publicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}, nil
+}
+
+func rsaPublicKey(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("invalid rsa modulus or exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"tax-aggregator-service-demo/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Require authenticate the hmac signature (when X-Api-Key is set) or the bearer token of the request and check it is granted role, the claims are put into
+// the request context. every request pass through when auth is not enabled.
+func (a *Authenticator) Require(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if a == nil {
+				return next(ctx)
+			}
+			request := ctx.Request()
+			var (
+				claims *Claims
+				err    error
+			)
+			if request.Header.Get(APIKeyHeader) != "" {
+				claims, err = a.AuthenticateSignature(request)
+				if err != nil {
+					slog.WarnContext(request.Context(), "[auth.Require]:: error authenticating signed request.", slog.String("key_id", request.Header.Get(APIKeyHeader)), logger.Err(err))
+					return ctx.JSON(http.StatusUnauthorized, echo.Map{"code": http.StatusUnauthorized, "message": ErrInvalidSignature.Error()})
+				}
+			} else {
+				token, ok := strings.CutPrefix(request.Header.Get(echo.HeaderAuthorization), "Bearer ")
+				if !ok {
+					ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+					return ctx.JSON(http.StatusUnauthorized, echo.Map{"code": http.StatusUnauthorized, "message": ErrUnauthenticated.Error()})
+				}
+				claims, err = a.Authenticate(token)
+				if err != nil {
+					slog.WarnContext(request.Context(), "[auth.Require]:: error authenticating request.", logger.Err(err))
+					ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+					return ctx.JSON(http.StatusUnauthorized, echo.Map{"code": http.StatusUnauthorized, "message": ErrUnauthenticated.Error()})
+				}
+			}
+			logAttrs := []any{slog.String("subject", claims.Subject)}
+			if claims.KeyID != "" {
+				logAttrs = append(logAttrs, slog.String("key_id", claims.KeyID))
+			}
+			requestCtx := logger.With(WithClaims(request.Context(), claims), logAttrs...)
+			if !claims.HasRole(role) {
+				slog.WarnContext(requestCtx, "[auth.Require]:: request is not granted role.", slog.String("role", role), slog.Any("roles", claims.Roles))
+				return ctx.JSON(http.StatusForbidden, echo.Map{"code": http.StatusForbidden, "message": "role " + role + " is required"})
+			}
+			ctx.SetRequest(request.WithContext(requestCtx))
+			return next(ctx)
+		}
+	}
+}
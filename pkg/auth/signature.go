@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"container/heap"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"tax-aggregator-service-demo/config"
+	"time"
+)
+
+// headers of a hmac signed request, the signature is the hex hmac-sha256 of CanonicalRequest with the api key secret.
+const (
+	APIKeyHeader    = "X-Api-Key"
+	TimestampHeader = "X-Timestamp"
+	SignatureHeader = "X-Signature"
+)
+
+// default accepted distance between the request timestamp and the server clock
+const defaultSignatureWindow = 5 * time.Minute
+
+var ErrInvalidSignature = errors.New("invalid request signature")
+
+// CanonicalRequest return the signed string of a request: method, path, query sorted by key and unix timestamp joined by new lines.
+func CanonicalRequest(method, path string, query url.Values, timestamp int64) string {
+	return strings.Join([]string{strings.ToUpper(method), path, query.Encode(), strconv.FormatInt(timestamp, 10)}, "\n")
+}
+
+// Sign return the signature of a request with secret, used by callers to fill SignatureHeader.
+func Sign(secret, method, path string, query url.Values, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(CanonicalRequest(method, path, query, timestamp)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signatureVerifier verify hmac signed requests, a signature is accepted once within the window so a captured request
+// can't be replayed on the same replica. seen signatures are kept in memory, replay protection doesn't span replicas,
+// a captured request can still be replayed once on every other replica within the window.
+type signatureVerifier struct {
+	apiKeys  map[string]config.APIKey
+	window   time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	seen     map[string]struct{}
+	expiries expiryHeap
+}
+
+// seen signature expiring at expiresAt
+type seenSignature struct {
+	signature string
+	expiresAt int64
+}
+
+// expiryHeap order seen signatures by expiry, so expired ones are removed without scanning every seen signature.
+type expiryHeap []seenSignature
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiresAt < h[j].expiresAt }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x any)        { *h = append(*h, x.(seenSignature)) }
+func (h *expiryHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func newSignatureVerifier(apiKeys []config.APIKey, window time.Duration) *signatureVerifier {
+	if window <= 0 {
+		window = defaultSignatureWindow
+	}
+	keys := map[string]config.APIKey{}
+	for _, apiKey := range apiKeys {
+		keys[apiKey.ID] = apiKey
+	}
+	return &signatureVerifier{
+		apiKeys: keys,
+		window:  window,
+		now:     time.Now,
+		seen:    map[string]struct{}{},
+	}
+}
+
+// verify return the claims of the api key client when the request is signed by the api key secret within the window.
+func (sv *signatureVerifier) verify(request *http.Request, resolve func(ctx context.Context, value string) (string, error)) (*Claims, error) {
+	apiKey, ok := sv.apiKeys[request.Header.Get(APIKeyHeader)]
+	if !ok {
+		return nil, errors.Join(ErrInvalidSignature, errors.New("unknown api key"))
+	}
+	timestamp, err := strconv.ParseInt(request.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, errors.New("timestamp is not unix seconds"))
+	}
+	now := sv.now()
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > sv.window || skew < -sv.window {
+		return nil, errors.Join(ErrInvalidSignature, errors.New("timestamp is outside of the signature window"))
+	}
+	secret, err := resolve(request.Context(), apiKey.Secret)
+	if err != nil {
+		return nil, err
+	}
+	signature := request.Header.Get(SignatureHeader)
+	expected := Sign(secret, request.Method, request.URL.Path, request.URL.Query(), timestamp)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, errors.Join(ErrInvalidSignature, errors.New("signature mismatch"))
+	}
+	// the signature stay acceptable until timestamp+window, so it must be remembered at least that long.
+	if !sv.remember(apiKey.ID+":"+signature, time.Unix(timestamp, 0).Add(sv.window).Unix(), now.Unix()) {
+		return nil, errors.Join(ErrInvalidSignature, errors.New("signature is replayed"))
+	}
+	return &Claims{Subject: apiKey.Client, KeyID: apiKey.ID, Roles: apiKey.Roles}, nil
+}
+
+// remember record signature until expiresAt, it return false when the signature was already seen.
+// only the expired signatures are removed, in O(log n) each.
+func (sv *signatureVerifier) remember(signature string, expiresAt, now int64) bool {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	for len(sv.expiries) > 0 && sv.expiries[0].expiresAt < now {
+		expired := heap.Pop(&sv.expiries).(seenSignature)
+		delete(sv.seen, expired.signature)
+	}
+	if _, ok := sv.seen[signature]; ok {
+		return false
+	}
+	sv.seen[signature] = struct{}{}
+	heap.Push(&sv.expiries, seenSignature{signature: signature, expiresAt: expiresAt})
+	return true
+}
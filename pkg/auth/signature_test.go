@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"tax-aggregator-service-demo/config"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedRequest(method, target, keyID, secret string, timestamp int64) *http.Request {
+	request := httptest.NewRequest(method, target, nil)
+	request.Header.Set(APIKeyHeader, keyID)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(secret, method, request.URL.Path, request.URL.Query(), timestamp))
+	return request
+}
+
+func TestSign(t *testing.T) {
+	query := url.Values{"start_date": {"1682899200"}, "amount_of_days": {"7"}}
+	assert.Equal(t, "GET\n/tax\namount_of_days=7&start_date=1682899200\n1682899200", CanonicalRequest("get", "/tax", query, 1682899200))
+	assert.Equal(t, Sign("secret", "GET", "/tax", query, 1682899200), Sign("secret", "GET", "/tax", url.Values{"amount_of_days": {"7"}, "start_date": {"1682899200"}}, 1682899200))
+	assert.NotEqual(t, Sign("secret", "GET", "/tax", query, 1682899200), Sign("secret", "GET", "/tax", query, 1682899201))
+}
+
+func TestAuthenticator_AuthenticateSignature(t *testing.T) {
+	now := time.Unix(1682899200, 0)
+	newAuthenticator := func() *Authenticator {
+		authenticator, err := NewAuthenticator(context.Background(), &config.Auth{
+			Enabled: true,
+			APIKeys: []config.APIKey{
+				{ID: "monolith-2023", Client: "monolith", Secret: "old-secret", Roles: []string{RoleAccountant}},
+				{ID: "monolith-2024", Client: "monolith", Secret: "new-secret", Roles: []string{RoleAccountant}},
+			},
+			SignatureWindowSeconds: 300,
+		}, nil)
+		assert.NoError(t, err)
+		authenticator.signatures.now = func() time.Time { return now }
+		return authenticator
+	}
+	target := "/tax?start_date=1682899200&amount_of_days=7"
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test passed when request is signed by a current key",
+			testFunction: func(t *testing.T) {
+				for _, key := range [][2]string{{"monolith-2023", "old-secret"}, {"monolith-2024", "new-secret"}} {
+					claims, err := newAuthenticator().AuthenticateSignature(signedRequest(http.MethodGet, target, key[0], key[1], now.Unix()-60))
+					assert.NoError(t, err)
+					assert.Equal(t, &Claims{Subject: "monolith", KeyID: key[0], Roles: []string{RoleAccountant}}, claims)
+				}
+			},
+		},
+		{
+			name: "test failed when signature is replayed",
+			testFunction: func(t *testing.T) {
+				authenticator := newAuthenticator()
+				_, err := authenticator.AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()))
+				assert.NoError(t, err)
+				_, err = authenticator.AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "test failed when signature signed ahead of the clock is replayed after the window",
+			testFunction: func(t *testing.T) {
+				authenticator := newAuthenticator()
+				request := signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()+300)
+				_, err := authenticator.AuthenticateSignature(request)
+				assert.NoError(t, err)
+				authenticator.signatures.now = func() time.Time { return now.Add(301 * time.Second) }
+				_, err = authenticator.AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()+300))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "test failed when timestamp is outside of the window",
+			testFunction: func(t *testing.T) {
+				_, err := newAuthenticator().AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()-301))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+				_, err = newAuthenticator().AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()+301))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "test failed when query is changed after signing",
+			testFunction: func(t *testing.T) {
+				request := signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix())
+				request.URL.RawQuery = "start_date=1682899200&amount_of_days=30"
+				_, err := newAuthenticator().AuthenticateSignature(request)
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "test failed when key is unknown or signed by another key secret",
+			testFunction: func(t *testing.T) {
+				_, err := newAuthenticator().AuthenticateSignature(signedRequest(http.MethodGet, target, "revoked", "old-secret", now.Unix()))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+				_, err = newAuthenticator().AuthenticateSignature(signedRequest(http.MethodGet, target, "monolith-2024", "old-secret", now.Unix()))
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "test require check the roles of the api key",
+			testFunction: func(t *testing.T) {
+				authenticator := newAuthenticator()
+				e := echo.New()
+				e.GET("/tax", func(ctx echo.Context) error {
+					claims, _ := FromContext(ctx.Request().Context())
+					return ctx.String(http.StatusOK, claims.Subject)
+				}, authenticator.Require(RoleViewer))
+				e.GET("/admin/audit", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }, authenticator.Require(RoleAdmin))
+
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, signedRequest(http.MethodGet, target, "monolith-2024", "new-secret", now.Unix()))
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "monolith", rec.Body.String())
+
+				rec = httptest.NewRecorder()
+				e.ServeHTTP(rec, signedRequest(http.MethodGet, "/admin/audit", "monolith-2024", "new-secret", now.Unix()))
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+
+				rec = httptest.NewRecorder()
+				e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestSignatureVerifier_Remember(t *testing.T) {
+	verifier := newSignatureVerifier(nil, time.Minute)
+	assert.True(t, verifier.remember("key-1:a", 160, 100))
+	assert.True(t, verifier.remember("key-1:b", 170, 110))
+	assert.False(t, verifier.remember("key-1:a", 180, 120))
+	assert.True(t, verifier.remember("key-1:c", 225, 165)) // a expired
+	assert.Len(t, verifier.seen, 2)
+	assert.Len(t, verifier.expiries, 2)
+	assert.True(t, verifier.remember("key-1:a", 230, 170))
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a size bounded least recently used cache with a time to live per entry, safe for concurrent use.
+// every removal bump the generation so a value computed before an invalidation is not added back afterwards.
+type LRU[K comparable, V any] struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	items      map[K]*list.Element
+	order      *list.List
+	generation uint64
+	now        func() time.Time
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU create a cache holding up to size entries, entries never expire when ttl is not set.
+func NewLRU[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:  size,
+		ttl:   ttl,
+		items: map[K]*list.Element{},
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+// Get return the value of key and mark it as recently used, expired entries are removed and reported as missing.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var value V
+	element, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	item := element.Value.(*entry[K, V])
+	if c.ttl > 0 && !c.now().Before(item.expiresAt) {
+		c.order.Remove(element)
+		delete(c.items, key)
+		return value, false
+	}
+	c.order.MoveToFront(element)
+	return item.value, true
+}
+
+// Generation return the current generation, read it before computing a value and pass it into Add.
+func (c *LRU[K, V]) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Add store value under key evicting the least recently used entry when full,
+// the value is dropped when an entry was removed since generation was read.
+func (c *LRU[K, V]) Add(key K, value V, generation uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.size <= 0 || generation != c.generation {
+		return false
+	}
+	item := &entry[K, V]{key: key, value: value}
+	if c.ttl > 0 {
+		item.expiresAt = c.now().Add(c.ttl)
+	}
+	if element, ok := c.items[key]; ok {
+		element.Value = item
+		c.order.MoveToFront(element)
+		return true
+	}
+	c.items[key] = c.order.PushFront(item)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry[K, V]).key)
+	}
+	return true
+}
+
+// RemoveFunc remove every entry whose key match and return the number of removed entries.
+func (c *LRU[K, V]) RemoveFunc(match func(key K) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	removed := 0
+	for key, element := range c.items {
+		if match(key) {
+			c.order.Remove(element)
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len return the number of entries, including expired entries not yet removed.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
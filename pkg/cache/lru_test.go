@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test evict least recently used entry when full",
+			testFunction: func(t *testing.T) {
+				lru := NewLRU[string, int](2, 0)
+				lru.Add("a", 1, lru.Generation())
+				lru.Add("b", 2, lru.Generation())
+				_, ok := lru.Get("a")
+				assert.True(t, ok)
+				lru.Add("c", 3, lru.Generation())
+				_, ok = lru.Get("b")
+				assert.False(t, ok)
+				value, ok := lru.Get("a")
+				assert.True(t, ok)
+				assert.Equal(t, 1, value)
+				assert.Equal(t, 2, lru.Len())
+			},
+		},
+		{
+			name: "test expire entry after ttl",
+			testFunction: func(t *testing.T) {
+				now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+				lru := NewLRU[string, int](2, time.Minute)
+				lru.now = func() time.Time { return now }
+				lru.Add("a", 1, lru.Generation())
+				now = now.Add(59 * time.Second)
+				_, ok := lru.Get("a")
+				assert.True(t, ok)
+				now = now.Add(time.Second)
+				_, ok = lru.Get("a")
+				assert.False(t, ok)
+				assert.Equal(t, 0, lru.Len())
+			},
+		},
+		{
+			name: "test remove matching entries and drop values computed before removal",
+			testFunction: func(t *testing.T) {
+				lru := NewLRU[int, int](10, 0)
+				generation := lru.Generation()
+				for i := 0; i < 5; i++ {
+					lru.Add(i, i, generation)
+				}
+				assert.Equal(t, 2, lru.RemoveFunc(func(key int) bool { return key >= 3 }))
+				assert.Equal(t, 3, lru.Len())
+				assert.False(t, lru.Add(3, 3, generation))
+				assert.True(t, lru.Add(3, 3, lru.Generation()))
+			},
+		},
+		{
+			name: "test disabled when size is not set",
+			testFunction: func(t *testing.T) {
+				lru := NewLRU[string, int](0, 0)
+				assert.False(t, lru.Add("a", 1, lru.Generation()))
+				_, ok := lru.Get("a")
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"tax-aggregator-service-demo/pkg/logger"
+	"time"
+)
+
+// connection id query of the pinned mysql connection, used to kill the running query.
+const MySQLConnectionID = "SELECT CONNECTION_ID()"
+
+// WithQueryTimeout bound a single query by timeout, DefaultTimeout milliseconds is used when timeout is not set.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout * time.Millisecond
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Rows of a query, Close release the pinned connection of QueryMySQLContext back into the pool.
+type Rows struct {
+	*sql.Rows
+	conn   *sql.Conn
+	stop   func() bool
+	killed chan struct{}
+}
+
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	if r.conn == nil { // not pinned
+		return err
+	}
+	if !r.stop() { // wait for the kill so it can't hit the next query on the same connection
+		<-r.killed
+	}
+	r.conn.Close()
+	return err
+}
+
+// QueryMySQLContext run query on a pinned connection. go-sql-driver/mysql only closes the client side of the connection
+// when ctx is done and leaves the query running on the server, so the query is killed with KILL QUERY.
+func QueryMySQLContext(ctx context.Context, db *sql.DB, query string, args ...any) (*Rows, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var connectionID int64
+	if err := conn.QueryRowContext(ctx, MySQLConnectionID).Scan(&connectionID); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	killed := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(killed)
+		killMySQLQuery(db, connectionID)
+	})
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		if !stop() {
+			<-killed
+		}
+		conn.Close()
+		return nil, err
+	}
+	return &Rows{
+		Rows:   rows,
+		conn:   conn,
+		stop:   stop,
+		killed: killed,
+	}, nil
+}
+
+func killMySQLQuery(db *sql.DB, connectionID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout*time.Millisecond)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("KILL QUERY %d", connectionID)); err != nil {
+		slog.Error("[dbconn.killMySQLQuery]:: error killing query on connection.", slog.Int64("connection_id", connectionID), logger.Err(err))
+	}
+}
@@ -0,0 +1,56 @@
+package dbconn
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMySQLContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test query release the connection without killing",
+			testFunction: func(t *testing.T) {
+				db, dbMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				dbMock.ExpectQuery(regexp.QuoteMeta(MySQLConnectionID)).WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(42))
+				dbMock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				ctx, cancel := context.WithCancel(context.Background())
+				rows, err := QueryMySQLContext(ctx, db, "SELECT 1")
+				assert.NoError(t, err)
+				assert.True(t, rows.Next())
+				assert.NoError(t, rows.Close())
+				cancel()
+				assert.NoError(t, dbMock.ExpectationsWereMet())
+			},
+		},
+		{
+			name: "test query killed on the server when context is done",
+			testFunction: func(t *testing.T) {
+				db, dbMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				dbMock.ExpectQuery(regexp.QuoteMeta(MySQLConnectionID)).WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(42))
+				dbMock.ExpectQuery("SELECT SLEEP").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+				dbMock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				_, err = QueryMySQLContext(ctx, db, "SELECT SLEEP(60)")
+				assert.Error(t, err)
+				assert.NoError(t, dbMock.ExpectationsWereMet())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
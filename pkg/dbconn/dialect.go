@@ -0,0 +1,82 @@
+package dbconn
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+)
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// Dialect of service database, queries are written with postgresql $n placeholders and rebound for the driver.
+type Dialect struct {
+	Driver string
+}
+
+// DialectOf return the dialect of the driver db was opened with, postgresql for any other driver.
+func DialectOf(db *sql.DB) Dialect {
+	if db == nil {
+		return Dialect{Driver: DriverPostgres}
+	}
+	switch db.Driver().(type) {
+	case *mysql.MySQLDriver, mysql.MySQLDriver:
+		return Dialect{Driver: DriverMySQL}
+	case *sqlite.Driver:
+		return Dialect{Driver: DriverSQLite}
+	}
+	return Dialect{Driver: DriverPostgres}
+}
+
+// Rebind rewrite $n placeholders of query into ? for mysql and sqlite, args are repeated and reordered
+// so a placeholder used more than once still get its value.
+func (d Dialect) Rebind(query string, args ...any) (string, []any) {
+	if d.Driver == DriverPostgres {
+		return query, args
+	}
+	rebound := []any{}
+	query = placeholder.ReplaceAllStringFunc(query, func(match string) string {
+		n, _ := strconv.Atoi(match[1:])
+		if n >= 1 && n <= len(args) {
+			rebound = append(rebound, args[n-1])
+		}
+		return "?"
+	})
+	return query, rebound
+}
+
+// Upsert return the clause appended into an insert updating columns when a row with the same conflict columns exists.
+func (d Dialect) Upsert(conflictColumns []string, updateColumns ...string) string {
+	updates := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		if d.Driver == DriverMySQL {
+			updates[i] = fmt.Sprintf("%s = VALUES(%s)", column, column)
+		} else {
+			updates[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+		}
+	}
+	if d.Driver == DriverMySQL {
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(updates, ", "))
+}
+
+// DayOfMonth return the expression of the day of month of a unix time column.
+func (d Dialect) DayOfMonth(column string) string {
+	switch d.Driver {
+	case DriverMySQL:
+		return fmt.Sprintf("DAYOFMONTH(DATE_ADD('1970-01-01', INTERVAL %s SECOND))", column)
+	case DriverSQLite:
+		return fmt.Sprintf("CAST(STRFTIME('%%d', %s, 'unixepoch') AS INTEGER)", column)
+	}
+	return fmt.Sprintf("DATE_PART('day', TO_TIMESTAMP(%s))", column)
+}
+
+// Returning report whether INSERT ... RETURNING is supported, LastInsertId is used on mysql.
+func (d Dialect) Returning() bool {
+	return d.Driver != DriverMySQL
+}
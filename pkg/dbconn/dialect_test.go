@@ -0,0 +1,66 @@
+package dbconn
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test rebind repeat and reorder args for mysql and sqlite",
+			testFunction: func(t *testing.T) {
+				query, args := Dialect{Driver: DriverSQLite}.Rebind("SELECT 1 WHERE a = $2 OR b = $1 OR c = $2", "x", "y")
+				assert.Equal(t, "SELECT 1 WHERE a = ? OR b = ? OR c = ?", query)
+				assert.Equal(t, []any{"y", "x", "y"}, args)
+			},
+		},
+		{
+			name: "test rebind keep postgresql query as is",
+			testFunction: func(t *testing.T) {
+				query, args := Dialect{Driver: DriverPostgres}.Rebind("SELECT 1 WHERE a = $1", "x")
+				assert.Equal(t, "SELECT 1 WHERE a = $1", query)
+				assert.Equal(t, []any{"x"}, args)
+			},
+		},
+		{
+			name: "test upsert clause",
+			testFunction: func(t *testing.T) {
+				assert.Equal(t, "ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)", Dialect{Driver: DriverMySQL}.Upsert([]string{"id"}, "a", "b"))
+				assert.Equal(t, "ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a, b = EXCLUDED.b", Dialect{Driver: DriverSQLite}.Upsert([]string{"id"}, "a", "b"))
+				assert.Equal(t, "ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a, b = EXCLUDED.b", Dialect{Driver: DriverPostgres}.Upsert([]string{"id"}, "a", "b"))
+			},
+		},
+		{
+			name: "test day of month and returning",
+			testFunction: func(t *testing.T) {
+				assert.Equal(t, "DAYOFMONTH(DATE_ADD('1970-01-01', INTERVAL t.d SECOND))", Dialect{Driver: DriverMySQL}.DayOfMonth("t.d"))
+				assert.Equal(t, "CAST(STRFTIME('%d', t.d, 'unixepoch') AS INTEGER)", Dialect{Driver: DriverSQLite}.DayOfMonth("t.d"))
+				assert.Equal(t, "DATE_PART('day', TO_TIMESTAMP(t.d))", Dialect{Driver: DriverPostgres}.DayOfMonth("t.d"))
+				assert.False(t, Dialect{Driver: DriverMySQL}.Returning())
+				assert.True(t, Dialect{Driver: DriverSQLite}.Returning())
+			},
+		},
+		{
+			name: "test dialect of driver db was opened with",
+			testFunction: func(t *testing.T) {
+				db, _, err := sqlmock.New()
+				assert.NoError(t, err)
+				defer db.Close()
+				assert.Equal(t, DriverPostgres, DialectOf(db).Driver)
+				assert.Equal(t, DriverPostgres, DialectOf(nil).Driver)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
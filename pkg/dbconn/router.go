@@ -0,0 +1,339 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/logger"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// replication lag query on mysql replica, Seconds_Behind_Source is NULL when replication is stopped
+// and there is no row when the server is not a replica.
+const MySQLReplicaStatus = "SHOW REPLICA STATUS"
+
+// replication lag query on postgresql standby in seconds, zero on primary.
+const PostgresReplicaStatus = "SELECT CASE WHEN pg_is_in_recovery() THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) ELSE 0 END"
+
+const defaultReplicaCheckInterval = 5 * time.Second
+
+var ErrReplicationStopped = errors.New("replication is stopped")
+
+// Replica is a read replica connection pool, name is only used for logging
+type Replica struct {
+	Name string
+	DB   *sql.DB
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// Router route read queries round-robin over the healthy replicas and fall back into the primary when every replica is down.
+// replicas are health checked periodically, a replica failing to ping or lagging behind max lag is skipped until it recovers,
+// and a replica failing to connect on a query is skipped right away.
+type Router struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+	options  RouterOptions
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+type RouterOptions struct {
+	Driver        string
+	MaxLag        time.Duration
+	CheckInterval time.Duration
+}
+
+type RouterOption func(*RouterOptions)
+
+// WithDriver set the database driver of primary and replicas, default mysql.
+func WithDriver(driver string) RouterOption {
+	return func(options *RouterOptions) {
+		if driver != "" {
+			options.Driver = driver
+		}
+	}
+}
+
+// WithMaxReplicaLag skip replicas behind the primary more than maxLag, lag is not checked when not set.
+func WithMaxReplicaLag(maxLag time.Duration) RouterOption {
+	return func(options *RouterOptions) {
+		options.MaxLag = maxLag
+	}
+}
+
+// WithReplicaCheckInterval set how often replicas are health checked, default 5 seconds.
+func WithReplicaCheckInterval(checkInterval time.Duration) RouterOption {
+	return func(options *RouterOptions) {
+		if checkInterval > 0 {
+			options.CheckInterval = checkInterval
+		}
+	}
+}
+
+// NewRouter create a router over primary and replicas, replicas are unhealthy until the first CheckReplicas.
+func NewRouter(primary *sql.DB, replicas []Replica, opts ...RouterOption) *Router {
+	options := RouterOptions{
+		Driver:        DriverMySQL,
+		CheckInterval: defaultReplicaCheckInterval,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	router := &Router{
+		primary: primary,
+		options: options,
+		stop:    make(chan struct{}),
+	}
+	for _, r := range replicas {
+		router.replicas = append(router.replicas, &replica{name: r.Name, db: r.DB})
+	}
+	return router
+}
+
+// NewSourceRouter connect into the primary and every replica of dbConfig with dbConfig driver (mysql when not set),
+// replicas failing to connect are left unhealthy and picked up by the periodic health check once they are back.
+func NewSourceRouter(dbConfig *config.Database, opts ...ConnOption) (*Router, error) {
+	options := newConnOptions(opts)
+	driver := dbConfig.Driver
+	if driver == "" {
+		driver = DriverMySQL
+	}
+	if driver == DriverSQLite && len(dbConfig.Replicas) > 0 {
+		return nil, errors.New("sqlite source database doesn't support replicas")
+	}
+	primary, err := openDBConn(driver, dbConfig, options)
+	if err != nil {
+		slog.Error("[dbconn.NewSourceRouter]:: error opening connection into source database", logger.Err(err))
+		return nil, err
+	}
+	if err := primary.Ping(); err != nil {
+		slog.Error("[dbconn.NewSourceRouter]:: error pinging connection", logger.Err(err))
+		primary.Close()
+		return nil, err
+	}
+	replicas := []Replica{}
+	for i := range dbConfig.Replicas {
+		replicaConfig := &dbConfig.Replicas[i]
+		name := net.JoinHostPort(replicaConfig.DBHost, replicaConfig.DBPort)
+		db, err := openDBConn(driver, replicaConfig, options)
+		if err != nil {
+			slog.Error("[dbconn.NewSourceRouter]:: error opening connection into replica.", slog.String("replica", name), logger.Err(err))
+			continue
+		}
+		replicas = append(replicas, Replica{Name: name, DB: db})
+	}
+	router := NewRouter(primary, replicas,
+		WithDriver(driver),
+		WithMaxReplicaLag(time.Duration(dbConfig.MaxReplicaLagSeconds)*time.Second),
+		WithReplicaCheckInterval(time.Duration(dbConfig.ReplicaCheckIntervalSeconds)*time.Second),
+	)
+	router.CheckReplicas(context.Background())
+	return router, nil
+}
+
+// Driver return the database driver of primary and replicas.
+func (r *Router) Driver() string {
+	return r.options.Driver
+}
+
+// Primary return the primary connection pool.
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+// Replicas return the replica connection pools.
+func (r *Router) Replicas() []Replica {
+	replicas := make([]Replica, len(r.replicas))
+	for i, replica := range r.replicas {
+		replicas[i] = Replica{Name: replica.name, DB: replica.db}
+	}
+	return replicas
+}
+
+// reader return the next healthy replica round-robin, nil when there is none.
+func (r *Router) reader() *replica {
+	n := uint64(len(r.replicas))
+	if n == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if replica := r.replicas[(start+i)%n]; replica.healthy.Load() {
+			return replica
+		}
+	}
+	return nil
+}
+
+// QueryContext run query on the next healthy replica, a replica failing to connect is marked unhealthy
+// and the query is retried on the next one, then on the primary. mysql queries are run with QueryMySQLContext.
+func (r *Router) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	for range r.replicas {
+		replica := r.reader()
+		if replica == nil {
+			break
+		}
+		rows, err := r.queryContext(ctx, replica.db, query, args...)
+		if err != nil && ctx.Err() == nil && isConnectionError(err) {
+			slog.WarnContext(ctx, "[dbconn.Router.QueryContext]:: replica is down, failing over.", slog.String("replica", replica.name), logger.Err(err))
+			replica.healthy.Store(false)
+			continue
+		}
+		return rows, err
+	}
+	return r.queryContext(ctx, r.primary, query, args...)
+}
+
+func (r *Router) queryContext(ctx context.Context, db *sql.DB, query string, args ...any) (*Rows, error) {
+	if r.options.Driver == DriverMySQL {
+		return QueryMySQLContext(ctx, db, query, args...)
+	}
+	rows, err := db.QueryContext(ctx, query, args...) // lib/pq and sqlite cancel the query on the server when ctx is done
+	if err != nil {
+		return nil, err
+	}
+	return &Rows{Rows: rows}, nil
+}
+
+// CheckReplicas ping every replica and check its replication lag, replicas are marked healthy or unhealthy accordingly.
+func (r *Router) CheckReplicas(ctx context.Context) {
+	for _, replica := range r.replicas {
+		err := r.checkReplica(ctx, replica.db)
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) != healthy {
+			if healthy {
+				slog.InfoContext(ctx, "[dbconn.Router.CheckReplicas]:: replica is healthy.", slog.String("replica", replica.name))
+			} else {
+				slog.WarnContext(ctx, "[dbconn.Router.CheckReplicas]:: replica is unhealthy.", slog.String("replica", replica.name), logger.Err(err))
+			}
+		}
+	}
+}
+
+func (r *Router) checkReplica(ctx context.Context, db *sql.DB) error {
+	ctx, cancel := WithQueryTimeout(ctx, 0)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+	if r.options.MaxLag <= 0 {
+		return nil
+	}
+	replicaLag := MySQLReplicaLag
+	if r.options.Driver == DriverPostgres {
+		replicaLag = PostgresReplicaLag
+	}
+	lag, err := replicaLag(ctx, db)
+	if err != nil {
+		return err
+	}
+	if lag > r.options.MaxLag {
+		return fmt.Errorf("replica is %s behind primary", lag)
+	}
+	return nil
+}
+
+// Start health check replicas every check interval until Close.
+func (r *Router) Start() {
+	if len(r.replicas) == 0 {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.options.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.CheckReplicas(context.Background())
+			}
+		}
+	}()
+}
+
+// Close stop the health check and close the primary and every replica connection pool.
+func (r *Router) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	r.wg.Wait()
+	err := r.primary.Close()
+	for _, replica := range r.replicas {
+		if replicaErr := replica.db.Close(); replicaErr != nil && err == nil {
+			err = replicaErr
+		}
+	}
+	return err
+}
+
+// MySQLReplicaLag return how far a mysql replica is behind its source, zero when the server is not a replica.
+func MySQLReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, MySQLReplicaStatus)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+		if !values[i].Valid {
+			return 0, ErrReplicationStopped
+		}
+		seconds, err := strconv.ParseInt(values[i].String, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, nil
+}
+
+// PostgresReplicaLag return how far a postgresql standby is behind its primary from the last replayed transaction,
+// zero when the server is not a standby.
+func PostgresReplicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds float64
+	if err := db.QueryRowContext(ctx, PostgresReplicaStatus).Scan(&seconds); err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// isConnectionError report whether err happened while reaching the server rather than running the query.
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.As(err, &netErr)
+}
@@ -0,0 +1,104 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newReplicaMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, dbMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	return db, dbMock
+}
+
+func expectReplicaLag(dbMock sqlmock.Sqlmock, secondsBehindSource any) {
+	dbMock.ExpectPing()
+	dbMock.ExpectQuery(MySQLReplicaStatus).WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("Waiting for source to send event", secondsBehindSource))
+}
+
+func expectQuery(dbMock sqlmock.Sqlmock, connectionID int64) {
+	dbMock.ExpectQuery(regexp.QuoteMeta(MySQLConnectionID)).WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(connectionID))
+	dbMock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+}
+
+func TestRouter(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test query round-robin over replicas and skip lagging replica",
+			testFunction: func(t *testing.T) {
+				primary, primaryMock := newReplicaMock(t)
+				replicaA, replicaAMock := newReplicaMock(t)
+				replicaB, replicaBMock := newReplicaMock(t)
+				replicaC, replicaCMock := newReplicaMock(t)
+				expectReplicaLag(replicaAMock, "1")
+				expectReplicaLag(replicaBMock, "0")
+				expectReplicaLag(replicaCMock, "120")
+				router := NewRouter(primary, []Replica{{Name: "a", DB: replicaA}, {Name: "b", DB: replicaB}, {Name: "c", DB: replicaC}}, WithMaxReplicaLag(30*time.Second))
+				router.CheckReplicas(context.Background())
+				expectQuery(replicaBMock, 1)
+				expectQuery(replicaAMock, 2)
+				expectQuery(replicaAMock, 3)
+				for i := 0; i < 3; i++ {
+					rows, err := router.QueryContext(context.Background(), "SELECT 1")
+					assert.NoError(t, err)
+					assert.NoError(t, rows.Close())
+				}
+				for _, dbMock := range []sqlmock.Sqlmock{primaryMock, replicaAMock, replicaBMock, replicaCMock} {
+					assert.NoError(t, dbMock.ExpectationsWereMet())
+				}
+			},
+		},
+		{
+			name: "test query fail over into primary when replica is down",
+			testFunction: func(t *testing.T) {
+				primary, primaryMock := newReplicaMock(t)
+				replica, replicaMock := newReplicaMock(t)
+				replicaMock.ExpectPing()
+				router := NewRouter(primary, []Replica{{Name: "a", DB: replica}})
+				router.CheckReplicas(context.Background())
+				replicaMock.ExpectQuery(regexp.QuoteMeta(MySQLConnectionID)).WillReturnError(&net.OpError{Op: "dial", Net: "tcp", Err: net.UnknownNetworkError("down")})
+				expectQuery(primaryMock, 1)
+				expectQuery(primaryMock, 2)
+				for i := 0; i < 2; i++ { // the second query goes straight into primary
+					rows, err := router.QueryContext(context.Background(), "SELECT 1")
+					assert.NoError(t, err)
+					assert.NoError(t, rows.Close())
+				}
+				assert.NoError(t, primaryMock.ExpectationsWereMet())
+				assert.NoError(t, replicaMock.ExpectationsWereMet())
+			},
+		},
+		{
+			name: "test replica with stopped replication is unhealthy",
+			testFunction: func(t *testing.T) {
+				primary, primaryMock := newReplicaMock(t)
+				replica, replicaMock := newReplicaMock(t)
+				expectReplicaLag(replicaMock, nil)
+				router := NewRouter(primary, []Replica{{Name: "a", DB: replica}}, WithMaxReplicaLag(30*time.Second))
+				router.CheckReplicas(context.Background())
+				expectQuery(primaryMock, 1)
+				rows, err := router.QueryContext(context.Background(), "SELECT 1")
+				assert.NoError(t, err)
+				assert.NoError(t, rows.Close())
+				assert.NoError(t, primaryMock.ExpectationsWereMet())
+				assert.NoError(t, replicaMock.ExpectationsWereMet())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -1,14 +1,28 @@
 package dbconn
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"strings"
 	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/secret"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-	_ "github.com/lib/pq"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// database drivers supported by config database driver
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 const (
@@ -19,40 +33,162 @@ const (
 	DefaultTimeout  = 2000
 )
 
-func NewMySQLDBConn(dbConfig *config.Database) (*sql.DB, error) {
-	dbConn, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-		dbConfig.DBUsername,
-		dbConfig.DBPassword,
-		dbConfig.DBHost,
-		dbConfig.DBPort,
-		dbConfig.DBName,
-	))
-	if err != nil {
-		log.Fatal("[dbconn.NewMySQLDBConn]:: error opening connection into source mysql")
+type ConnOptions struct {
+	Secrets *secret.Store
+}
+
+type ConnOption func(*ConnOptions)
+
+// WithSecrets resolve secret:<name> database passwords from secrets, the password is resolved on every new connection
+// so connections replacing the expired ones (connMaxLifetime) use a rotated password.
+func WithSecrets(secrets *secret.Store) ConnOption {
+	return func(options *ConnOptions) {
+		options.Secrets = secrets
 	}
+}
+
+func newConnOptions(opts []ConnOption) ConnOptions {
+	options := ConnOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func NewMySQLDBConn(dbConfig *config.Database, opts ...ConnOption) (*sql.DB, error) {
+	dbConn := openMySQLDBConn(dbConfig, newConnOptions(opts))
+	if err := dbConn.Ping(); err != nil {
+		slog.Error("[dbconn.NewMySQLDBConn]:: error pinging connection", logger.Err(err))
+		dbConn.Close()
+		return nil, err
+	}
+	return dbConn, nil
+}
+
+// openMySQLDBConn open a connection pool without connecting, connections are made on first use.
+func openMySQLDBConn(dbConfig *config.Database, options ConnOptions) *sql.DB {
+	database := *dbConfig
+	dbConn := sql.OpenDB(&passwordConnector{
+		driver:   &mysql.MySQLDriver{},
+		password: func(ctx context.Context) (string, error) { return options.Secrets.Resolve(ctx, database.DBPassword) },
+		dsn: func(password string) string {
+			mysqlConfig := mysql.NewConfig()
+			mysqlConfig.User = database.DBUsername
+			mysqlConfig.Passwd = password
+			mysqlConfig.Net = "tcp"
+			mysqlConfig.Addr = net.JoinHostPort(database.DBHost, database.DBPort)
+			mysqlConfig.DBName = database.DBName
+			return mysqlConfig.FormatDSN()
+		},
+		connector: func(dsn string) (driver.Connector, error) { return (&mysql.MySQLDriver{}).OpenConnector(dsn) },
+	})
 	setSQLDBConn(dbConn)
+	return dbConn
+}
+
+// NewSQLiteDBConn open the sqlite database file at database name, eg: to run the pipeline locally.
+func NewSQLiteDBConn(dbConfig *config.Database) (*sql.DB, error) {
+	dbConn, err := openSQLiteDBConn(dbConfig)
+	if err != nil {
+		slog.Error("[dbconn.NewSQLiteDBConn]:: error opening sqlite database", logger.Err(err))
+		return nil, err
+	}
 	if err = dbConn.Ping(); err != nil {
-		log.Fatal("[dbconn.NewMySQLDBConn]:: error pinging connection")
+		slog.Error("[dbconn.NewSQLiteDBConn]:: error pinging connection", logger.Err(err))
+		dbConn.Close()
+		return nil, err
 	}
 	return dbConn, nil
 }
 
-func NewPostgreSQLDBConn(dbConfig *config.Database) (*sql.DB, error) {
-	dbConn, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		dbConfig.DBHost,
-		dbConfig.DBPort,
-		dbConfig.DBUsername,
-		dbConfig.DBPassword,
-		dbConfig.DBName,
-	))
-	setSQLDBConn(dbConn)
+func openSQLiteDBConn(dbConfig *config.Database) (*sql.DB, error) {
+	dbConn, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)", dbConfig.DBName, DefaultTimeout))
 	if err != nil {
-		log.Println("[dbconn.NewPostgreSQLDBConn]:: error opening connection into source postgresql")
 		return nil, err
 	}
+	setSQLDBConn(dbConn)
 	return dbConn, nil
 }
 
+func NewPostgreSQLDBConn(dbConfig *config.Database, opts ...ConnOption) (*sql.DB, error) {
+	return openPostgreSQLDBConn(dbConfig, newConnOptions(opts)), nil
+}
+
+func openPostgreSQLDBConn(dbConfig *config.Database, options ConnOptions) *sql.DB {
+	database := *dbConfig
+	dbConn := sql.OpenDB(&passwordConnector{
+		driver:   &pq.Driver{},
+		password: func(ctx context.Context) (string, error) { return options.Secrets.Resolve(ctx, database.DBPassword) },
+		dsn: func(password string) string {
+			return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+				quotePostgres(database.DBHost),
+				quotePostgres(database.DBPort),
+				quotePostgres(database.DBUsername),
+				quotePostgres(password),
+				quotePostgres(database.DBName),
+			)
+		},
+		connector: func(dsn string) (driver.Connector, error) { return pq.NewConnector(dsn) },
+	})
+	setSQLDBConn(dbConn)
+	return dbConn
+}
+
+// quotePostgres quote a connection string value, so passwords with spaces or quotes are kept as is.
+func quotePostgres(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// openDBConn open a connection pool of driver without connecting, connections are made on first use.
+func openDBConn(driver string, dbConfig *config.Database, options ConnOptions) (*sql.DB, error) {
+	switch driver {
+	case DriverMySQL:
+		return openMySQLDBConn(dbConfig, options), nil
+	case DriverPostgres:
+		return openPostgreSQLDBConn(dbConfig, options), nil
+	case DriverSQLite:
+		return openSQLiteDBConn(dbConfig)
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
+
+// NewServiceDBConn connect into service database with dbConfig driver, postgres when not set.
+func NewServiceDBConn(dbConfig *config.Database, opts ...ConnOption) (*sql.DB, error) {
+	switch dbConfig.Driver {
+	case "", DriverPostgres:
+		return NewPostgreSQLDBConn(dbConfig, opts...)
+	case DriverMySQL:
+		return NewMySQLDBConn(dbConfig, opts...)
+	case DriverSQLite:
+		return NewSQLiteDBConn(dbConfig)
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", dbConfig.Driver)
+}
+
+// passwordConnector build the dsn with the current password on every new connection.
+type passwordConnector struct {
+	driver    driver.Driver
+	password  func(ctx context.Context) (string, error)
+	dsn       func(password string) string
+	connector func(dsn string) (driver.Connector, error)
+}
+
+func (pc *passwordConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	password, err := pc.password(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := pc.connector(pc.dsn(password))
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+func (pc *passwordConnector) Driver() driver.Driver {
+	return pc.driver
+}
+
 func setSQLDBConn(sqlConn *sql.DB) {
 	sqlConn.SetMaxOpenConns(maxOpenConns)
 	sqlConn.SetConnMaxLifetime(connMaxLifetime)
@@ -62,32 +198,32 @@ func setSQLDBConn(sqlConn *sql.DB) {
 
 type TransactionOption func(*sql.Tx) error
 
-func WithTransaction(db *sql.DB, fns ...TransactionOption) error {
-	tx, err := db.Begin()
+func WithTransaction(ctx context.Context, db *sql.DB, fns ...TransactionOption) error {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Println("[dbConn.WithTransaction]:: error starting transaction")
+		slog.ErrorContext(ctx, "[dbConn.WithTransaction]:: error starting transaction", logger.Err(err))
 		return err
 	}
 
 	defer func() {
 		if r := recover(); r != nil {
 			if err = tx.Rollback(); err != nil {
-				log.Println("[dbConn.WithTransaction]:: error recovering, rollback transaction")
+				slog.ErrorContext(ctx, "[dbConn.WithTransaction]:: error recovering, rollback transaction", logger.Err(err))
 			}
 		} else if err != nil {
 			if err = tx.Rollback(); err != nil {
-				log.Println("[dbConn.WithTransaction]:: error on rollback transaction")
+				slog.ErrorContext(ctx, "[dbConn.WithTransaction]:: error on rollback transaction", logger.Err(err))
 			}
 		} else {
 			if err = tx.Commit(); err != nil {
-				log.Println("[dbConn.WithTransaction]:: error commit transaction")
+				slog.ErrorContext(ctx, "[dbConn.WithTransaction]:: error commit transaction", logger.Err(err))
 			}
 		}
 	}()
 
 	for _, fn := range fns {
 		if err := fn(tx); err != nil {
-			log.Println("[dbConn.WithTransaction]:: error")
+			slog.ErrorContext(ctx, "[dbConn.WithTransaction]:: error", logger.Err(err))
 			return err
 		}
 	}
@@ -0,0 +1,80 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/secret"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSecretProvider map[string]string
+
+func (sp stubSecretProvider) Get(ctx context.Context, name string) (string, error) {
+	return sp[name], nil
+}
+
+type stubConnector struct {
+	dsn string
+}
+
+func (sc *stubConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return nil, errors.New(sc.dsn)
+}
+
+func (sc *stubConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestPasswordConnector(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test new connection use rotated secret password",
+			testFunction: func(t *testing.T) {
+				provider := stubSecretProvider{"db": "v1"}
+				secrets := secret.NewStore(provider, 0)
+				connector := &passwordConnector{
+					password:  func(ctx context.Context) (string, error) { return secrets.Resolve(ctx, "secret:db") },
+					dsn:       func(password string) string { return "password=" + password },
+					connector: func(dsn string) (driver.Connector, error) { return &stubConnector{dsn: dsn}, nil },
+				}
+				_, err := connector.Connect(context.Background())
+				assert.EqualError(t, err, "password=v1")
+				provider["db"] = "v2"
+				secrets.Refresh(context.Background())
+				_, err = connector.Connect(context.Background())
+				assert.EqualError(t, err, "password=v2")
+			},
+		},
+		{
+			name: "test open connection with secret password and driver of dialect",
+			testFunction: func(t *testing.T) {
+				database := &config.Database{DBUsername: "root", DBPassword: "secret:db", DBHost: "127.0.0.1", DBPort: "3306", DBName: "source"}
+				dbConn := openMySQLDBConn(database, ConnOptions{Secrets: secret.NewStore(stubSecretProvider{"db": "p@ss:w/rd"}, 0)})
+				defer dbConn.Close()
+				assert.Equal(t, DriverMySQL, DialectOf(dbConn).Driver)
+				dbConn = openPostgreSQLDBConn(database, ConnOptions{})
+				defer dbConn.Close()
+				assert.Equal(t, DriverPostgres, DialectOf(dbConn).Driver)
+			},
+		},
+		{
+			name: "test quote postgres connection string value",
+			testFunction: func(t *testing.T) {
+				assert.Equal(t, `'it\'s a \\ pass'`, quotePostgres(`it's a \ pass`))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"syscall"
+	"tax-aggregator-service-demo/pkg/logger"
+	"time"
+)
+
+// exit codes of the process
+const (
+	ExitOK       = 0
+	ExitFailure  = 1 // a component failed to start or a command failed
+	ExitRuntime  = 2 // a running component failed, eg: the server stopped listening
+	ExitShutdown = 3 // a component was not stopped cleanly within the shutdown timeout
+)
+
+// default time given to stop every component, eg: to drain in-flight requests and running jobs
+const DefaultShutdownTimeout = 120 * time.Second
+
+// Signals stop the process gracefully, SIGTERM is sent by docker and kubernetes.
+var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ExitError carry the exit code of err.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode return the exit code of err, ExitFailure when err doesn't carry one.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
+
+// Component is started and stopped by the Manager, start must return once the component is running,
+// long running work is done in the background and report its failure with Manager.Fail.
+// stop must return once the component is stopped or ctx is done.
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+type ManagerOptions struct {
+	ShutdownTimeout time.Duration
+}
+
+type ManagerOption func(*ManagerOptions)
+
+// WithShutdownTimeout bound the time given to stop every component, DefaultShutdownTimeout when not set.
+func WithShutdownTimeout(shutdownTimeout time.Duration) ManagerOption {
+	return func(options *ManagerOptions) {
+		if shutdownTimeout > 0 {
+			options.ShutdownTimeout = shutdownTimeout
+		}
+	}
+}
+
+// Manager start components in the order they are added and stop them in reverse order,
+// so a component is only stopped after every component depending on it.
+type Manager struct {
+	options    ManagerOptions
+	components []Component
+	failures   chan error
+}
+
+func NewManager(opts ...ManagerOption) *Manager {
+	options := ManagerOptions{
+		ShutdownTimeout: DefaultShutdownTimeout,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Manager{
+		options:  options,
+		failures: make(chan error, 1),
+	}
+}
+
+// Add component after the components it depends on.
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// Fail report the failure of a running component, the manager stop every component. only the first failure is kept.
+func (m *Manager) Fail(name string, err error) {
+	select {
+	case m.failures <- fmt.Errorf("%s: %w", name, err):
+	default:
+	}
+}
+
+// Run start every component then wait until ctx is done, eg: on Signals, or a component failed,
+// and stop the started components within the shutdown timeout. the returned error carry the exit code:
+// ExitFailure when a component failed to start, ExitRuntime when a component failed while running
+// and ExitShutdown when a component was not stopped cleanly.
+func (m *Manager) Run(ctx context.Context) error {
+	var runErr error
+	started := 0
+	for _, component := range m.components {
+		if component.Start != nil {
+			if err := component.Start(ctx); err != nil {
+				slog.ErrorContext(ctx, "[lifecycle.Manager.Run]:: error starting component.", slog.String("component", component.Name), logger.Err(err))
+				runErr = &ExitError{Code: ExitFailure, Err: fmt.Errorf("%s: %w", component.Name, err)}
+				break
+			}
+		}
+		started++
+	}
+	if runErr == nil {
+		slog.InfoContext(ctx, "[lifecycle.Manager.Run]:: every component started.", slog.Int("components", started))
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "[lifecycle.Manager.Run]:: shutting down.", slog.Duration("timeout", m.options.ShutdownTimeout))
+		case err := <-m.failures:
+			slog.ErrorContext(ctx, "[lifecycle.Manager.Run]:: component failed, shutting down.", logger.Err(err))
+			runErr = &ExitError{Code: ExitRuntime, Err: err}
+		}
+	}
+	stopErr := m.stop(m.components[:started])
+	if runErr != nil {
+		return errors.Join(runErr, stopErr)
+	}
+	if stopErr != nil {
+		return &ExitError{Code: ExitShutdown, Err: stopErr}
+	}
+	return nil
+}
+
+// stop components in reverse order, every component is stopped even when a previous one failed or the timeout passed.
+func (m *Manager) stop(components []Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.options.ShutdownTimeout)
+	defer cancel()
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		component := components[i]
+		if component.Stop == nil {
+			continue
+		}
+		start := time.Now()
+		if err := component.Stop(ctx); err != nil {
+			slog.Error("[lifecycle.Manager.stop]:: error stopping component.", slog.String("component", component.Name), logger.Err(err))
+			errs = append(errs, fmt.Errorf("%s: %w", component.Name, err))
+			continue
+		}
+		slog.Info("[lifecycle.Manager.stop]:: component stopped.", slog.String("component", component.Name), slog.Duration("duration", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
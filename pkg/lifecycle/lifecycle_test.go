@@ -0,0 +1,100 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_Run(t *testing.T) {
+	// component record its start and stop into events
+	component := func(events *[]string, name string, startErr, stopErr error) Component {
+		return Component{
+			Name: name,
+			Start: func(ctx context.Context) error {
+				*events = append(*events, "start "+name)
+				return startErr
+			},
+			Stop: func(ctx context.Context) error {
+				*events = append(*events, "stop "+name)
+				return stopErr
+			},
+		}
+	}
+	errComponent := errors.New("component error")
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test start in order and stop in reverse order on shutdown",
+			testFunction: func(t *testing.T) {
+				events := []string{}
+				manager := NewManager()
+				manager.Add(component(&events, "database", nil, nil))
+				manager.Add(component(&events, "scheduler", nil, nil))
+				manager.Add(component(&events, "server", nil, nil))
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				err := manager.Run(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, ExitOK, ExitCode(err))
+				assert.Equal(t, []string{"start database", "start scheduler", "start server", "stop server", "stop scheduler", "stop database"}, events)
+			},
+		},
+		{
+			name: "test stop started components when a component fail to start",
+			testFunction: func(t *testing.T) {
+				events := []string{}
+				manager := NewManager()
+				manager.Add(component(&events, "database", nil, nil))
+				manager.Add(component(&events, "server", errComponent, nil))
+				manager.Add(component(&events, "admin", nil, nil))
+				err := manager.Run(context.Background())
+				assert.ErrorIs(t, err, errComponent)
+				assert.Equal(t, ExitFailure, ExitCode(err))
+				assert.Equal(t, []string{"start database", "start server", "stop database"}, events)
+			},
+		},
+		{
+			name: "test shutdown when a running component fail",
+			testFunction: func(t *testing.T) {
+				events := []string{}
+				manager := NewManager()
+				manager.Add(component(&events, "database", nil, nil))
+				manager.Add(Component{Name: "server", Start: func(ctx context.Context) error {
+					go manager.Fail("server", errComponent)
+					return nil
+				}})
+				err := manager.Run(context.Background())
+				assert.ErrorIs(t, err, errComponent)
+				assert.Equal(t, ExitRuntime, ExitCode(err))
+				assert.Equal(t, []string{"start database", "stop database"}, events)
+			},
+		},
+		{
+			name: "test stop every component within shutdown timeout",
+			testFunction: func(t *testing.T) {
+				events := []string{}
+				manager := NewManager(WithShutdownTimeout(10 * time.Millisecond))
+				manager.Add(component(&events, "database", nil, nil))
+				manager.Add(Component{Name: "server", Stop: func(ctx context.Context) error {
+					<-ctx.Done() // requests never drain
+					return ctx.Err()
+				}})
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				err := manager.Run(ctx)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.Equal(t, ExitShutdown, ExitCode(err))
+				assert.Equal(t, []string{"start database", "stop database"}, events)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
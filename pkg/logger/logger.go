@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// level of the default logger, swapped on config reload.
+var levelVar = new(slog.LevelVar)
+
+// Setup install the default slog logger writing format (json or text) at level into stderr,
+// the standard log package is redirected into it.
+func Setup(format, level string) error {
+	handler, err := NewHandler(os.Stderr, format, level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// NewHandler return a handler writing format into w, every record carry the request scoped attributes
+// added with With and the trace id of the span in the context.
+func NewHandler(w io.Writer, format, level string) (slog.Handler, error) {
+	if err := SetLevel(level); err != nil {
+		return nil, err
+	}
+	options := &slog.HandlerOptions{Level: levelVar}
+	if format == "text" {
+		return &contextHandler{Handler: slog.NewTextHandler(w, options)}, nil
+	}
+	return &contextHandler{Handler: slog.NewJSONHandler(w, options)}, nil
+}
+
+// SetLevel change the level of the handlers created by NewHandler.
+func SetLevel(level string) error {
+	var newLevel slog.Level
+	if err := newLevel.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	levelVar.Set(newLevel)
+	return nil
+}
+
+// Level return the current level, eg: info.
+func Level() string {
+	return strings.ToLower(levelVar.Level().String())
+}
+
+// Err attach err to a record under the error key.
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+type attrsKey struct{}
+
+// With return a copy of ctx carrying attributes added to every record logged with it.
+func With(ctx context.Context, args ...any) context.Context {
+	attrs := append([]slog.Attr{}, attrsOf(ctx)...)
+	record := slog.Record{}
+	record.Add(args...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func attrsOf(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (ch *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(attrsOf(ctx)...)
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+	return ch.Handler.Handle(ctx, record)
+}
+
+func (ch *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: ch.Handler.WithAttrs(attrs)}
+}
+
+func (ch *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: ch.Handler.WithGroup(name)}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestLogger install a json default logger writing into the returned buffer, the previous one is restored on cleanup.
+func newTestLogger(t *testing.T, level string) *bytes.Buffer {
+	previous := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		SetLevel("info")
+	})
+	buffer := &bytes.Buffer{}
+	handler, err := NewHandler(buffer, "json", level)
+	assert.NoError(t, err)
+	slog.SetDefault(slog.New(handler))
+	return buffer
+}
+
+func decodeRecords(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	records := []map[string]any{}
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		record := map[string]any{}
+		assert.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test passed when context attributes and error are attached",
+			testFunction: func(t *testing.T) {
+				buffer := newTestLogger(t, "info")
+				ctx := With(context.Background(), "request_id", "abc")
+				ctx = With(ctx, slog.String("tenant", "exchange"))
+				slog.ErrorContext(ctx, "[Test]:: error.", Err(errors.New("source database down")))
+
+				records := decodeRecords(t, buffer)
+				assert.Len(t, records, 1)
+				assert.Equal(t, "abc", records[0]["request_id"])
+				assert.Equal(t, "exchange", records[0]["tenant"])
+				assert.Equal(t, "source database down", records[0]["error"])
+			},
+		},
+		{
+			name: "test passed when records below level are dropped until level is lowered",
+			testFunction: func(t *testing.T) {
+				buffer := newTestLogger(t, "warn")
+				slog.Info("[Test]:: dropped.")
+				assert.NoError(t, SetLevel("debug"))
+				slog.Debug("[Test]:: kept.")
+
+				records := decodeRecords(t, buffer)
+				assert.Len(t, records, 1)
+				assert.Equal(t, "[Test]:: kept.", records[0]["msg"])
+			},
+		},
+		{
+			name: "test failed when level is unknown",
+			testFunction: func(t *testing.T) {
+				_, err := NewHandler(&bytes.Buffer{}, "text", "verbose")
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	buffer := newTestLogger(t, "info")
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/tax", func(ctx echo.Context) error {
+		slog.InfoContext(ctx.Request().Context(), "[Test]:: handler.")
+		return ctx.NoContent(http.StatusOK)
+	})
+	request := httptest.NewRequest(http.MethodGet, "/tax?start_date=1648746000&amount_of_days=30", nil)
+	request.Header.Set(RequestIDHeader, "abc")
+	request.Header.Set(TenantHeader, "exchange")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, "abc", rec.Header().Get(RequestIDHeader))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tax", nil))
+	assert.Len(t, rec.Header().Get(RequestIDHeader), 32)
+
+	records := decodeRecords(t, buffer)
+	assert.Len(t, records, 4)
+	for _, record := range records[:2] {
+		assert.Equal(t, "abc", record["request_id"])
+		assert.Equal(t, "exchange", record["tenant"])
+		assert.Equal(t, "1648746000", record["start_date"])
+		assert.Equal(t, "30", record["amount_of_days"])
+	}
+	assert.Equal(t, "/tax", records[1]["route"])
+	assert.Equal(t, float64(http.StatusOK), records[1]["status"])
+}
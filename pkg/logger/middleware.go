@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headers set by the monolith, the request id is generated when missing and echoed on the response.
+const (
+	RequestIDHeader = "X-Request-ID"
+	TenantHeader    = "X-Tenant-ID"
+)
+
+// query params of the requested range added to every record of the request.
+var rangeParams = []string{"start_date", "end_date", "amount_of_days"}
+
+// Middleware add the request id, tenant and requested range into the request context logger,
+// and write an access log after every request.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			request := ctx.Request()
+			requestID := request.Header.Get(RequestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+			}
+			ctx.Response().Header().Set(RequestIDHeader, requestID)
+			args := []any{slog.String("request_id", requestID)}
+			if tenant := request.Header.Get(TenantHeader); tenant != "" {
+				args = append(args, slog.String("tenant", tenant))
+			}
+			for _, param := range rangeParams {
+				if value := ctx.QueryParam(param); value != "" {
+					args = append(args, slog.String(param, value))
+				}
+			}
+			requestCtx := With(request.Context(), args...)
+			ctx.SetRequest(request.WithContext(requestCtx))
+
+			err := next(ctx)
+			status := ctx.Response().Status
+			if err != nil && !ctx.Response().Committed {
+				status = http.StatusInternalServerError
+				if httpErr, ok := err.(*echo.HTTPError); ok {
+					status = httpErr.Code
+				}
+			}
+			attrs := []slog.Attr{
+				slog.String("method", request.Method),
+				slog.String("route", ctx.Path()),
+				slog.String("path", request.URL.Path),
+				slog.Int("status", status),
+				slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+				slog.Int64("bytes_out", ctx.Response().Size),
+				slog.String("remote_ip", ctx.RealIP()),
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			if err != nil {
+				attrs = append(attrs, Err(err))
+			}
+			slog.LogAttrs(requestCtx, level, "[logger.Middleware]:: access.", attrs...)
+			return err
+		}
+	}
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(bytes)
+}
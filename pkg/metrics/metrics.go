@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tax_aggregator"
+
+// reasons of RateLimited
+const (
+	RateLimitReasonClient             = "client"
+	RateLimitReasonSourceQueueFull    = "source_queue_full"
+	RateLimitReasonSourceQueueTimeout = "source_queue_timeout"
+)
+
+// Registry hold every metric of the service, exposed by Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"method", "route"})
+
+	RepositoryQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_query_duration_seconds",
+		Help:      "TaxRepository call latency by method, including retries.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"method"})
+
+	RepositoryQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "repository_query_errors_total",
+		Help:      "TaxRepository call errors by method.",
+	}, []string{"method"})
+
+	TaxResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tax_responses_total",
+		Help:      "GetTax responses by data source (cache is service database, source is source database) and result cache status.",
+	}, []string{"source", "cache"})
+
+	DaysInserted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tax_transaction_days_inserted_total",
+		Help:      "Days written, inserted or updated, into tax_transaction by InsertTaxTransactions.",
+	})
+
+	SourceFetchesCoalesced = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_fetches_coalesced_total",
+		Help:      "Source fetches of GetTax waiting for a concurrent request computing the same days instead of querying source database.",
+	})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requests rejected by the per client rate limit or the source fetch limit, by reason.",
+	}, []string{"reason"})
+
+	AggregationWatermark = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "aggregation_watermark_seconds",
+		Help:      "Unix time of the last day successfully aggregated into tax_transaction.",
+	}, []string{"name"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RepositoryQueryDuration,
+		RepositoryQueryErrors,
+		TaxResponses,
+		DaysInserted,
+		SourceFetchesCoalesced,
+		RateLimited,
+		AggregationWatermark,
+	)
+}
+
+// RegisterDB expose the sql.DBStats pool gauges of db labelled with name, eg: source or service.
+func RegisterDB(name string, db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serve the metrics in prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
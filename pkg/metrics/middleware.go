@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware count requests and observe their latency by the route template, so path params don't grow the label set.
+// requests matching no route are labelled with an empty route.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			err := next(ctx)
+			code := ctx.Response().Status
+			if err != nil && !ctx.Response().Committed { // the error is written by the echo error handler afterward
+				code = http.StatusInternalServerError
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					code = httpErr.Code
+				}
+			}
+			route := ctx.Path()
+			method := ctx.Request().Method
+			HTTPRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+			HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
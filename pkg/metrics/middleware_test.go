@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/jobs/:name", func(ctx echo.Context) error {
+		if ctx.Param("name") == "missing" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return ctx.NoContent(http.StatusOK)
+	})
+	e.GET("/metrics", echo.WrapHandler(Handler()))
+	for _, path := range []string{"/jobs/a", "/jobs/b", "/jobs/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, "/jobs/:name", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(HTTPRequests.WithLabelValues(http.MethodGet, "/jobs/:name", "404")))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), `tax_aggregator_http_request_duration_seconds_count{method="GET",route="/jobs/:name"} 3`))
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idle clients are forgotten after their bucket is full again, swept at most once per sweep interval
+const sweepInterval = time.Minute
+
+// Limiter is a token bucket per client, every client can make requests per second with bursts up to burst requests.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	idleTTL   time.Duration
+	clients   map[string]*client
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter return nil when requests per second is not set, a nil limiter allow every request.
+// burst default to requests per second rounded up.
+func NewLimiter(requestsPerSecond float64, burst int) *Limiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	return &Limiter{
+		limit:   rate.Limit(requestsPerSecond),
+		burst:   burst,
+		idleTTL: max(sweepInterval, time.Duration(float64(burst)/requestsPerSecond*float64(time.Second))),
+		clients: map[string]*client{},
+		now:     time.Now,
+	}
+}
+
+// Allow take a token of the client bucket, when the bucket is empty it return false and how long until a token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > l.idleTTL {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
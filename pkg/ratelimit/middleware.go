@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"tax-aggregator-service-demo/pkg/auth"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware reject requests of a client over its rate with 429 and Retry-After, the client is the authenticated
+// subject or the client ip when the request is not authenticated. it must run after auth.Require to see the subject,
+// and the echo instance must use IPExtractor, the echo default trust any X-Forwarded-For header.
+// every request pass through when the limiter is nil.
+func (l *Limiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if l == nil {
+				return next(ctx)
+			}
+			client := ctx.RealIP()
+			if claims, ok := auth.FromContext(ctx.Request().Context()); ok && claims.Subject != "" {
+				client = claims.Subject
+			}
+			allowed, retryAfter := l.Allow(client)
+			if !allowed {
+				slog.WarnContext(ctx.Request().Context(), "[ratelimit.Middleware]:: client is rate limited.", slog.String("client", client), slog.Duration("retry_after", retryAfter))
+				metrics.RateLimited.WithLabelValues(metrics.RateLimitReasonClient).Inc()
+				SetRetryAfter(ctx.Response().Header(), retryAfter)
+				return ctx.JSON(http.StatusTooManyRequests, echo.Map{"code": http.StatusTooManyRequests, "message": "rate limit exceeded"})
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// IPExtractor return the echo ip extractor of the client ip limited by Middleware. the peer address is the client,
+// unless the peer is one of trustedProxies (CIDRs), then X-Forwarded-For is read back to the first untrusted address.
+// X-Forwarded-For and X-Real-IP of any other peer are ignored, so a client can't get a new bucket by spoofing them.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, trustedProxy := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(trustedProxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", trustedProxy, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// SetRetryAfter set the Retry-After header in seconds, rounded up.
+func SetRetryAfter(header http.Header, retryAfter time.Duration) {
+	header.Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+}
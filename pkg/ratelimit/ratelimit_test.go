@@ -0,0 +1,207 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"tax-aggregator-service-demo/pkg/auth"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test allow burst then refill per client",
+			testFunction: func(t *testing.T) {
+				now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+				limiter := NewLimiter(1, 2)
+				limiter.now = func() time.Time { return now }
+				for i := 0; i < 2; i++ {
+					allowed, _ := limiter.Allow("monolith")
+					assert.True(t, allowed)
+				}
+				allowed, retryAfter := limiter.Allow("monolith")
+				assert.False(t, allowed)
+				assert.Equal(t, time.Second, retryAfter)
+				allowed, _ = limiter.Allow("reporting") // other clients have their own bucket
+				assert.True(t, allowed)
+
+				now = now.Add(time.Second)
+				allowed, _ = limiter.Allow("monolith")
+				assert.True(t, allowed)
+			},
+		},
+		{
+			name: "test forget idle clients",
+			testFunction: func(t *testing.T) {
+				now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+				limiter := NewLimiter(10, 0)
+				limiter.now = func() time.Time { return now }
+				limiter.Allow("monolith")
+				now = now.Add(2 * sweepInterval)
+				limiter.Allow("reporting")
+				assert.Len(t, limiter.clients, 1)
+			},
+		},
+		{
+			name: "test nil limiter allow every request",
+			testFunction: func(t *testing.T) {
+				limiter := NewLimiter(0, 10)
+				assert.Nil(t, limiter)
+				allowed, _ := limiter.Allow("monolith")
+				assert.True(t, allowed)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test queued caller take the released slot",
+			testFunction: func(t *testing.T) {
+				semaphore := NewSemaphore(1, 1, time.Minute)
+				release, err := semaphore.Acquire(context.Background())
+				assert.NoError(t, err)
+				acquired := make(chan error)
+				go func() {
+					release, err := semaphore.Acquire(context.Background())
+					if err == nil {
+						release()
+					}
+					acquired <- err
+				}()
+				assert.Eventually(t, func() bool { _, queued := semaphore.InFlight(); return queued == 1 }, time.Second, time.Millisecond)
+				_, err = semaphore.Acquire(context.Background())
+				assert.ErrorIs(t, err, ErrQueueFull)
+				release()
+				assert.NoError(t, <-acquired)
+			},
+		},
+		{
+			name: "test queued caller time out with retry after",
+			testFunction: func(t *testing.T) {
+				semaphore := NewSemaphore(1, 1, 10*time.Millisecond)
+				_, err := semaphore.Acquire(context.Background())
+				assert.NoError(t, err)
+				_, err = semaphore.Acquire(context.Background())
+				assert.ErrorIs(t, err, ErrQueueTimeout)
+				retryAfter, ok := RetryAfter(err)
+				assert.True(t, ok)
+				assert.Equal(t, time.Second, retryAfter)
+			},
+		},
+		{
+			name: "test queued caller stop waiting when context is done",
+			testFunction: func(t *testing.T) {
+				semaphore := NewSemaphore(1, 1, 0)
+				_, err := semaphore.Acquire(context.Background())
+				assert.NoError(t, err)
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				_, err = semaphore.Acquire(ctx)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				_, ok := RetryAfter(err)
+				assert.False(t, ok)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	limiter := NewLimiter(1, 1)
+	e := echo.New()
+	e.GET("/tax", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error { // stand in for auth.Require
+			if subject := ctx.Request().Header.Get("X-Subject"); subject != "" {
+				ctx.SetRequest(ctx.Request().WithContext(auth.WithClaims(ctx.Request().Context(), &auth.Claims{Subject: subject})))
+			}
+			return next(ctx)
+		}
+	}, limiter.Middleware())
+	serve := func(subject string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/tax", nil)
+		request.Header.Set("X-Subject", subject)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+	assert.Equal(t, http.StatusOK, serve("monolith").Code)
+	rec := serve("monolith")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Equal(t, http.StatusOK, serve("reporting").Code)
+	assert.Equal(t, http.StatusOK, serve("").Code) // unauthenticated requests are limited by client ip
+	assert.Equal(t, http.StatusTooManyRequests, serve("").Code)
+}
+
+func TestLimiter_Middleware_ClientIP(t *testing.T) {
+	// serve send a request of the peer remoteAddr with X-Forwarded-For forwardedFor through a limiter of burst 1
+	newServe := func(t *testing.T, trustedProxies []string) func(remoteAddr, forwardedFor string) int {
+		limiter := NewLimiter(1, 1)
+		e := echo.New()
+		var err error
+		e.IPExtractor, err = IPExtractor(trustedProxies)
+		assert.NoError(t, err)
+		e.GET("/tax", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }, limiter.Middleware())
+		return func(remoteAddr, forwardedFor string) int {
+			request := httptest.NewRequest(http.MethodGet, "/tax", nil)
+			request.RemoteAddr = remoteAddr
+			request.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, request)
+			return rec.Code
+		}
+	}
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test spoofed forwarded header doesn't get a new bucket",
+			testFunction: func(t *testing.T) {
+				serve := newServe(t, nil)
+				assert.Equal(t, http.StatusOK, serve("203.0.113.7:40000", "198.51.100.1"))
+				assert.Equal(t, http.StatusTooManyRequests, serve("203.0.113.7:40001", "198.51.100.2"))
+				assert.Equal(t, http.StatusOK, serve("203.0.113.8:40000", "198.51.100.2"))
+			},
+		},
+		{
+			name: "test forwarded header is read only from trusted proxies",
+			testFunction: func(t *testing.T) {
+				serve := newServe(t, []string{"10.0.0.0/8"})
+				assert.Equal(t, http.StatusOK, serve("10.0.0.2:40000", "198.51.100.1"))
+				assert.Equal(t, http.StatusOK, serve("10.0.0.2:40001", "198.51.100.2")) // other client behind the proxy
+				assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.2:40002", "198.51.100.3, 198.51.100.1"))
+				assert.Equal(t, http.StatusOK, serve("203.0.113.7:40000", "198.51.100.4"))
+				assert.Equal(t, http.StatusTooManyRequests, serve("203.0.113.7:40001", "198.51.100.5"))
+			},
+		},
+		{
+			name: "test failed when trusted proxy is not a cidr",
+			testFunction: func(t *testing.T) {
+				_, err := IPExtractor([]string{"10.0.0.1"})
+				assert.Error(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
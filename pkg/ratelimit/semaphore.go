@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrQueueFull    = errors.New("too many requests waiting")
+	ErrQueueTimeout = errors.New("timed out waiting for a free slot")
+)
+
+// RetryAfterError is a rejection the caller can retry after retry after.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter return the retry after of err, false when err is not a RetryAfterError.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryAfterErr *RetryAfterError
+	if errors.As(err, &retryAfterErr) {
+		return retryAfterErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// Semaphore bound the number of callers running at once, up to queue size callers wait for queue timeout for a free slot,
+// the others are rejected right away.
+type Semaphore struct {
+	slots        chan struct{}
+	queued       atomic.Int64
+	queueSize    int64
+	queueTimeout time.Duration
+}
+
+// NewSemaphore return nil when size is not set, a nil semaphore never block. callers wait until their context is done
+// when queue timeout is not set.
+func NewSemaphore(size, queueSize int, queueTimeout time.Duration) *Semaphore {
+	if size <= 0 {
+		return nil
+	}
+	return &Semaphore{
+		slots:        make(chan struct{}, size),
+		queueSize:    int64(queueSize),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire take a slot, release must be called once the caller is done. ErrQueueFull or ErrQueueTimeout are returned
+// as RetryAfterError, the context error when ctx is done while waiting.
+func (s *Semaphore) Acquire(ctx context.Context) (release func(), err error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return s.release, nil
+	default:
+	}
+	if s.queued.Add(1) > s.queueSize {
+		s.queued.Add(-1)
+		return nil, &RetryAfterError{Err: ErrQueueFull, RetryAfter: s.retryAfter()}
+	}
+	defer s.queued.Add(-1)
+	var timeout <-chan time.Time
+	if s.queueTimeout > 0 {
+		timer := time.NewTimer(s.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return s.release, nil
+	case <-timeout:
+		return nil, &RetryAfterError{Err: ErrQueueTimeout, RetryAfter: s.retryAfter()}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InFlight return the number of slots taken and callers waiting.
+func (s *Semaphore) InFlight() (running, queued int) {
+	if s == nil {
+		return 0, 0
+	}
+	return len(s.slots), int(s.queued.Load())
+}
+
+func (s *Semaphore) release() {
+	<-s.slots
+}
+
+// a queue slot is expected to free up within the queue timeout
+func (s *Semaphore) retryAfter() time.Duration {
+	return max(s.queueTimeout, time.Second)
+}
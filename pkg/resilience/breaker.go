@@ -0,0 +1,111 @@
+package resilience
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"tax-aggregator-service-demo/pkg/logger"
+	"time"
+)
+
+// circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker stop calling a failing database, it opens after failure threshold consecutive failures,
+// reject every call for open timeout, then let a single probe call through and closes again when the probe succeed.
+// only errors reported by isFailure are counted, eg: a syntax error doesn't mean the database is down,
+// and only a successful probe closes it.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	isFailure        func(error) bool
+	state            string
+	failures         int
+	openedAt         time.Time
+	now              func() time.Time
+}
+
+func NewCircuitBreaker(name string, failureThreshold int, openTimeout time.Duration, isFailure func(error) bool) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		isFailure:        isFailure,
+		state:            StateClosed,
+		now:              time.Now,
+	}
+}
+
+// Do call fn unless the breaker is open, ErrCircuitOpen is returned without calling fn when it is.
+// the breaker is disabled when failure threshold is not set.
+func (cb *CircuitBreaker) Do(fn func() error) error {
+	if cb.failureThreshold <= 0 {
+		return fn()
+	}
+	if !cb.allow() {
+		return ErrCircuitOpen
+	}
+	err := fn()
+	cb.record(err)
+	return err
+}
+
+// State return the current state of the breaker.
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == StateOpen && !cb.now().Before(cb.openedAt.Add(cb.openTimeout)) {
+		return StateHalfOpen
+	}
+	return cb.state
+}
+
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case StateOpen:
+		if cb.now().Before(cb.openedAt.Add(cb.openTimeout)) {
+			return false
+		}
+		cb.state = StateHalfOpen // only the first call after open timeout probe the database
+		return true
+	case StateHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+func (cb *CircuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if err != nil && cb.isFailure(err) {
+		cb.failures++
+		if cb.state == StateHalfOpen || cb.failures >= cb.failureThreshold {
+			if cb.state != StateOpen {
+				slog.Warn("[CircuitBreaker.Do]:: circuit breaker opened.", slog.String("breaker", cb.name), slog.Int("failures", cb.failures), logger.Err(err))
+			}
+			cb.state = StateOpen
+			cb.openedAt = cb.now()
+		}
+		return
+	}
+	if err != nil && cb.state == StateHalfOpen { // the probe proved nothing, eg: it was cancelled, the next call probe again
+		cb.state = StateOpen
+		return
+	}
+	if cb.state == StateHalfOpen {
+		slog.Info("[CircuitBreaker.Do]:: circuit breaker closed.", slog.String("breaker", cb.name))
+	}
+	cb.state = StateClosed
+	cb.failures = 0
+}
@@ -0,0 +1,85 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	errTransient := errors.New("transient")
+	errQuery := errors.New("query")
+	isFailure := func(err error) bool { return errors.Is(err, errTransient) }
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test open after consecutive failures and fail fast",
+			testFunction: func(t *testing.T) {
+				breaker := NewCircuitBreaker("source database", 2, time.Minute, isFailure)
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				assert.ErrorIs(t, breaker.Do(func() error { return errQuery }), errQuery) // not counted, reset failures
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				assert.Equal(t, StateClosed, breaker.State())
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				assert.Equal(t, StateOpen, breaker.State())
+				called := false
+				assert.ErrorIs(t, breaker.Do(func() error { called = true; return nil }), ErrCircuitOpen)
+				assert.False(t, called)
+			},
+		},
+		{
+			name: "test single probe after open timeout close on success and reopen on failure",
+			testFunction: func(t *testing.T) {
+				now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+				breaker := NewCircuitBreaker("source database", 1, time.Minute, isFailure)
+				breaker.now = func() time.Time { return now }
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				now = now.Add(time.Minute)
+				assert.Equal(t, StateHalfOpen, breaker.State())
+				assert.ErrorIs(t, breaker.Do(func() error {
+					assert.ErrorIs(t, breaker.Do(func() error { return nil }), ErrCircuitOpen) // other calls during the probe
+					return errTransient
+				}), errTransient)
+				assert.Equal(t, StateOpen, breaker.State())
+				now = now.Add(time.Minute)
+				assert.NoError(t, breaker.Do(func() error { return nil }))
+				assert.Equal(t, StateClosed, breaker.State())
+			},
+		},
+		{
+			name: "test probe failing with an uncounted error doesn't close the breaker",
+			testFunction: func(t *testing.T) {
+				now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+				breaker := NewCircuitBreaker("source database", 1, time.Minute, isFailure)
+				breaker.now = func() time.Time { return now }
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				now = now.Add(time.Minute)
+				assert.ErrorIs(t, breaker.Do(func() error { return context.Canceled }), context.Canceled)
+				assert.Equal(t, StateHalfOpen, breaker.State()) // the next call probe again without waiting for open timeout
+				assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				assert.Equal(t, StateOpen, breaker.State())
+			},
+		},
+		{
+			name: "test disabled when failure threshold is not set",
+			testFunction: func(t *testing.T) {
+				breaker := NewCircuitBreaker("source database", 0, time.Minute, isFailure)
+				for i := 0; i < 5; i++ {
+					assert.ErrorIs(t, breaker.Do(func() error { return errTransient }), errTransient)
+				}
+				assert.Equal(t, StateClosed, breaker.State())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
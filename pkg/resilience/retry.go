@@ -0,0 +1,90 @@
+package resilience
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Backoff of Retry, the delay before retry n is a random duration up to min(max delay, base delay * 2^n).
+type Backoff struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Delay return the jittered delay before retry attempt, starting from 0.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.MaxDelay
+	if attempt < 32 && b.BaseDelay<<attempt > 0 && (b.MaxDelay <= 0 || b.BaseDelay<<attempt < b.MaxDelay) {
+		delay = b.BaseDelay << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// Retry call fn up to attempts times while it return a retryable error, waiting for the backoff delay in between.
+// the last error is returned, or the context error when ctx is done while waiting.
+func Retry(ctx context.Context, backoff Backoff, retryable func(error) bool, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil || attempt+1 >= backoff.Attempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		timer := time.NewTimer(backoff.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// mysql server errors worth retrying
+var retryableMySQLErrors = map[uint16]bool{
+	1040: true, // too many connections
+	1053: true, // server shutdown in progress
+	1205: true, // lock wait timeout exceeded
+	1213: true, // deadlock found
+	2006: true, // server has gone away
+	2013: true, // lost connection during query
+}
+
+// IsRetryable report whether err is a transient database error, eg: lost connection or deadlock.
+// context errors are never retryable, the caller gave up.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return retryableMySQLErrors[mysqlErr.Number]
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", "53", "57": // connection exception, insufficient resources, operator intervention
+			return true
+		}
+		return pqErr.Code == "40001" || pqErr.Code == "40P01" // serialization failure, deadlock
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff // primary result code
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
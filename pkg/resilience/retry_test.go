@@ -0,0 +1,78 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	backoff := Backoff{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	errTransient := &mysql.MySQLError{Number: 1213, Message: "deadlock found"}
+	tests := []struct {
+		name          string
+		errs          []error
+		expectedCalls int
+		expectedError error
+	}{
+		{
+			name:          "test retry transient error until success",
+			errs:          []error{errTransient, errTransient, nil},
+			expectedCalls: 3,
+		},
+		{
+			name:          "test return last error after attempts",
+			errs:          []error{errTransient, errTransient, errTransient, nil},
+			expectedCalls: 3,
+			expectedError: errTransient,
+		},
+		{
+			name:          "test not retry permanent error",
+			errs:          []error{&mysql.MySQLError{Number: 1064, Message: "syntax error"}, nil},
+			expectedCalls: 1,
+			expectedError: &mysql.MySQLError{Number: 1064, Message: "syntax error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), backoff, IsRetryable, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{err: &mysql.MySQLError{Number: 1205}, retryable: true},
+		{err: &mysql.MySQLError{Number: 1146}, retryable: false},
+		{err: fmt.Errorf("query: %w", mysql.ErrInvalidConn), retryable: true},
+		{err: &pq.Error{Code: "08006"}, retryable: true},
+		{err: &pq.Error{Code: "40P01"}, retryable: true},
+		{err: &pq.Error{Code: "23505"}, retryable: false},
+		{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, retryable: true},
+		{err: context.DeadlineExceeded, retryable: false},
+		{err: errors.New("sql: no rows in result set"), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+)
+
+// Locker elect a single leader per lock name across every replica.
+type Locker interface {
+	TryLock(name string) (bool, error)
+	Unlock(name string) error
+}
+
+// sessionLocker hold session level locks of the service database, every held lock pin one connection
+// from the pool so the lock is released by the database when the replica dies.
+type sessionLocker struct {
+	db          *sql.DB
+	lockQuery   string // return true when the lock was acquired
+	unlockQuery string
+	key         func(name string) any
+	mu          sync.Mutex
+	conns       map[string]*sql.Conn
+}
+
+// NewPostgresLocker use session level pg_try_advisory_lock on service database.
+func NewPostgresLocker(db *sql.DB) Locker {
+	return &sessionLocker{
+		db:          db,
+		lockQuery:   "SELECT pg_try_advisory_lock($1)",
+		unlockQuery: "SELECT pg_advisory_unlock($1)",
+		key:         func(name string) any { return LockKey(name) },
+		conns:       map[string]*sql.Conn{},
+	}
+}
+
+// NewMySQLLocker use GET_LOCK on service database, GET_LOCK return NULL on error which is reported as not locked.
+func NewMySQLLocker(db *sql.DB) Locker {
+	return &sessionLocker{
+		db:          db,
+		lockQuery:   "SELECT COALESCE(GET_LOCK(?, 0), 0)",
+		unlockQuery: "SELECT RELEASE_LOCK(?)",
+		key:         func(name string) any { return MySQLLockName(name) },
+		conns:       map[string]*sql.Conn{},
+	}
+}
+
+// LockKey hash the lock name into advisory lock key.
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// MySQLLockName prefix the lock name so it doesn't collide with locks of other applications sharing the server,
+// mysql lock names are limited to 64 characters.
+func MySQLLockName(name string) string {
+	lockName := "tax_aggregator." + name
+	if len(lockName) > 64 {
+		lockName = fmt.Sprintf("tax_aggregator.%x", uint64(LockKey(name)))
+	}
+	return lockName
+}
+
+// TryLock return true when the lock is held by this replica, a lock that is already held is verified by pinging its connection.
+func (sl *sessionLocker) TryLock(name string) (bool, error) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	ctx := context.Background()
+	if conn, ok := sl.conns[name]; ok {
+		if err := conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		slog.Warn("[scheduler.TryLock]:: lost connection holding lock.", slog.String("lock", name))
+		conn.Close()
+		delete(sl.conns, name)
+	}
+	conn, err := sl.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, sl.lockQuery, sl.key(name)).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+	sl.conns[name] = conn
+	return true, nil
+}
+
+func (sl *sessionLocker) Unlock(name string) error {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	conn, ok := sl.conns[name]
+	if !ok {
+		return nil
+	}
+	delete(sl.conns, name)
+	defer conn.Close()
+	_, err := conn.ExecContext(context.Background(), sl.unlockQuery, sl.key(name))
+	return err
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLLocker(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test lock is held until unlock",
+			testFunction: func(t *testing.T) {
+				db, dbMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				dbMock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(GET_LOCK(?, 0), 0)")).WithArgs("tax_aggregator.daily_aggregation").
+					WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(1))
+				dbMock.ExpectPing()
+				dbMock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs("tax_aggregator.daily_aggregation").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				locker := NewMySQLLocker(db)
+				locked, err := locker.TryLock("daily_aggregation")
+				assert.NoError(t, err)
+				assert.True(t, locked)
+				locked, err = locker.TryLock("daily_aggregation") // already held, only the connection is checked
+				assert.NoError(t, err)
+				assert.True(t, locked)
+				assert.NoError(t, locker.Unlock("daily_aggregation"))
+				assert.NoError(t, dbMock.ExpectationsWereMet())
+			},
+		},
+		{
+			name: "test not locked when held by another replica",
+			testFunction: func(t *testing.T) {
+				db, dbMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				dbMock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(GET_LOCK(?, 0), 0)")).WithArgs("tax_aggregator.daily_aggregation").
+					WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(0))
+				locker := NewMySQLLocker(db)
+				locked, err := locker.TryLock("daily_aggregation")
+				assert.NoError(t, err)
+				assert.False(t, locked)
+				assert.NoError(t, locker.Unlock("daily_aggregation"))
+				assert.NoError(t, dbMock.ExpectationsWereMet())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestMySQLLockName(t *testing.T) {
+	assert.Equal(t, "tax_aggregator.daily_aggregation", MySQLLockName("daily_aggregation"))
+	assert.LessOrEqual(t, len(MySQLLockName(strings.Repeat("x", 100))), 64)
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2023, 5, 10, 2, 7, 30, 0, time.UTC) // wednesday
+	tests := []struct {
+		name          string
+		spec          string
+		expectedNext  time.Time
+		expectedError bool
+	}{
+		{
+			name:         "test every 10 minutes",
+			spec:         "*/10 * * * *",
+			expectedNext: time.Date(2023, 5, 10, 2, 10, 0, 0, time.UTC),
+		},
+		{
+			name:         "test daily descriptor",
+			spec:         "@daily",
+			expectedNext: time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "test day of week with sunday as 7",
+			spec:         "30 1 * * 7",
+			expectedNext: time.Date(2023, 5, 14, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "test day of month or day of week",
+			spec:         "0 0 1 * 4",
+			expectedNext: time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "test every duration",
+			spec:         "@every 5m",
+			expectedNext: time.Date(2023, 5, 10, 2, 10, 0, 0, time.UTC),
+		},
+		{
+			name:          "test failed on missing fields",
+			spec:          "*/10 * * *",
+			expectedError: true,
+		},
+		{
+			name:          "test failed on out of range value",
+			spec:          "0 24 * * *",
+			expectedError: true,
+		},
+		{
+			name:          "test failed on sub second interval",
+			spec:          "@every 10ms",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedNext, schedule.Next(from))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"tax-aggregator-service-demo/config"
+	"time"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Provider read the current value of a secret by name.
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// NewProvider create the provider of secretManager, nil when no provider is configured.
+func NewProvider(secretManager *config.SecretManager) (Provider, error) {
+	switch secretManager.Provider {
+	case "":
+		return nil, nil
+	case "file":
+		return NewFileProvider(secretManager.Dir), nil
+	case "env":
+		return NewEnvProvider(), nil
+	case "vault":
+		return NewVaultProvider(secretManager.VaultAddress, secretManager.VaultToken, secretManager.VaultMount, &http.Client{Timeout: 10 * time.Second}), nil
+	}
+	return nil, fmt.Errorf("unsupported secret provider %q", secretManager.Provider)
+}
+
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider read secrets from files under dir, eg: a kubernetes secret mounted as volume,
+// the file is read on every Get so the kubelet updates of a rotated secret are picked up.
+func NewFileProvider(dir string) Provider {
+	return &fileProvider{dir: dir}
+}
+
+func (fp *fileProvider) Get(ctx context.Context, name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("secret name %q is outside of %s", name, fp.dir)
+	}
+	bytes, err := os.ReadFile(filepath.Join(fp.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(bytes), "\r\n"), nil
+}
+
+type envProvider struct{}
+
+// NewEnvProvider read secrets from environment variables.
+func NewEnvProvider() Provider {
+	return envProvider{}
+}
+
+func (envProvider) Get(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
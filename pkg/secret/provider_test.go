@@ -0,0 +1,82 @@
+package secret
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test file provider read mounted secret without trailing newline",
+			testFunction: func(t *testing.T) {
+				dir := t.TempDir()
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "source-password"), []byte("s3cret\n"), 0o600))
+				provider := NewFileProvider(dir)
+				secret, err := provider.Get(context.Background(), "source-password")
+				assert.NoError(t, err)
+				assert.Equal(t, "s3cret", secret)
+				_, err = provider.Get(context.Background(), "missing")
+				assert.ErrorIs(t, err, ErrSecretNotFound)
+				_, err = provider.Get(context.Background(), "../etc/passwd")
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "test env provider read environment variable",
+			testFunction: func(t *testing.T) {
+				t.Setenv("TAX_TEST_SECRET", "s3cret")
+				provider := NewEnvProvider()
+				secret, err := provider.Get(context.Background(), "TAX_TEST_SECRET")
+				assert.NoError(t, err)
+				assert.Equal(t, "s3cret", secret)
+				_, err = provider.Get(context.Background(), "TAX_TEST_MISSING_SECRET")
+				assert.ErrorIs(t, err, ErrSecretNotFound)
+			},
+		},
+		{
+			name: "test vault provider read key of kv v2 secret",
+			testFunction: func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("X-Vault-Token") != "token" {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					if r.URL.Path != "/v1/kv/data/tax-aggregator/source-database" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.Write([]byte(`{"data":{"data":{"password":"s3cret"},"metadata":{"version":2}}}`))
+				}))
+				defer server.Close()
+				provider := NewVaultProvider(server.URL, "token", "kv", server.Client())
+				secret, err := provider.Get(context.Background(), "tax-aggregator/source-database#password")
+				assert.NoError(t, err)
+				assert.Equal(t, "s3cret", secret)
+				_, err = provider.Get(context.Background(), "tax-aggregator/source-database#username")
+				assert.ErrorIs(t, err, ErrSecretNotFound)
+				_, err = provider.Get(context.Background(), "tax-aggregator/service-database#password")
+				assert.ErrorIs(t, err, ErrSecretNotFound)
+				_, err = provider.Get(context.Background(), "tax-aggregator/source-database")
+				assert.Error(t, err)
+				_, err = NewVaultProvider(server.URL, "expired", "kv", server.Client()).Get(context.Background(), "tax-aggregator/source-database#password")
+				assert.ErrorContains(t, err, "403")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/logger"
+	"time"
+)
+
+// Store resolve secret references and cache their values, cached secrets are re-read from the provider
+// every refresh interval so a rotated secret is used by the next Resolve.
+type Store struct {
+	provider        Provider
+	refreshInterval time.Duration
+	mu              sync.RWMutex
+	values          map[string]string
+	stop            chan struct{}
+	stopOnce        sync.Once
+	wg              sync.WaitGroup
+}
+
+// NewStore create a store over provider, provider may be nil when no secret is referenced.
+func NewStore(provider Provider, refreshInterval time.Duration) *Store {
+	return &Store{
+		provider:        provider,
+		refreshInterval: refreshInterval,
+		values:          map[string]string{},
+		stop:            make(chan struct{}),
+	}
+}
+
+// NewStoreFromConfig create a store over the provider of secretManager.
+func NewStoreFromConfig(secretManager *config.SecretManager) (*Store, error) {
+	provider, err := NewProvider(secretManager)
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(provider, time.Duration(secretManager.RefreshSeconds)*time.Second), nil
+}
+
+// IsReference report whether value is a secret:<name> reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, config.SecretReferencePrefix)
+}
+
+// Resolve return value as is when it is not a secret reference, otherwise the current value of the secret,
+// read from the provider on first use.
+func (s *Store) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	if s == nil || s.provider == nil {
+		return "", errors.New("secret is referenced but no secret provider is configured")
+	}
+	name := strings.TrimPrefix(value, config.SecretReferencePrefix)
+	s.mu.RLock()
+	secret, ok := s.values[name]
+	s.mu.RUnlock()
+	if ok {
+		return secret, nil
+	}
+	secret, err := s.provider.Get(ctx, name)
+	if err != nil {
+		slog.ErrorContext(ctx, "[secret.Store.Resolve]:: error reading secret.", slog.String("secret", name), logger.Err(err))
+		return "", err
+	}
+	s.mu.Lock()
+	s.values[name] = secret
+	s.mu.Unlock()
+	return secret, nil
+}
+
+// Refresh re-read every cached secret, a secret failing to be read keep its last value.
+func (s *Store) Refresh(ctx context.Context) {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.values))
+	for name := range s.values {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	for _, name := range names {
+		secret, err := s.provider.Get(ctx, name)
+		if err != nil {
+			slog.WarnContext(ctx, "[secret.Store.Refresh]:: error reading secret, keeping last value.", slog.String("secret", name), logger.Err(err))
+			continue
+		}
+		s.mu.Lock()
+		if s.values[name] != secret {
+			slog.InfoContext(ctx, "[secret.Store.Refresh]:: secret is rotated.", slog.String("secret", name))
+			s.values[name] = secret
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Start refresh cached secrets every refresh interval until Close, nothing is refreshed without provider or interval.
+func (s *Store) Start() {
+	if s.provider == nil || s.refreshInterval <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.Refresh(context.Background())
+			}
+		}
+	}()
+}
+
+// Close stop the refresh.
+func (s *Store) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	s.wg.Wait()
+}
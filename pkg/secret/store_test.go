@@ -0,0 +1,81 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProvider struct {
+	secrets map[string]string
+	calls   int
+}
+
+func (sp *stubProvider) Get(ctx context.Context, name string) (string, error) {
+	sp.calls++
+	secret, ok := sp.secrets[name]
+	if !ok {
+		return "", errors.New("vault is down")
+	}
+	return secret, nil
+}
+
+func TestStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test resolve plain value and cache referenced secret",
+			testFunction: func(t *testing.T) {
+				provider := &stubProvider{secrets: map[string]string{"db": "v1"}}
+				store := NewStore(provider, 0)
+				value, err := store.Resolve(context.Background(), "plain")
+				assert.NoError(t, err)
+				assert.Equal(t, "plain", value)
+				for i := 0; i < 2; i++ {
+					value, err = store.Resolve(context.Background(), "secret:db")
+					assert.NoError(t, err)
+					assert.Equal(t, "v1", value)
+				}
+				assert.Equal(t, 1, provider.calls)
+			},
+		},
+		{
+			name: "test refresh pick up rotated secret and keep last value on error",
+			testFunction: func(t *testing.T) {
+				provider := &stubProvider{secrets: map[string]string{"db": "v1"}}
+				store := NewStore(provider, 0)
+				_, err := store.Resolve(context.Background(), "secret:db")
+				assert.NoError(t, err)
+				provider.secrets["db"] = "v2"
+				store.Refresh(context.Background())
+				value, _ := store.Resolve(context.Background(), "secret:db")
+				assert.Equal(t, "v2", value)
+				delete(provider.secrets, "db")
+				store.Refresh(context.Background())
+				value, _ = store.Resolve(context.Background(), "secret:db")
+				assert.Equal(t, "v2", value)
+			},
+		},
+		{
+			name: "test resolve reference without provider",
+			testFunction: func(t *testing.T) {
+				_, err := NewStore(nil, 0).Resolve(context.Background(), "secret:db")
+				assert.Error(t, err)
+				var store *Store
+				value, err := store.Resolve(context.Background(), "plain")
+				assert.NoError(t, err)
+				assert.Equal(t, "plain", value)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type vaultProvider struct {
+	address string
+	token   string
+	mount   string
+	client  *http.Client
+}
+
+// NewVaultProvider read secrets from a kv v2 secrets engine mounted at mount on a hashicorp vault compatible server,
+// the secret name is <path>#<key>, eg: tax-aggregator/source-database#password.
+func NewVaultProvider(address, token, mount string, client *http.Client) Provider {
+	return &vaultProvider{
+		address: strings.TrimRight(address, "/"),
+		token:   token,
+		mount:   strings.Trim(mount, "/"),
+		client:  client,
+	}
+}
+
+type vaultSecret struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+func (vp *vaultProvider) Get(ctx context.Context, name string) (string, error) {
+	path, key, ok := strings.Cut(name, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault secret name %q is not <path>#<key>", name)
+	}
+	secretURL := vp.address + "/v1/" + vp.mount + "/data/" + (&url.URL{Path: strings.Trim(path, "/")}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", vp.token)
+	resp, err := vp.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s reading %s", resp.Status, path)
+	}
+	secret := &vaultSecret{}
+	if err := json.NewDecoder(resp.Body).Decode(secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data.Data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"tax-aggregator-service-demo/config"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name          string
+		tracing       config.Tracing
+		expectedError bool
+	}{
+		{
+			name:    "test passed when exporter is none",
+			tracing: config.Tracing{Exporter: "none", ServiceName: "tax-aggregator-service", SampleRatio: 1},
+		},
+		{
+			name:    "test passed when exporter is stdout",
+			tracing: config.Tracing{Exporter: "stdout", ServiceName: "tax-aggregator-service", SampleRatio: 1},
+		},
+		{
+			name:          "test failed when exporter is unknown",
+			tracing:       config.Tracing{Exporter: "zipkin", ServiceName: "tax-aggregator-service", SampleRatio: 1},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), &tt.tracing)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/jobs/:name", func(ctx echo.Context) error {
+		_, span := Start(ctx.Request().Context(), "child")
+		span.End()
+		if ctx.Param("name") == "broken" {
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		return ctx.NoContent(http.StatusOK)
+	})
+	request := httptest.NewRequest(http.MethodGet, "/jobs/a", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/jobs/broken", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 4)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /jobs/:name", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Equal(t, codes.Unset, server.Status().Code)
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+}
@@ -7,8 +7,61 @@ CREATE TABLE IF NOT EXISTS tax_transaction
     fee                 BIGINT(20)  DEFAULT 0,
     upline_bonus        BIGINT(20)  DEFAULT 0,
     remain              BIGINT(20)  DEFAULT 0,
-    ppn                 BIGINT(20)  DEFAULT 0
-)
+    ppn                 BIGINT(20)  DEFAULT 0,
+    UNIQUE (transaction_date)
+);
 
-CREATE INDEX IF NOT EXISTS tax_transaction_transaction_date_index
-    ON tax_transaction (transaction_date);
\ No newline at end of file
+CREATE TABLE IF NOT EXISTS backfill_checkpoint
+(
+    chunk_start         BIGINT      PRIMARY KEY,
+    chunk_end           BIGINT      NOT NULL,
+    status              VARCHAR(16) NOT NULL,
+    days_inserted       INT         DEFAULT 0,
+    updated_at          BIGINT      NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS aggregation_watermark
+(
+    name                VARCHAR(64) PRIMARY KEY,
+    watermark           BIGINT      NOT NULL,
+    updated_at          BIGINT      NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS job_run
+(
+    id                  SERIAL       PRIMARY KEY,
+    job_name            VARCHAR(64)  NOT NULL,
+    instance            VARCHAR(255) NOT NULL,
+    started_at          BIGINT       NOT NULL,
+    finished_at         BIGINT,
+    status              VARCHAR(16)  NOT NULL,
+    error               TEXT
+);
+
+CREATE INDEX IF NOT EXISTS job_run_job_name_started_at_index
+    ON job_run (job_name, started_at);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id                  BIGSERIAL    PRIMARY KEY,
+    action              VARCHAR(64)  NOT NULL,
+    actor               VARCHAR(255) NOT NULL,
+    claimed_actor       VARCHAR(255) NOT NULL DEFAULT '',
+    params              TEXT         NOT NULL,
+    source              VARCHAR(16)  NOT NULL DEFAULT '',
+    rows_inserted       BIGINT       NOT NULL DEFAULT 0,
+    config_hash         VARCHAR(64)  NOT NULL,
+    status              VARCHAR(16)  NOT NULL,
+    error               TEXT         NOT NULL DEFAULT '',
+    created_at          BIGINT       NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_index
+    ON audit_log (created_at);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_created_at_index
+    ON audit_log (actor, created_at);
+
+-- audit log is append-only
+CREATE OR REPLACE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE audit_log_no_delete AS ON DELETE TO audit_log DO INSTEAD NOTHING;
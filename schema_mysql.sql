@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS tax_transaction
+(
+    id                  BIGINT       PRIMARY KEY AUTO_INCREMENT,
+    transaction_date    BIGINT       NOT NULL,
+    deposit_rp          BIGINT       DEFAULT 0,
+    withdraw_rp         BIGINT       DEFAULT 0,
+    fee                 BIGINT       DEFAULT 0,
+    upline_bonus        BIGINT       DEFAULT 0,
+    remain              BIGINT       DEFAULT 0,
+    ppn                 BIGINT       DEFAULT 0,
+    UNIQUE (transaction_date)
+);
+
+CREATE TABLE IF NOT EXISTS backfill_checkpoint
+(
+    chunk_start         BIGINT      PRIMARY KEY,
+    chunk_end           BIGINT      NOT NULL,
+    status              VARCHAR(16) NOT NULL,
+    days_inserted       INT         DEFAULT 0,
+    updated_at          BIGINT      NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS aggregation_watermark
+(
+    name                VARCHAR(64) PRIMARY KEY,
+    watermark           BIGINT      NOT NULL,
+    updated_at          BIGINT      NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS job_run
+(
+    id                  BIGINT       PRIMARY KEY AUTO_INCREMENT,
+    job_name            VARCHAR(64)  NOT NULL,
+    instance            VARCHAR(255) NOT NULL,
+    started_at          BIGINT       NOT NULL,
+    finished_at         BIGINT,
+    status              VARCHAR(16)  NOT NULL,
+    error               TEXT,
+    INDEX job_run_job_name_started_at_index (job_name, started_at)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id                  BIGINT       PRIMARY KEY AUTO_INCREMENT,
+    action              VARCHAR(64)  NOT NULL,
+    actor               VARCHAR(255) NOT NULL,
+    claimed_actor       VARCHAR(255) NOT NULL DEFAULT '',
+    params              TEXT         NOT NULL,
+    source              VARCHAR(16)  NOT NULL DEFAULT '',
+    rows_inserted       BIGINT       NOT NULL DEFAULT 0,
+    config_hash         VARCHAR(64)  NOT NULL,
+    status              VARCHAR(16)  NOT NULL,
+    error               TEXT         NOT NULL,
+    created_at          BIGINT       NOT NULL,
+    INDEX audit_log_created_at_index (created_at),
+    INDEX audit_log_actor_created_at_index (actor, created_at)
+);
+
+-- audit log is append-only, grant the service user only INSERT and SELECT on audit_log
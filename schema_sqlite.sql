@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS tax_transaction
+(
+    id                  INTEGER     PRIMARY KEY AUTOINCREMENT,
+    transaction_date    INTEGER     NOT NULL,
+    deposit_rp          INTEGER     DEFAULT 0,
+    withdraw_rp         INTEGER     DEFAULT 0,
+    fee                 INTEGER     DEFAULT 0,
+    upline_bonus        INTEGER     DEFAULT 0,
+    remain              INTEGER     DEFAULT 0,
+    ppn                 INTEGER     DEFAULT 0,
+    UNIQUE (transaction_date)
+);
+
+CREATE TABLE IF NOT EXISTS backfill_checkpoint
+(
+    chunk_start         INTEGER     PRIMARY KEY,
+    chunk_end           INTEGER     NOT NULL,
+    status              TEXT        NOT NULL,
+    days_inserted       INTEGER     DEFAULT 0,
+    updated_at          INTEGER     NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS aggregation_watermark
+(
+    name                TEXT        PRIMARY KEY,
+    watermark           INTEGER     NOT NULL,
+    updated_at          INTEGER     NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS job_run
+(
+    id                  INTEGER     PRIMARY KEY AUTOINCREMENT,
+    job_name            TEXT        NOT NULL,
+    instance            TEXT        NOT NULL,
+    started_at          INTEGER     NOT NULL,
+    finished_at         INTEGER,
+    status              TEXT        NOT NULL,
+    error               TEXT
+);
+
+CREATE INDEX IF NOT EXISTS job_run_job_name_started_at_index
+    ON job_run (job_name, started_at);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id                  INTEGER     PRIMARY KEY AUTOINCREMENT,
+    action              TEXT        NOT NULL,
+    actor               TEXT        NOT NULL,
+    claimed_actor       TEXT        NOT NULL DEFAULT '',
+    params              TEXT        NOT NULL,
+    source              TEXT        NOT NULL DEFAULT '',
+    rows_inserted       INTEGER     NOT NULL DEFAULT 0,
+    config_hash         TEXT        NOT NULL,
+    status              TEXT        NOT NULL,
+    error               TEXT        NOT NULL DEFAULT '',
+    created_at          INTEGER     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_index
+    ON audit_log (created_at);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_created_at_index
+    ON audit_log (actor, created_at);
+
+-- audit log is append-only
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log BEGIN SELECT RAISE(IGNORE); END;
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log BEGIN SELECT RAISE(IGNORE); END;
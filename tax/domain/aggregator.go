@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"tax-aggregator-service-demo/tax/entity"
+	"time"
+)
+
+// watermark name of the daily aggregation job in service database
+const DailyAggregationWatermark = "daily_aggregation"
+
+// aggregator configuration from service config
+type AggregatorConfig struct {
+	CloseDelay time.Duration
+	StartDate  int64
+}
+
+// aggregator usecase interface contract for persisting closed business days ahead of reads
+type AggregatorUsecase interface {
+	Aggregate(ctx context.Context) error
+}
+
+// aggregator repository interface contract for high-water mark in service database
+type AggregatorRepository interface {
+	GetAggregationWatermark(ctx context.Context, name string) (*entity.AggregationWatermark, error)
+	UpsertAggregationWatermark(ctx context.Context, watermark *entity.AggregationWatermark) error
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"tax-aggregator-service-demo/tax/entity"
+)
+
+// backfill checkpoint status stored in service database
+const (
+	BackfillStatusDone   = "done"
+	BackfillStatusFailed = "failed"
+)
+
+// backfill configuration from cli flags
+type BackfillConfig struct {
+	Concurrency int
+}
+
+// backfill chunk, always aligned into calendar month since GetTax index the summaries by day of month
+type BackfillChunk struct {
+	StartDate    int64
+	EndDate      int64
+	AmountOfDays int
+}
+
+// backfill usecase interface contract for populating historical tax transaction
+type BackfillUsecase interface {
+	Backfill(ctx context.Context, from, to int64) error
+}
+
+// backfill repository interface contract for checkpoints in service database
+type BackfillRepository interface {
+	GetBackfillCheckpoints(ctx context.Context, startDate, endDate int64) ([]entity.BackfillCheckpoint, error)
+	UpsertBackfillCheckpoint(ctx context.Context, checkpoint *entity.BackfillCheckpoint) error
+}
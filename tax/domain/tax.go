@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"context"
+	"errors"
 	"tax-aggregator-service-demo/tax/entity"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -20,19 +23,32 @@ type TaxConfig struct {
 	TarifPpnNew     int64 `query:"tarif_ppn_new"`
 }
 
+// tax settings swapped on config reload without restart, source concurrency is the default when not set
+type TaxSettings struct {
+	TaxConfig           TaxConfig
+	SourceConcurrency   int
+	SourceQueryTimeout  time.Duration
+	ServiceQueryTimeout time.Duration
+	ChunkDays           map[string]int
+	ChunkConcurrency    int
+}
+
 type Response struct {
 	Data    any    `json:"data"`
 	Message string `json:"message"`
 	Code    int    `json:"code"`
 }
 
+// tax date of GetTax, days missing in service database are not fetched from source database when persisted only is set
 type TaxDate struct {
-	StartDate    int64
-	EndDate      int64
-	StartDay     int
-	AmountOfDays int
+	StartDate     int64
+	EndDate       int64
+	StartDay      int
+	AmountOfDays  int
+	PersistedOnly bool
 }
 
+// source date range of FetchSourceTax, start and end date are Asia/Jakarta business day boundaries, eg: tax.SourceDayStart
 type TaxSourceDate struct {
 	StartDate    int64
 	EndDate      int64
@@ -42,11 +58,44 @@ type TaxSourceDate struct {
 
 // tax usecase interface contract for business layer level in tax usecase
 type TaxUsecase interface {
-	GetTax(taxDate *TaxDate) (*TaxResponse, error)
-	FetchSourceTax(taxSourceDate *TaxSourceDate) (*TaxResponse, error)
+	GetTax(ctx context.Context, taxDate *TaxDate) (*TaxResponse, error)
+	FetchSourceTax(ctx context.Context, taxSourceDate *TaxSourceDate) (*TaxResponse, error)
+	AggregateDay(ctx context.Context, transactionDate int64) error
+	InvalidateTax(ctx context.Context, startDate, endDate int64) int
+	Reload(taxSettings *TaxSettings)
 }
 
-// tax response for tax_usecase from business layer in tax usecase
+// returned without querying while the circuit breaker of the database is open
+var (
+	ErrSourceUnavailable  = errors.New("source unavailable")
+	ErrServiceUnavailable = errors.New("service database unavailable")
+)
+
+// returned without querying when too many source fetches are running, retried after ratelimit.RetryAfter
+var ErrSourceBusy = errors.New("source busy")
+
+// resilience configuration of tax repository, retries and circuit breaker are disabled when attempts or threshold is not set
+type ResilienceConfig struct {
+	RetryAttempts           int
+	RetryBaseDelay          time.Duration
+	RetryMaxDelay           time.Duration
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+}
+
+// tax data source of a response, tax_transaction in service database is the cache of source database
+const (
+	TaxSourceCache  = "cache"
+	TaxSourceSource = "source"
+)
+
+// tax result cache status of a response, empty when the result cache is disabled
+const (
+	TaxCacheHit  = "hit"
+	TaxCacheMiss = "miss"
+)
+
+// tax response for tax_usecase from business layer in tax usecase, source and rows inserted are only used for audit trail
 type TaxResponse struct {
 	Summary          []TaxSummary `json:"summary"`
 	TotalRevenue     int64        `json:"total_revenue"`
@@ -54,6 +103,9 @@ type TaxResponse struct {
 	TotalUplineBonus int64        `json:"total_upline_bonus"`
 	TotalRemain      int64        `json:"total_remain"`
 	TotalPpn         int64        `json:"total_ppn"`
+	Cache            string       `json:"cache,omitempty"`
+	Source           string       `json:"-"`
+	DaysWritten      int64        `json:"-"`
 }
 
 // tax summary for tax bounded context
@@ -77,14 +129,16 @@ type AggregateFee struct {
 
 // tax repository interface contract for repository layer
 type TaxRepository interface {
-	GetDepositRpTotalAmount(startDate, endDate int64) ([]entity.DepositRpTotalAmount, error)
-	GetTotalWithdrawRp(startDate, calculationDate int64) ([]entity.TotalWithdrawRp, error)
-	GetFees(startDate, endDate int64) ([]entity.TotalFee, error)
-	GetOldFees(startDate, endDate int64) ([]entity.TotalFee, error)
-	GetCounterFees(startDate, endDate int64) ([]entity.CounterFee, error)
-	GetFeesPerDay(startTime, endTime int64) (*entity.TotalFee, error)
-	GetOldFeesPerDay(startTime, endTime int64) (*entity.TotalFee, error)
-
-	GetTaxTransactions(startDate, endDate int64) ([]entity.TaxTransactionSummary, error)
-	InsertTaxTransactions(transactionDate int64, taxTransactions []entity.TaxTransaction) error
+	GetDepositRpTotalAmount(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error)
+	GetTotalWithdrawRp(ctx context.Context, startDate, calculationDate int64) ([]entity.TotalWithdrawRp, error)
+	GetFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error)
+	GetOldFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error)
+	GetCounterFees(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error)
+	GetFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error)
+	GetOldFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error)
+
+	GetTaxTransactions(ctx context.Context, startDate, endDate int64) ([]entity.TaxTransactionSummary, error)
+	InsertTaxTransactions(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error)
+
+	Reload(taxSettings *TaxSettings)
 }
@@ -47,3 +47,17 @@ type TaxTransactionSummary struct {
 	Remain      int64 `json:"remain"`
 	Ppn         int64 `json:"ppn"`
 }
+
+type BackfillCheckpoint struct {
+	ChunkStart   int64  `json:"chunk_start"`
+	ChunkEnd     int64  `json:"chunk_end"`
+	Status       string `json:"status"`
+	DaysInserted int64  `json:"days_inserted"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+type AggregationWatermark struct {
+	Name      string `json:"name"`
+	Watermark int64  `json:"watermark"`
+	UpdatedAt int64  `json:"updated_at"`
+}
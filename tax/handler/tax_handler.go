@@ -1,25 +1,43 @@
 package handler
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"tax-aggregator-service-demo/audit"
+	"tax-aggregator-service-demo/pkg/auth"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/pkg/ratelimit"
 	"tax-aggregator-service-demo/tax/domain"
 
+	auditDomain "tax-aggregator-service-demo/audit/domain"
+	auditEntity "tax-aggregator-service-demo/audit/entity"
+
 	"github.com/labstack/echo/v4"
 )
 
 type taxHandler struct {
-	taxUsecase domain.TaxUsecase
+	taxUsecase    domain.TaxUsecase
+	auditUsecase  auditDomain.AuditUsecase
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
 }
 
-func NewTaxHandler(taxUsecase domain.TaxUsecase) domain.TaxHandler {
+func NewTaxHandler(taxUsecase domain.TaxUsecase, auditUsecase auditDomain.AuditUsecase, authenticator *auth.Authenticator, limiter *ratelimit.Limiter) domain.TaxHandler {
 	return &taxHandler{
-		taxUsecase: taxUsecase,
+		taxUsecase:    taxUsecase,
+		auditUsecase:  auditUsecase,
+		authenticator: authenticator,
+		limiter:       limiter,
 	}
 }
 
+// Routes require viewer to read tax, days missing in service database are only fetched from source database for accountant.
+// every route is rate limited per client.
 func (th *taxHandler) Routes(echo *echo.Echo) {
-	echo.GET("/tax", th.GetTax)
+	echo.GET("/tax", th.GetTax, th.authenticator.Require(auth.RoleViewer), th.limiter.Middleware())
 }
 
 func (th *taxHandler) GetTax(ctx echo.Context) error {
@@ -29,19 +47,50 @@ func (th *taxHandler) GetTax(ctx echo.Context) error {
 		MustInt("amount_of_days", &taxDate.AmountOfDays).
 		BindError()
 	if err != nil {
-		log.Println("[TaxHandler.GetTax]:: error bind query params")
+		slog.WarnContext(ctx.Request().Context(), "[TaxHandler.GetTax]:: error bind query params", logger.Err(err))
 		return ctx.JSON(http.StatusBadRequest, &domain.Response{
 			Code:    http.StatusInternalServerError,
 			Message: "bad request",
 		})
 	}
-	tax, err := th.taxUsecase.GetTax(taxDate)
+	taxDate.PersistedOnly = !th.authenticator.Allowed(ctx.Request().Context(), auth.RoleAccountant)
+	auditLog := &auditEntity.AuditLog{
+		Action:       auditDomain.AuditActionGetTax,
+		Actor:        audit.Actor(ctx),
+		ClaimedActor: audit.ClaimedActor(ctx),
+		Params:       audit.Params(ctx.QueryParams()),
+	}
+	tax, err := th.taxUsecase.GetTax(ctx.Request().Context(), taxDate)
+	if err != nil {
+		auditLog.Status = auditDomain.AuditStatusFailed
+		auditLog.Error = err.Error()
+	} else {
+		auditLog.Source = tax.Source
+		auditLog.RowsInserted = tax.DaysWritten
+		metrics.TaxResponses.WithLabelValues(tax.Source, tax.Cache).Inc()
+	}
+	// recorded even when the client went away or the request deadline passed
+	if err := th.auditUsecase.Record(context.WithoutCancel(ctx.Request().Context()), auditLog); err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "[TaxHandler.GetTax]:: error recording audit log.", logger.Err(err))
+	}
+	if retryAfter, ok := ratelimit.RetryAfter(err); ok && errors.Is(err, domain.ErrSourceBusy) {
+		ratelimit.SetRetryAfter(ctx.Response().Header(), retryAfter)
+	}
+	if errors.Is(err, domain.ErrSourceBusy) || errors.Is(err, domain.ErrSourceUnavailable) || errors.Is(err, domain.ErrServiceUnavailable) {
+		return ctx.JSON(http.StatusServiceUnavailable, &domain.Response{
+			Code:    http.StatusServiceUnavailable,
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, &domain.Response{
 			Code:    http.StatusInternalServerError,
 			Message: err.Error(),
 		})
 	}
+	if tax.Cache != "" {
+		ctx.Response().Header().Set("X-Cache", tax.Cache)
+	}
 	return ctx.JSON(http.StatusOK, &domain.Response{
 		Code:    http.StatusOK,
 		Message: "success get tax",
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+)
+
+type aggregatorRepository struct {
+	serviceConn *sql.DB
+	dialect     dbconn.Dialect
+}
+
+func NewAggregatorRepository(serviceConn *sql.DB) domain.AggregatorRepository {
+	return &aggregatorRepository{
+		serviceConn: serviceConn,
+		dialect:     dbconn.DialectOf(serviceConn),
+	}
+}
+
+// get aggregation watermark query from service database.
+const getAggregationWatermark = `
+	SELECT
+		a.name,
+		a.watermark,
+		a.updated_at
+	FROM
+		aggregation_watermark AS a
+	WHERE
+		a.name = $1
+`
+
+// GetAggregationWatermark return nil watermark when the job never ran before.
+func (ar *aggregatorRepository) GetAggregationWatermark(ctx context.Context, name string) (*entity.AggregationWatermark, error) {
+	serviceConn := ar.serviceConn
+	watermark := new(entity.AggregationWatermark)
+	query, args := ar.dialect.Rebind(getAggregationWatermark, name)
+	err := serviceConn.QueryRowContext(ctx, query, args...).Scan(
+		&watermark.Name,
+		&watermark.Watermark,
+		&watermark.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "[AggregatorRepository.GetAggregationWatermark]:: error getting aggregation_watermark from service database.", logger.Err(err))
+		return nil, err
+	}
+	return watermark, nil
+}
+
+// upsert aggregation watermark query from service database, the upsert clause is appended by dialect.
+const upsertAggregationWatermark = `
+	INSERT INTO
+		aggregation_watermark(name, watermark, updated_at)
+	VALUES
+		($1, $2, $3)
+`
+
+func (ar *aggregatorRepository) UpsertAggregationWatermark(ctx context.Context, watermark *entity.AggregationWatermark) error {
+	serviceConn := ar.serviceConn
+	query, args := ar.dialect.Rebind(upsertAggregationWatermark+ar.dialect.Upsert([]string{"name"}, "watermark", "updated_at"),
+		watermark.Name,
+		watermark.Watermark,
+		watermark.UpdatedAt,
+	)
+	if _, err := serviceConn.ExecContext(ctx, query, args...); err != nil {
+		slog.ErrorContext(ctx, "[AggregatorRepository.UpsertAggregationWatermark]:: error upsert aggregation_watermark into service database.", logger.Err(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregatorRepository_GetAggregationWatermark(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test get aggregation watermark that never ran",
+			testFunction: func(t *testing.T) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				rows := sqlmock.NewRows([]string{"name", "watermark", "updated_at"})
+				serviceMock.ExpectQuery(regexp.QuoteMeta(getAggregationWatermark)).WithArgs("daily_aggregation").WillReturnRows(rows)
+				aggregatorRepository := NewAggregatorRepository(serviceConn)
+				watermark, err := aggregatorRepository.GetAggregationWatermark(context.Background(), "daily_aggregation")
+				assert.NoError(t, err)
+				assert.Nil(t, watermark)
+			},
+		},
+		{
+			name: "test get aggregation watermark success",
+			testFunction: func(t *testing.T) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				rows := sqlmock.NewRows([]string{"name", "watermark", "updated_at"})
+				rows.AddRow("daily_aggregation", 1683417600, 1683504900)
+				serviceMock.ExpectQuery(regexp.QuoteMeta(getAggregationWatermark)).WithArgs("daily_aggregation").WillReturnRows(rows)
+				aggregatorRepository := NewAggregatorRepository(serviceConn)
+				watermark, err := aggregatorRepository.GetAggregationWatermark(context.Background(), "daily_aggregation")
+				assert.NoError(t, err)
+				assert.Equal(t, &entity.AggregationWatermark{Name: "daily_aggregation", Watermark: 1683417600, UpdatedAt: 1683504900}, watermark)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
+
+func TestAggregatorRepository_UpsertAggregationWatermark(t *testing.T) {
+	serviceConn, serviceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceMock.ExpectExec(regexp.QuoteMeta(upsertAggregationWatermark)).
+		WithArgs("daily_aggregation", int64(1683417600), int64(1683504900)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	aggregatorRepository := NewAggregatorRepository(serviceConn)
+	assert.NoError(t, aggregatorRepository.UpsertAggregationWatermark(context.Background(), &entity.AggregationWatermark{
+		Name:      "daily_aggregation",
+		Watermark: 1683417600,
+		UpdatedAt: 1683504900,
+	}))
+	assert.NoError(t, serviceMock.ExpectationsWereMet())
+}
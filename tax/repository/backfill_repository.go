@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+)
+
+type backfillRepository struct {
+	serviceConn *sql.DB
+	dialect     dbconn.Dialect
+}
+
+func NewBackfillRepository(serviceConn *sql.DB) domain.BackfillRepository {
+	return &backfillRepository{
+		serviceConn: serviceConn,
+		dialect:     dbconn.DialectOf(serviceConn),
+	}
+}
+
+// get backfill checkpoints query from service database.
+const getBackfillCheckpoints = `
+	SELECT
+		b.chunk_start,
+		b.chunk_end,
+		b.status,
+		b.days_inserted,
+		b.updated_at
+	FROM
+		backfill_checkpoint AS b
+	WHERE
+		b.chunk_start >= $1 AND b.chunk_start < $2
+	ORDER BY
+		b.chunk_start
+	ASC
+`
+
+func (br *backfillRepository) GetBackfillCheckpoints(ctx context.Context, startDate, endDate int64) ([]entity.BackfillCheckpoint, error) {
+	serviceConn := br.serviceConn
+	checkpoints := []entity.BackfillCheckpoint{}
+	query, args := br.dialect.Rebind(getBackfillCheckpoints, startDate, endDate)
+	r, err := serviceConn.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BackfillRepository.GetBackfillCheckpoints]:: error getting backfill_checkpoint from service database.", logger.Err(err))
+		return nil, err
+	}
+	defer r.Close()
+	checkpoint := &entity.BackfillCheckpoint{}
+	for r.Next() {
+		if err := r.Scan(
+			&checkpoint.ChunkStart,
+			&checkpoint.ChunkEnd,
+			&checkpoint.Status,
+			&checkpoint.DaysInserted,
+			&checkpoint.UpdatedAt,
+		); err != nil {
+			slog.ErrorContext(ctx, "[BackfillRepository.GetBackfillCheckpoints]:: error scanning backfill_checkpoint from service database.", logger.Err(err))
+			return nil, err
+		}
+		checkpoints = append(checkpoints, *checkpoint)
+	}
+	return checkpoints, r.Err()
+}
+
+// upsert backfill checkpoint query from service database, the upsert clause is appended by dialect.
+const upsertBackfillCheckpoint = `
+	INSERT INTO
+		backfill_checkpoint(chunk_start, chunk_end, status, days_inserted, updated_at)
+	VALUES
+		($1, $2, $3, $4, $5)
+`
+
+func (br *backfillRepository) UpsertBackfillCheckpoint(ctx context.Context, checkpoint *entity.BackfillCheckpoint) error {
+	serviceConn := br.serviceConn
+	query, args := br.dialect.Rebind(upsertBackfillCheckpoint+br.dialect.Upsert([]string{"chunk_start"}, "chunk_end", "status", "days_inserted", "updated_at"),
+		checkpoint.ChunkStart,
+		checkpoint.ChunkEnd,
+		checkpoint.Status,
+		checkpoint.DaysInserted,
+		checkpoint.UpdatedAt,
+	)
+	if _, err := serviceConn.ExecContext(ctx, query, args...); err != nil {
+		slog.ErrorContext(ctx, "[BackfillRepository.UpsertBackfillCheckpoint]:: error upsert backfill_checkpoint into service database.", logger.Err(err))
+		return err
+	}
+	return nil
+}
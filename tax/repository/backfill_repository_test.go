@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillRepository_GetBackfillCheckpoints(t *testing.T) {
+	type args struct {
+		startDate int64
+		endDate   int64
+	}
+	tests := []struct {
+		name         string
+		args         args
+		testFunction func(t *testing.T, tt args)
+	}{
+		{
+			name: "test get backfill checkpoints with 0 data",
+			args: args{
+				startDate: 1672531200,
+				endDate:   1704067200,
+			},
+			testFunction: func(t *testing.T, tt args) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				rows := sqlmock.NewRows([]string{"chunk_start", "chunk_end", "status", "days_inserted", "updated_at"})
+				serviceMock.ExpectQuery(regexp.QuoteMeta(getBackfillCheckpoints)).WithArgs(tt.startDate, tt.endDate).WillReturnRows(rows)
+				backfillRepository := NewBackfillRepository(serviceConn)
+				checkpoints, err := backfillRepository.GetBackfillCheckpoints(context.Background(), tt.startDate, tt.endDate)
+				assert.NoError(t, err)
+				assert.Empty(t, checkpoints)
+			},
+		},
+		{
+			name: "test get backfill checkpoints success",
+			args: args{
+				startDate: 1672531200,
+				endDate:   1704067200,
+			},
+			testFunction: func(t *testing.T, tt args) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				rows := sqlmock.NewRows([]string{"chunk_start", "chunk_end", "status", "days_inserted", "updated_at"})
+				rows.AddRow(1672531200, 1675209600, "done", 31, 1700000000)
+				rows.AddRow(1675209600, 1677628800, "failed", 0, 1700000000)
+				serviceMock.ExpectQuery(regexp.QuoteMeta(getBackfillCheckpoints)).WithArgs(tt.startDate, tt.endDate).WillReturnRows(rows)
+				backfillRepository := NewBackfillRepository(serviceConn)
+				checkpoints, err := backfillRepository.GetBackfillCheckpoints(context.Background(), tt.startDate, tt.endDate)
+				assert.NoError(t, err)
+				assert.Equal(t, []entity.BackfillCheckpoint{
+					{ChunkStart: 1672531200, ChunkEnd: 1675209600, Status: "done", DaysInserted: 31, UpdatedAt: 1700000000},
+					{ChunkStart: 1675209600, ChunkEnd: 1677628800, Status: "failed", DaysInserted: 0, UpdatedAt: 1700000000},
+				}, checkpoints)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t, tt.args)
+		})
+	}
+}
+
+func TestBackfillRepository_UpsertBackfillCheckpoint(t *testing.T) {
+	type args struct {
+		checkpoint *entity.BackfillCheckpoint
+	}
+	tests := []struct {
+		name         string
+		args         args
+		testFunction func(t *testing.T, tt args)
+	}{
+		{
+			name: "test upsert backfill checkpoint success",
+			args: args{
+				checkpoint: &entity.BackfillCheckpoint{ChunkStart: 1672531200, ChunkEnd: 1675209600, Status: "done", DaysInserted: 31, UpdatedAt: 1700000000},
+			},
+			testFunction: func(t *testing.T, tt args) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceMock.ExpectExec(regexp.QuoteMeta(upsertBackfillCheckpoint)).
+					WithArgs(tt.checkpoint.ChunkStart, tt.checkpoint.ChunkEnd, tt.checkpoint.Status, tt.checkpoint.DaysInserted, tt.checkpoint.UpdatedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				backfillRepository := NewBackfillRepository(serviceConn)
+				assert.NoError(t, backfillRepository.UpsertBackfillCheckpoint(context.Background(), tt.checkpoint))
+				assert.NoError(t, serviceMock.ExpectationsWereMet())
+			},
+		},
+		{
+			name: "test upsert backfill checkpoint failed",
+			args: args{
+				checkpoint: &entity.BackfillCheckpoint{ChunkStart: 1672531200, ChunkEnd: 1675209600, Status: "done", DaysInserted: 31, UpdatedAt: 1700000000},
+			},
+			testFunction: func(t *testing.T, tt args) {
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceMock.ExpectExec(regexp.QuoteMeta(upsertBackfillCheckpoint)).WillReturnError(errors.New("connection refused"))
+				backfillRepository := NewBackfillRepository(serviceConn)
+				assert.Error(t, backfillRepository.UpsertBackfillCheckpoint(context.Background(), tt.checkpoint))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t, tt.args)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/pkg/tracing"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// instrumentedTaxRepository decorate a tax repository with latency and error metrics and a span per method,
+// and count the days inserted into tax_transaction.
+type instrumentedTaxRepository struct {
+	taxRepository domain.TaxRepository
+}
+
+func NewInstrumentedTaxRepository(taxRepository domain.TaxRepository) domain.TaxRepository {
+	return &instrumentedTaxRepository{taxRepository: taxRepository}
+}
+
+// observe run fn inside a span carrying the queried table and row count, and record its latency and error under method.
+func observe[T any](ctx context.Context, method, table string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := tracing.Start(ctx, "TaxRepository."+method, semconv.DBSQLTable(table))
+	start := time.Now()
+	result, err := fn(ctx)
+	metrics.RepositoryQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.RepositoryQueryErrors.WithLabelValues(method).Inc()
+	} else {
+		span.SetAttributes(attribute.Int64("db.rows", rowCount(result)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// rowCount return the rows read as the slice length or a single row, and the rows written as the affected count.
+func rowCount(result any) int64 {
+	if rows, ok := result.(int64); ok {
+		return rows
+	}
+	value := reflect.ValueOf(result)
+	switch value.Kind() {
+	case reflect.Slice:
+		return int64(value.Len())
+	case reflect.Pointer:
+		if !value.IsNil() {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (itr *instrumentedTaxRepository) GetDepositRpTotalAmount(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+	return observe(ctx, "GetDepositRpTotalAmount", DepositRpTable, func(ctx context.Context) ([]entity.DepositRpTotalAmount, error) {
+		return itr.taxRepository.GetDepositRpTotalAmount(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetTotalWithdrawRp(ctx context.Context, startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
+	return observe(ctx, "GetTotalWithdrawRp", WithdrawRpTable, func(ctx context.Context) ([]entity.TotalWithdrawRp, error) {
+		return itr.taxRepository.GetTotalWithdrawRp(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	return observe(ctx, "GetFees", FeesTable, func(ctx context.Context) ([]entity.TotalFee, error) {
+		return itr.taxRepository.GetFees(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetOldFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	return observe(ctx, "GetOldFees", OldFeesTable, func(ctx context.Context) ([]entity.TotalFee, error) {
+		return itr.taxRepository.GetOldFees(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetCounterFees(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error) {
+	return observe(ctx, "GetCounterFees", CounterBuyBtcTable, func(ctx context.Context) ([]entity.CounterFee, error) {
+		return itr.taxRepository.GetCounterFees(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error) {
+	return observe(ctx, "GetFeesPerDay", FeesTable, func(ctx context.Context) (*entity.TotalFee, error) {
+		return itr.taxRepository.GetFeesPerDay(ctx, startTime, endTime)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetOldFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error) {
+	return observe(ctx, "GetOldFeesPerDay", OldFeesTable, func(ctx context.Context) (*entity.TotalFee, error) {
+		return itr.taxRepository.GetOldFeesPerDay(ctx, startTime, endTime)
+	})
+}
+
+func (itr *instrumentedTaxRepository) GetTaxTransactions(ctx context.Context, startDate, endDate int64) ([]entity.TaxTransactionSummary, error) {
+	return observe(ctx, "GetTaxTransactions", "tax_transaction", func(ctx context.Context) ([]entity.TaxTransactionSummary, error) {
+		return itr.taxRepository.GetTaxTransactions(ctx, startDate, endDate)
+	})
+}
+
+func (itr *instrumentedTaxRepository) InsertTaxTransactions(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error) {
+	rows, err := observe(ctx, "InsertTaxTransactions", "tax_transaction", func(ctx context.Context) (int64, error) {
+		return itr.taxRepository.InsertTaxTransactions(ctx, transactionDate, taxTransactions)
+	})
+	if err == nil {
+		metrics.DaysInserted.Add(float64(rows))
+	}
+	return rows, err
+}
+
+func (itr *instrumentedTaxRepository) Reload(taxSettings *domain.TaxSettings) {
+	itr.taxRepository.Reload(taxSettings)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+
+	mocks "tax-aggregator-service-demo/mocks/tax/domain"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentedTaxRepository(t *testing.T) {
+	taxRepository := mocks.NewTaxRepository(t)
+	taxRepository.EXPECT().GetCounterFees(mock.Anything, int64(1), int64(2)).Return(nil, errors.New("source database down")).Once()
+	taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1), mock.Anything).Return(int64(3), nil).Once()
+	instrumentedTaxRepository := NewInstrumentedTaxRepository(taxRepository)
+	errorsBefore := testutil.ToFloat64(metrics.RepositoryQueryErrors.WithLabelValues("GetCounterFees"))
+	daysBefore := testutil.ToFloat64(metrics.DaysInserted)
+
+	_, err := instrumentedTaxRepository.GetCounterFees(context.Background(), 1, 2)
+	assert.Error(t, err)
+	rows, err := instrumentedTaxRepository.InsertTaxTransactions(context.Background(), 1, []entity.TaxTransaction{{}, {}, {}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), rows)
+
+	assert.Equal(t, errorsBefore+1, testutil.ToFloat64(metrics.RepositoryQueryErrors.WithLabelValues("GetCounterFees")))
+	assert.Equal(t, daysBefore+3, testutil.ToFloat64(metrics.DaysInserted))
+}
+
+func TestInstrumentedTaxRepository_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	taxRepository := mocks.NewTaxRepository(t)
+	taxRepository.EXPECT().GetFees(mock.Anything, int64(1), int64(2)).Return([]entity.TotalFee{{}, {}}, nil).Once()
+	taxRepository.EXPECT().GetFeesPerDay(mock.Anything, int64(1), int64(2)).Return(nil, errors.New("source database down")).Once()
+	instrumentedTaxRepository := NewInstrumentedTaxRepository(taxRepository)
+
+	_, err := instrumentedTaxRepository.GetFees(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	_, err = instrumentedTaxRepository.GetFeesPerDay(context.Background(), 1, 2)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "TaxRepository.GetFees", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.sql.table", FeesTable))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int64("db.rows", 2))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/pkg/resilience"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+)
+
+// resilientTaxRepository decorate a tax repository with retries of transient errors and a circuit breaker per database,
+// inserts are retried too since a day written again after a lost commit acknowledgement is upserted, not duplicated.
+type resilientTaxRepository struct {
+	taxRepository  domain.TaxRepository
+	backoff        resilience.Backoff
+	sourceBreaker  *resilience.CircuitBreaker
+	serviceBreaker *resilience.CircuitBreaker
+}
+
+func NewResilientTaxRepository(taxRepository domain.TaxRepository, resilienceConfig *domain.ResilienceConfig) domain.TaxRepository {
+	return &resilientTaxRepository{
+		taxRepository: taxRepository,
+		backoff: resilience.Backoff{
+			Attempts:  resilienceConfig.RetryAttempts,
+			BaseDelay: resilienceConfig.RetryBaseDelay,
+			MaxDelay:  resilienceConfig.RetryMaxDelay,
+		},
+		sourceBreaker:  resilience.NewCircuitBreaker("source database", resilienceConfig.BreakerFailureThreshold, resilienceConfig.BreakerOpenTimeout, resilience.IsRetryable),
+		serviceBreaker: resilience.NewCircuitBreaker("service database", resilienceConfig.BreakerFailureThreshold, resilienceConfig.BreakerOpenTimeout, resilience.IsRetryable),
+	}
+}
+
+// call run fn through breaker, retrying transient errors with backoff. an open breaker is reported as unavailable.
+func call[T any](ctx context.Context, breaker *resilience.CircuitBreaker, backoff resilience.Backoff, unavailable error, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := resilience.Retry(ctx, backoff, resilience.IsRetryable, func() error {
+		return breaker.Do(func() (err error) {
+			result, err = fn(ctx)
+			return err
+		})
+	})
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return result, unavailable
+	}
+	return result, err
+}
+
+func (rtr *resilientTaxRepository) GetDepositRpTotalAmount(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) ([]entity.DepositRpTotalAmount, error) {
+		return rtr.taxRepository.GetDepositRpTotalAmount(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetTotalWithdrawRp(ctx context.Context, startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) ([]entity.TotalWithdrawRp, error) {
+		return rtr.taxRepository.GetTotalWithdrawRp(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) ([]entity.TotalFee, error) {
+		return rtr.taxRepository.GetFees(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetOldFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) ([]entity.TotalFee, error) {
+		return rtr.taxRepository.GetOldFees(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetCounterFees(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) ([]entity.CounterFee, error) {
+		return rtr.taxRepository.GetCounterFees(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) (*entity.TotalFee, error) {
+		return rtr.taxRepository.GetFeesPerDay(ctx, startTime, endTime)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetOldFeesPerDay(ctx context.Context, startTime, endTime int64) (*entity.TotalFee, error) {
+	return call(ctx, rtr.sourceBreaker, rtr.backoff, domain.ErrSourceUnavailable, func(ctx context.Context) (*entity.TotalFee, error) {
+		return rtr.taxRepository.GetOldFeesPerDay(ctx, startTime, endTime)
+	})
+}
+
+func (rtr *resilientTaxRepository) GetTaxTransactions(ctx context.Context, startDate, endDate int64) ([]entity.TaxTransactionSummary, error) {
+	return call(ctx, rtr.serviceBreaker, rtr.backoff, domain.ErrServiceUnavailable, func(ctx context.Context) ([]entity.TaxTransactionSummary, error) {
+		return rtr.taxRepository.GetTaxTransactions(ctx, startDate, endDate)
+	})
+}
+
+func (rtr *resilientTaxRepository) InsertTaxTransactions(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error) {
+	return call(ctx, rtr.serviceBreaker, rtr.backoff, domain.ErrServiceUnavailable, func(ctx context.Context) (int64, error) {
+		return rtr.taxRepository.InsertTaxTransactions(ctx, transactionDate, taxTransactions)
+	})
+}
+
+func (rtr *resilientTaxRepository) Reload(taxSettings *domain.TaxSettings) {
+	rtr.taxRepository.Reload(taxSettings)
+}
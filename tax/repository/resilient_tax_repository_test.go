@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/tax/domain"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResilientTaxRepository(t *testing.T) {
+	resilienceConfig := &domain.ResilienceConfig{
+		RetryAttempts:           3,
+		RetryBaseDelay:          time.Millisecond,
+		RetryMaxDelay:           time.Millisecond,
+		BreakerFailureThreshold: 3,
+		BreakerOpenTimeout:      time.Minute,
+	}
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test retry transient source error",
+			testFunction: func(t *testing.T) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetFees(mock.Anything, int64(1682899200), int64(1682985600)).Return(nil, mysql.ErrInvalidConn).Once()
+				taxRepository.EXPECT().GetFees(mock.Anything, int64(1682899200), int64(1682985600)).Return([]entity.TotalFee{{}}, nil).Once()
+				totalFees, err := NewResilientTaxRepository(taxRepository, resilienceConfig).GetFees(context.Background(), 1682899200, 1682985600)
+				assert.NoError(t, err)
+				assert.Len(t, totalFees, 1)
+			},
+		},
+		{
+			name: "test fail fast with source unavailable when source breaker is open",
+			testFunction: func(t *testing.T) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetCounterFees(mock.Anything, int64(1682899200), int64(1682985600)).Return(nil, mysql.ErrInvalidConn).Times(3)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1682985600)).Return(nil, nil).Once()
+				resilientTaxRepository := NewResilientTaxRepository(taxRepository, resilienceConfig)
+				_, err := resilientTaxRepository.GetCounterFees(context.Background(), 1682899200, 1682985600)
+				assert.ErrorIs(t, err, mysql.ErrInvalidConn)
+				_, err = resilientTaxRepository.GetDepositRpTotalAmount(context.Background(), 1682899200, 1682985600)
+				assert.ErrorIs(t, err, domain.ErrSourceUnavailable)
+				_, err = resilientTaxRepository.GetTaxTransactions(context.Background(), 1682899200, 1682985600) // service breaker still closed
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "test retry idempotent insert and not retry permanent error",
+			testFunction: func(t *testing.T) {
+				taxRepository := mocks.NewTaxRepository(t)
+				errPermanent := errors.New("pq: duplicate key value violates unique constraint")
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1682899200), mock.Anything).Return(0, mysql.ErrInvalidConn).Once()
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1682899200), mock.Anything).Return(1, nil).Once()
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1682985600)).Return(nil, errPermanent).Once()
+				resilientTaxRepository := NewResilientTaxRepository(taxRepository, resilienceConfig)
+				days, err := resilientTaxRepository.InsertTaxTransactions(context.Background(), 1682899200, []entity.TaxTransaction{{}})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), days)
+				_, err = resilientTaxRepository.GetTaxTransactions(context.Background(), 1682899200, 1682985600)
+				assert.ErrorIs(t, err, errPermanent)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceRepositories_SQLite(t *testing.T) {
+	serviceConn, err := dbconn.NewSQLiteDBConn(&config.Database{DBName: filepath.Join(t.TempDir(), "service.db")})
+	assert.NoError(t, err)
+	defer serviceConn.Close()
+	schema, err := os.ReadFile("../../schema_sqlite.sql")
+	assert.NoError(t, err)
+	_, err = serviceConn.Exec(string(schema))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	taxRepository := NewTaxRepository(nil, serviceConn)
+	rows, err := taxRepository.InsertTaxTransactions(ctx, 1682899200, []entity.TaxTransaction{
+		{TransactionDate: 1682899200, DepositRp: 10, WithdrawRp: 20, Fee: 30, UplineBonus: 3, Remain: 27, Ppn: 2},
+		{TransactionDate: 1682985600, DepositRp: 40, WithdrawRp: 50, Fee: 60, UplineBonus: 6, Remain: 54, Ppn: 5},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	taxTransactions, err := taxRepository.GetTaxTransactions(ctx, 1682899200, 1682985600)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.TaxTransactionSummary{{DayOfMonth: 1, DepositRp: 10, WithdrawRp: 20, Fee: 30, UplineBonus: 3, Remain: 27, Ppn: 2}}, taxTransactions)
+	// a day written again is kept as a single row
+	_, err = taxRepository.InsertTaxTransactions(ctx, 1682899200, []entity.TaxTransaction{
+		{TransactionDate: 1682899200, DepositRp: 11, WithdrawRp: 20, Fee: 30, UplineBonus: 3, Remain: 27, Ppn: 2},
+	})
+	assert.NoError(t, err)
+	taxTransactions, err = taxRepository.GetTaxTransactions(ctx, 1682899200, 1682985600)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.TaxTransactionSummary{{DayOfMonth: 1, DepositRp: 11, WithdrawRp: 20, Fee: 30, UplineBonus: 3, Remain: 27, Ppn: 2}}, taxTransactions)
+
+	backfillRepository := NewBackfillRepository(serviceConn)
+	assert.NoError(t, backfillRepository.UpsertBackfillCheckpoint(ctx, &entity.BackfillCheckpoint{ChunkStart: 1, ChunkEnd: 2, Status: "running", UpdatedAt: 10}))
+	assert.NoError(t, backfillRepository.UpsertBackfillCheckpoint(ctx, &entity.BackfillCheckpoint{ChunkStart: 1, ChunkEnd: 2, Status: "done", DaysInserted: 1, UpdatedAt: 20}))
+	checkpoints, err := backfillRepository.GetBackfillCheckpoints(ctx, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.BackfillCheckpoint{{ChunkStart: 1, ChunkEnd: 2, Status: "done", DaysInserted: 1, UpdatedAt: 20}}, checkpoints)
+
+	aggregatorRepository := NewAggregatorRepository(serviceConn)
+	watermark, err := aggregatorRepository.GetAggregationWatermark(ctx, "daily")
+	assert.NoError(t, err)
+	assert.Nil(t, watermark)
+	assert.NoError(t, aggregatorRepository.UpsertAggregationWatermark(ctx, &entity.AggregationWatermark{Name: "daily", Watermark: 1, UpdatedAt: 10}))
+	assert.NoError(t, aggregatorRepository.UpsertAggregationWatermark(ctx, &entity.AggregationWatermark{Name: "daily", Watermark: 2, UpdatedAt: 20}))
+	watermark, err = aggregatorRepository.GetAggregationWatermark(ctx, "daily")
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.AggregationWatermark{Name: "daily", Watermark: 2, UpdatedAt: 20}, watermark)
+}
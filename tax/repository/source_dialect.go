@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"tax-aggregator-service-demo/pkg/dbconn"
+)
+
+// sourceQueries is the query set of a source database dialect, every query take start and end unix time
+// and group rows by Asia/Jakarta day of month.
+type sourceQueries struct {
+	DepositRpTotalAmount string
+	TotalWithdrawRp      string
+	Fees                 string
+	OldFees              string
+	CounterFees          string
+}
+
+// query sets by source database driver
+var sourceDialects = map[string]sourceQueries{
+	dbconn.DriverMySQL: {
+		DepositRpTotalAmount: getDepositRpTotalAmount,
+		TotalWithdrawRp:      getTotalWithdrawRp,
+		Fees:                 getFees,
+		OldFees:              getOldFees,
+		CounterFees:          getCounterFees,
+	},
+	dbconn.DriverPostgres: {
+		DepositRpTotalAmount: getDepositRpTotalAmountPostgres,
+		TotalWithdrawRp:      getTotalWithdrawRpPostgres,
+		Fees:                 getFeesPostgres,
+		OldFees:              getOldFeesPostgres,
+		CounterFees:          getCounterFeesPostgres,
+	},
+	dbconn.DriverSQLite: {
+		DepositRpTotalAmount: getDepositRpTotalAmountSQLite,
+		TotalWithdrawRp:      getTotalWithdrawRpSQLite,
+		Fees:                 getFeesSQLite,
+		OldFees:              getOldFeesSQLite,
+		CounterFees:          getCounterFeesSQLite,
+	},
+}
+
+// postgresql source queries, day of month is converted with AT TIME ZONE.
+const (
+	getDepositRpTotalAmountPostgres = `
+	SELECT
+		EXTRACT(DAY FROM TO_TIMESTAMP(success_time) AT TIME ZONE 'Asia/Jakarta')::INTEGER AS day_of_month,
+		SUM(rp) AS total_rp,
+		SUM(amount) AS total_amount,
+		SUM(subsidi_fee) AS total_subsidi_fee
+	FROM
+		deposit_rp
+	WHERE
+		success_time >= $1
+	AND
+		success_time < $2
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getTotalWithdrawRpPostgres = `
+	SELECT
+		EXTRACT(DAY FROM TO_TIMESTAMP(success_time) AT TIME ZONE 'Asia/Jakarta')::INTEGER AS day_of_month,
+		SUM(rp) AS total_rp
+	FROM
+		withdraw_rp
+	WHERE
+		success_time >= $1
+	AND
+		success_time < $2
+	AND
+		type != 'coupon'
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getFeesPostgres = `
+	SELECT
+		EXTRACT(DAY FROM TO_TIMESTAMP(waktu_transaksi) AT TIME ZONE 'Asia/Jakarta')::INTEGER AS day_of_month,
+		SUM(fee) AS total_fee,
+		SUM(upline_bonus) AS total_upline_bonus,
+		SUM(remain) AS total_remain
+	FROM
+		fees
+	WHERE
+		waktu_transaksi >= $1 AND waktu_transaksi < $2
+	AND
+		type NOT IN('deposit', 'tax')
+	AND
+		upline_id != 1
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getOldFeesPostgres = `
+	SELECT
+		EXTRACT(DAY FROM TO_TIMESTAMP(waktu_transaksi) AT TIME ZONE 'Asia/Jakarta')::INTEGER AS day_of_month,
+		SUM(fee) AS total_fee,
+		SUM(upline_bonus) AS total_upline_bonus,
+		SUM(remain) AS total_remain
+	FROM
+		fees_old
+	WHERE
+		waktu_transaksi >= $1 AND waktu_transaksi < $2
+	AND
+		type NOT IN('deposit', 'tax')
+	AND
+		upline_id != 1
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getCounterFeesPostgres = `
+	SELECT
+		EXTRACT(DAY FROM TO_TIMESTAMP(success_time) AT TIME ZONE 'Asia/Jakarta')::INTEGER AS day_of_month,
+		SUM(fee) AS total_fee
+	FROM
+		counter_buy_btc
+	WHERE
+		status = 'success'
+	AND
+		success_time >= $1 AND success_time < $2
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+)
+
+// sqlite source queries, sqlite has no time zone database so Asia/Jakarta is the fixed +7 hours offset.
+const (
+	getDepositRpTotalAmountSQLite = `
+	SELECT
+		CAST(STRFTIME('%d', success_time, 'unixepoch', '+7 hours') AS INTEGER) AS day_of_month,
+		SUM(rp) AS total_rp,
+		SUM(amount) AS total_amount,
+		SUM(subsidi_fee) AS total_subsidi_fee
+	FROM
+		deposit_rp
+	WHERE
+		success_time >= ?
+	AND
+		success_time < ?
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getTotalWithdrawRpSQLite = `
+	SELECT
+		CAST(STRFTIME('%d', success_time, 'unixepoch', '+7 hours') AS INTEGER) AS day_of_month,
+		SUM(rp) AS total_rp
+	FROM
+		withdraw_rp
+	WHERE
+		success_time >= ?
+	AND
+		success_time < ?
+	AND
+		type != 'coupon'
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getFeesSQLite = `
+	SELECT
+		CAST(STRFTIME('%d', waktu_transaksi, 'unixepoch', '+7 hours') AS INTEGER) AS day_of_month,
+		SUM(fee) AS total_fee,
+		SUM(upline_bonus) AS total_upline_bonus,
+		SUM(remain) AS total_remain
+	FROM
+		fees
+	WHERE
+		waktu_transaksi >= ? AND waktu_transaksi < ?
+	AND
+		type NOT IN('deposit', 'tax')
+	AND
+		upline_id != 1
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getOldFeesSQLite = `
+	SELECT
+		CAST(STRFTIME('%d', waktu_transaksi, 'unixepoch', '+7 hours') AS INTEGER) AS day_of_month,
+		SUM(fee) AS total_fee,
+		SUM(upline_bonus) AS total_upline_bonus,
+		SUM(remain) AS total_remain
+	FROM
+		fees_old
+	WHERE
+		waktu_transaksi >= ? AND waktu_transaksi < ?
+	AND
+		type NOT IN('deposit', 'tax')
+	AND
+		upline_id != 1
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+	getCounterFeesSQLite = `
+	SELECT
+		CAST(STRFTIME('%d', success_time, 'unixepoch', '+7 hours') AS INTEGER) AS day_of_month,
+		SUM(fee) AS total_fee
+	FROM
+		counter_buy_btc
+	WHERE
+		status = 'success'
+	AND
+		success_time >= ? AND success_time < ?
+	GROUP BY
+		day_of_month
+	ORDER BY
+		day_of_month
+	ASC
+`
+)
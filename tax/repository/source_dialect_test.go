@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"regexp"
+	"tax-aggregator-service-demo/config"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const createSQLiteSource = `
+	CREATE TABLE deposit_rp (success_time INTEGER, rp INTEGER, amount INTEGER, subsidi_fee INTEGER);
+	CREATE TABLE withdraw_rp (success_time INTEGER, rp INTEGER, type TEXT);
+	CREATE TABLE fees (waktu_transaksi INTEGER, fee INTEGER, upline_bonus INTEGER, remain INTEGER, type TEXT, upline_id INTEGER);
+	CREATE TABLE fees_old (waktu_transaksi INTEGER, fee INTEGER, upline_bonus INTEGER, remain INTEGER, type TEXT, upline_id INTEGER);
+	CREATE TABLE counter_buy_btc (success_time INTEGER, fee INTEGER, status TEXT);
+	INSERT INTO deposit_rp VALUES (1682873990, 1, 1, 0), (1682877600, 10, 100, 1), (1682960390, 20, 200, 2), (1682960410, 30, 300, 3);
+	INSERT INTO withdraw_rp VALUES (1682877600, 50, 'bank'), (1682877600, 70, 'coupon');
+	INSERT INTO fees VALUES (1682877600, 100, 10, 90, 'trade', 2), (1682877600, 5, 0, 5, 'tax', 2), (1682960410, 200, 20, 180, 'trade', 2);
+	INSERT INTO fees_old VALUES (1682877600, 300, 30, 270, 'trade', 1);
+	INSERT INTO counter_buy_btc VALUES (1682960410, 7, 'success'), (1682960410, 9, 'failed');
+`
+
+func TestTaxRepository_SQLiteSource(t *testing.T) {
+	sourceConn, err := dbconn.NewSQLiteDBConn(&config.Database{DBName: filepath.Join(t.TempDir(), "source.db")})
+	assert.NoError(t, err)
+	defer sourceConn.Close()
+	_, err = sourceConn.Exec(createSQLiteSource)
+	assert.NoError(t, err)
+	taxRepository := NewTaxRepository(nil, nil, WithSourceRouter(dbconn.NewRouter(sourceConn, nil, dbconn.WithDriver(dbconn.DriverSQLite))))
+	ctx := context.Background()
+	day := func(dayOfMonth int64) sql.NullInt64 { return sql.NullInt64{Int64: dayOfMonth, Valid: true} }
+	amount := func(amount int64) sql.NullInt64 { return sql.NullInt64{Int64: amount, Valid: true} }
+	startDate, endDate := int64(1682874000), int64(1683046800) // 2023-05-01 and 2023-05-03 Asia/Jakarta
+
+	depositRpTotalAmount, err := taxRepository.GetDepositRpTotalAmount(ctx, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, []int64{depositRpTotalAmount[0].DayOfMonth.Int64, depositRpTotalAmount[1].DayOfMonth.Int64})
+	assert.Equal(t, amount(300), depositRpTotalAmount[0].TotalAmount) // 23:59:50 on 1 May Jakarta
+	assert.Equal(t, amount(300), depositRpTotalAmount[1].TotalAmount)
+
+	totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(ctx, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Len(t, totalWithdrawRp, 1)
+	assert.Equal(t, amount(50), totalWithdrawRp[0].TotalRp)
+
+	totalFees, err := taxRepository.GetFees(ctx, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Len(t, totalFees, 2)
+	assert.Equal(t, day(1), totalFees[0].DayOfMonth)
+	assert.Equal(t, amount(100), totalFees[0].TotalFee)
+	assert.Equal(t, amount(180), totalFees[1].TotalRemain)
+
+	oldFees, err := taxRepository.GetOldFees(ctx, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Len(t, oldFees, 0)
+
+	counterFees, err := taxRepository.GetCounterFees(ctx, startDate, endDate)
+	assert.NoError(t, err)
+	assert.Len(t, counterFees, 1)
+	assert.Equal(t, day(2), counterFees[0].DayOfMonth)
+	assert.Equal(t, amount(7), counterFees[0].TotalFee)
+
+	feesPerDay, err := taxRepository.GetFeesPerDay(ctx, startDate+86400, endDate)
+	assert.NoError(t, err)
+	assert.Equal(t, amount(200), feesPerDay.TotalFee)
+}
+
+func TestTaxRepository_PostgresSource(t *testing.T) {
+	sourceConn, sourceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	sourceMock.ExpectQuery(regexp.QuoteMeta(getCounterFeesPostgres)).WithArgs(int64(1682874000), int64(1682960400)).
+		WillReturnRows(sqlmock.NewRows([]string{"day_of_month", "total_fee"}).AddRow(1, "7"))
+	taxRepository := NewTaxRepository(nil, nil, WithSourceRouter(dbconn.NewRouter(sourceConn, nil, dbconn.WithDriver(dbconn.DriverPostgres))))
+	counterFees, err := taxRepository.GetCounterFees(context.Background(), 1682874000, 1682960400)
+	assert.NoError(t, err)
+	assert.Len(t, counterFees, 1)
+	assert.Equal(t, int64(7), counterFees[0].TotalFee.Int64)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+}
@@ -1,30 +1,96 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"strings"
+	"sync/atomic"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/pkg/logger"
 	"tax-aggregator-service-demo/tax/domain"
 	"tax-aggregator-service-demo/tax/entity"
+	"time"
 )
 
 type taxRepository struct {
-	sourceConn  *sql.DB
-	serviceConn *sql.DB
+	sourceRouter   *dbconn.Router
+	sourceQueries  sourceQueries
+	serviceConn    *sql.DB
+	serviceDialect dbconn.Dialect
+	options        atomic.Pointer[TaxRepositoryOptions]
 }
 
-func NewTaxRepository(sourceConn, serviceConn *sql.DB) domain.TaxRepository {
-	return &taxRepository{
-		sourceConn:  sourceConn,
-		serviceConn: serviceConn,
+type TaxRepositoryOptions struct {
+	SourceQueryTimeout  time.Duration
+	ServiceQueryTimeout time.Duration
+	ChunkDays           map[string]int
+	ChunkConcurrency    int
+	SourceRouter        *dbconn.Router
+}
+
+type TaxRepositoryOption func(*TaxRepositoryOptions)
+
+// WithQueryTimeout bound every query on source and service database, dbconn.DefaultTimeout is used when not set.
+func WithQueryTimeout(sourceQueryTimeout, serviceQueryTimeout time.Duration) TaxRepositoryOption {
+	return func(options *TaxRepositoryOptions) {
+		options.SourceQueryTimeout = sourceQueryTimeout
+		options.ServiceQueryTimeout = serviceQueryTimeout
+	}
+}
+
+// WithChunking split source queries over a range into sub-ranges of chunk days per source table, eg: fees by 1 day and deposit_rp by 7 days,
+// the sub-ranges are queried in parallel up to chunk concurrency and stitched by day of month. tables without chunk days are queried at once.
+func WithChunking(chunkDays map[string]int, chunkConcurrency int) TaxRepositoryOption {
+	return func(options *TaxRepositoryOptions) {
+		options.ChunkDays = chunkDays
+		options.ChunkConcurrency = chunkConcurrency
+	}
+}
+
+// WithSourceRouter read source aggregate queries from the healthy replicas of router, source conn is used as mysql when not set.
+// the query set follow the driver of the router.
+func WithSourceRouter(sourceRouter *dbconn.Router) TaxRepositoryOption {
+	return func(options *TaxRepositoryOptions) {
+		options.SourceRouter = sourceRouter
+	}
+}
+
+func NewTaxRepository(sourceConn, serviceConn *sql.DB, opts ...TaxRepositoryOption) domain.TaxRepository {
+	options := TaxRepositoryOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	sourceRouter := options.SourceRouter
+	if sourceRouter == nil {
+		sourceRouter = dbconn.NewRouter(sourceConn, nil)
+	}
+	taxRepository := &taxRepository{
+		sourceRouter:   sourceRouter,
+		sourceQueries:  sourceDialects[sourceRouter.Driver()],
+		serviceConn:    serviceConn,
+		serviceDialect: dbconn.DialectOf(serviceConn),
 	}
+	taxRepository.options.Store(&options)
+	return taxRepository
+}
+
+// Reload swap the query timeouts and chunking of taxSettings, running queries keep the previous ones.
+func (tr *taxRepository) Reload(taxSettings *domain.TaxSettings) {
+	options := *tr.options.Load()
+	options.SourceQueryTimeout = taxSettings.SourceQueryTimeout
+	options.ServiceQueryTimeout = taxSettings.ServiceQueryTimeout
+	options.ChunkDays = maps.Clone(taxSettings.ChunkDays)
+	options.ChunkConcurrency = taxSettings.ChunkConcurrency
+	tr.options.Store(&options)
 }
 
 // get deposit rp total amount query from source database.
 const getDepositRpTotalAmount = `
 	SELECT
-		DATE FORMAT(CONVERT_TZ(FROM_UNIXTIME(success_time), 'UTC', 'Asia/Jakarta'), '%e') AS day_of_month,
+		DATE_FORMAT(CONVERT_TZ(FROM_UNIXTIME(success_time), 'UTC', 'Asia/Jakarta'), '%e') AS day_of_month,
 		SUM(rp) AS total_rp,
 		SUM(amount) AS total_amount,
 		SUM(subsidi_fee) AS total_subsidi_fee
@@ -41,17 +107,28 @@ const getDepositRpTotalAmount = `
 	ASC
 `
 
-func (tr *taxRepository) GetDepositRpTotalAmount(startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetDepositRpTotalAmount(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+	options := tr.options.Load()
+	chunks, err := queryChunks(ctx, options.ChunkDays[DepositRpTable], options.ChunkConcurrency, startDate, endDate, tr.getDepositRpTotalAmountChunk)
+	if err != nil {
+		return nil, err
+	}
+	return mergeDepositRpTotalAmount(chunks), nil
+}
+
+func (tr *taxRepository) getDepositRpTotalAmountChunk(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
 	depositRpTotalAmount := []entity.DepositRpTotalAmount{}
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getDepositRpTotalAmount, sourceConn)
+	r, err := query(tr.sourceQueries.DepositRpTotalAmount)
 	if err != nil {
-		log.Println("[TaxRepository.GetDepositRpTotalAmount]:: server getting deposit_rp_total_amount from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetDepositRpTotalAmount]:: server getting deposit_rp_total_amount from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	depositRpTotalAmountPerDay := &entity.DepositRpTotalAmount{}
 	for r.Next() {
 		if err := r.Scan(
@@ -60,13 +137,12 @@ func (tr *taxRepository) GetDepositRpTotalAmount(startDate, endDate int64) ([]en
 			&depositRpTotalAmountPerDay.TotalAmount,
 			&depositRpTotalAmountPerDay.TotalSubsidiFee,
 		); err != nil {
-			log.Println("[TaxRepository.GetDepositRpTotalAmount]:: error scanning deposit_rp_total_amount from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetDepositRpTotalAmount]:: error scanning deposit_rp_total_amount from source database.", logger.Err(err))
 			return nil, err
 		}
 		depositRpTotalAmount = append(depositRpTotalAmount, *depositRpTotalAmountPerDay)
 	}
-	r.Close()
-	return depositRpTotalAmount, nil
+	return depositRpTotalAmount, r.Err()
 }
 
 // get total withdraw rp query from source database.
@@ -89,29 +165,40 @@ const getTotalWithdrawRp = `
 	ASC
 `
 
-func (tr *taxRepository) GetTotalWithdrawRp(startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetTotalWithdrawRp(ctx context.Context, startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
+	options := tr.options.Load()
+	chunks, err := queryChunks(ctx, options.ChunkDays[WithdrawRpTable], options.ChunkConcurrency, startDate, endDate, tr.getTotalWithdrawRpChunk)
+	if err != nil {
+		return nil, err
+	}
+	return mergeTotalWithdrawRp(chunks), nil
+}
+
+func (tr *taxRepository) getTotalWithdrawRpChunk(ctx context.Context, startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
 	totalWithdrawRp := []entity.TotalWithdrawRp{}
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getTotalWithdrawRp, sourceConn)
+	r, err := query(tr.sourceQueries.TotalWithdrawRp)
 	if err != nil {
-		log.Println("[TaxRepository.GetTotalWithdrawRp]:: error on getting withdraw_rp_total_amount from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetTotalWithdrawRp]:: error on getting withdraw_rp_total_amount from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	totalWithdrawRpPerDay := &entity.TotalWithdrawRp{}
 	for r.Next() {
 		if err := r.Scan(
 			&totalWithdrawRpPerDay.DayOfMonth,
 			&totalWithdrawRpPerDay.TotalRp,
 		); err != nil {
-			log.Println("[TaxRepository.GetTotalWithdrawRp]:: error on scanning withdraw_rp_total_amount from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetTotalWithdrawRp]:: error on scanning withdraw_rp_total_amount from source database.", logger.Err(err))
+			return nil, err
 		}
 		totalWithdrawRp = append(totalWithdrawRp, *totalWithdrawRpPerDay)
 	}
-	r.Close()
-	return totalWithdrawRp, nil
+	return totalWithdrawRp, r.Err()
 }
 
 // get fees query from source database.
@@ -120,7 +207,7 @@ const getFees = `
 		DATE_FORMAT(CONVERT_TZ(FROM_UNIXTIME(waktu_transaksi), 'UTC', 'Asia/Jakarta'), '%e') AS day_of_month,
 		SUM(fee) AS total_fee,
 		SUM(upline_bonus) AS total_upline_bonus,
-		SUM(remain) AS
+		SUM(remain) AS total_remain
 	FROM
 		fees
 	WHERE
@@ -136,17 +223,28 @@ const getFees = `
 	ASC
 `
 
-func (tr *taxRepository) GetFees(startDate, endDate int64) ([]entity.TotalFee, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	options := tr.options.Load()
+	chunks, err := queryChunks(ctx, options.ChunkDays[FeesTable], options.ChunkConcurrency, startDate, endDate, tr.getFeesChunk)
+	if err != nil {
+		return nil, err
+	}
+	return mergeTotalFees(chunks), nil
+}
+
+func (tr *taxRepository) getFeesChunk(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
 	totalFees := []entity.TotalFee{}
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getFees, sourceConn)
+	r, err := query(tr.sourceQueries.Fees)
 	if err != nil {
-		log.Println("[TaxRepository.GetFees]:: error getting total_fee from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetFees]:: error getting total_fee from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	totalFeesPerDay := &entity.TotalFee{}
 	for r.Next() {
 		if err := r.Scan(
@@ -155,13 +253,12 @@ func (tr *taxRepository) GetFees(startDate, endDate int64) ([]entity.TotalFee, e
 			&totalFeesPerDay.TotalUplineBonus,
 			&totalFeesPerDay.TotalRemain,
 		); err != nil {
-			log.Println("[TaxRepository.GetFees]:: error scanning fees from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetFees]:: error scanning fees from source database.", logger.Err(err))
 			return nil, err
 		}
 		totalFees = append(totalFees, *totalFeesPerDay)
 	}
-	r.Close()
-	return totalFees, nil
+	return totalFees, r.Err()
 }
 
 // get old fees query from source database.
@@ -186,17 +283,28 @@ const getOldFees = `
 	ASC
 `
 
-func (tr *taxRepository) GetOldFees(startDate, endDate int64) ([]entity.TotalFee, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetOldFees(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+	options := tr.options.Load()
+	chunks, err := queryChunks(ctx, options.ChunkDays[OldFeesTable], options.ChunkConcurrency, startDate, endDate, tr.getOldFeesChunk)
+	if err != nil {
+		return nil, err
+	}
+	return mergeTotalFees(chunks), nil
+}
+
+func (tr *taxRepository) getOldFeesChunk(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
 	totalFees := []entity.TotalFee{}
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getOldFees, sourceConn)
+	r, err := query(tr.sourceQueries.OldFees)
 	if err != nil {
-		log.Println("[TaxRepository.GetOldFees]:: error getting total_fee from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetOldFees]:: error getting total_fee from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	totalFeesPerDay := &entity.TotalFee{}
 	for r.Next() {
 		if err := r.Scan(
@@ -205,13 +313,12 @@ func (tr *taxRepository) GetOldFees(startDate, endDate int64) ([]entity.TotalFee
 			&totalFeesPerDay.TotalUplineBonus,
 			&totalFeesPerDay.TotalRemain,
 		); err != nil {
-			log.Println("[TaxRepository.GetOldFees]:: error scanning total_fee from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetOldFees]:: error scanning total_fee from source database.", logger.Err(err))
 			return nil, err
 		}
 		totalFees = append(totalFees, *totalFeesPerDay)
 	}
-	r.Close()
-	return totalFees, nil
+	return totalFees, r.Err()
 }
 
 // get counter fees query from source database.
@@ -232,43 +339,55 @@ const getCounterFees = `
 	ASC
 `
 
-func (tr *taxRepository) GetCounterFees(startDate, endDate int64) ([]entity.CounterFee, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetCounterFees(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error) {
+	options := tr.options.Load()
+	chunks, err := queryChunks(ctx, options.ChunkDays[CounterBuyBtcTable], options.ChunkConcurrency, startDate, endDate, tr.getCounterFeesChunk)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCounterFees(chunks), nil
+}
+
+func (tr *taxRepository) getCounterFeesChunk(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error) {
 	counterFees := []entity.CounterFee{}
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getCounterFees, sourceConn)
+	r, err := query(tr.sourceQueries.CounterFees)
 	if err != nil {
-		log.Println("[TaxRepository.GetCounterFees]:: error getting counter_fee from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetCounterFees]:: error getting counter_fee from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	couterFeesPerDay := &entity.CounterFee{}
 	for r.Next() {
 		if err := r.Scan(
 			&couterFeesPerDay.DayOfMonth,
 			&couterFeesPerDay.TotalFee,
 		); err != nil {
-			log.Println("[TaxRepository.GetCounterFees]:: error scanning counter_fee from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetCounterFees]:: error scanning counter_fee from source database.", logger.Err(err))
 			return nil, err
 		}
 		counterFees = append(counterFees, *couterFeesPerDay)
 	}
-	r.Close()
-	return counterFees, nil
+	return counterFees, r.Err()
 }
 
-func (tr *taxRepository) GetFeesPerDay(startDate, endDate int64) (*entity.TotalFee, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetFeesPerDay(ctx context.Context, startDate, endDate int64) (*entity.TotalFee, error) {
 	totalFees := new(entity.TotalFee)
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getFees, sourceConn)
+	r, err := query(tr.sourceQueries.Fees)
 	if err != nil {
-		log.Println("[TaxRepository.GetFeesPerDay]:: error getting total_fee per day from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetFeesPerDay]:: error getting total_fee per day from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	for r.Next() {
 		if err := r.Scan(
 			&totalFees.DayOfMonth,
@@ -276,25 +395,26 @@ func (tr *taxRepository) GetFeesPerDay(startDate, endDate int64) (*entity.TotalF
 			&totalFees.TotalUplineBonus,
 			&totalFees.TotalRemain,
 		); err != nil {
-			log.Println("[TaxRepository.GetFeesPerDay]:: error scanning total_fee per day from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetFeesPerDay]:: error scanning total_fee per day from source database.", logger.Err(err))
 			return nil, err
 		}
 	}
-	r.Close()
-	return totalFees, nil
+	return totalFees, r.Err()
 }
 
-func (tr *taxRepository) GetOldFeesPerDay(startDate, endDate int64) (*entity.TotalFee, error) {
-	sourceConn := tr.sourceConn
+func (tr *taxRepository) GetOldFeesPerDay(ctx context.Context, startDate, endDate int64) (*entity.TotalFee, error) {
 	totalFees := new(entity.TotalFee)
-	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().SourceQueryTimeout)
+	defer cancel()
+	query := func(query string) (*dbconn.Rows, error) {
+		return tr.sourceRouter.QueryContext(ctx, query, startDate, endDate)
 	}
-	r, err := query(getOldFees, sourceConn)
+	r, err := query(tr.sourceQueries.OldFees)
 	if err != nil {
-		log.Println("[TaxRepository.GetOldFeesPerDay]:: error getting total_fee per day from source database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetOldFeesPerDay]:: error getting total_fee per day from source database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	for r.Next() {
 		if err := r.Scan(
 			&totalFees.DayOfMonth,
@@ -302,18 +422,17 @@ func (tr *taxRepository) GetOldFeesPerDay(startDate, endDate int64) (*entity.Tot
 			&totalFees.TotalUplineBonus,
 			&totalFees.TotalRemain,
 		); err != nil {
-			log.Println("[TaxRepository.GetOldFeesPerDay]:: error scanning total_fee per day from source database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetOldFeesPerDay]:: error scanning total_fee per day from source database.", logger.Err(err))
 			return nil, err
 		}
 	}
-	r.Close()
-	return totalFees, nil
+	return totalFees, r.Err()
 }
 
-// get tax transactions query from service database.
+// get tax transactions query from service database, day of month expression is formatted by dialect.
 const getTaxTransactions = `
 	SELECT
-		DATE_PART('day', TO_TIMESTAMP(t.transaction_date)) AS day_of_month,
+		%s AS day_of_month,
 		t.deposit_rp,
 		t.withdraw_rp,
 		t.fee,
@@ -326,17 +445,21 @@ const getTaxTransactions = `
 		t.transaction_date >= $1 AND t.transaction_date < $2
 `
 
-func (tr *taxRepository) GetTaxTransactions(startDate, endDate int64) ([]entity.TaxTransactionSummary, error) {
+func (tr *taxRepository) GetTaxTransactions(ctx context.Context, startDate, endDate int64) ([]entity.TaxTransactionSummary, error) {
 	serviceConn := tr.serviceConn
 	taxTransactionSummaries := []entity.TaxTransactionSummary{}
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().ServiceQueryTimeout)
+	defer cancel()
 	query := func(query string, db *sql.DB) (*sql.Rows, error) {
-		return db.Query(query, startDate, endDate)
+		query, args := tr.serviceDialect.Rebind(fmt.Sprintf(query, tr.serviceDialect.DayOfMonth("t.transaction_date")), startDate, endDate)
+		return db.QueryContext(ctx, query, args...)
 	}
 	r, err := query(getTaxTransactions, serviceConn)
 	if err != nil {
-		log.Println("[TaxRepository.GetTaxTransactions]:: error getting tax_transactions from service database.")
+		slog.ErrorContext(ctx, "[TaxRepository.GetTaxTransactions]:: error getting tax_transactions from service database.", logger.Err(err))
 		return nil, err
 	}
+	defer r.Close()
 	taxTransactionPerDay := &entity.TaxTransactionSummary{}
 	for r.Next() {
 		if err := r.Scan(
@@ -348,29 +471,30 @@ func (tr *taxRepository) GetTaxTransactions(startDate, endDate int64) ([]entity.
 			&taxTransactionPerDay.Remain,
 			&taxTransactionPerDay.Ppn,
 		); err != nil {
-			log.Println("[TaxRepository.GetTaxTransactions]:: error scanning tax_transactions from service database.")
+			slog.ErrorContext(ctx, "[TaxRepository.GetTaxTransactions]:: error scanning tax_transactions from service database.", logger.Err(err))
 			return nil, err
 		}
 		taxTransactionSummaries = append(taxTransactionSummaries, *taxTransactionPerDay)
 	}
-	r.Close()
-	return taxTransactionSummaries, nil
+	return taxTransactionSummaries, r.Err()
 }
 
-// insert tax transaction query from service database.
+// insert tax transaction query from service database, a day written by another replica, the aggregator
+// or the backfill at the same time is updated instead of inserted twice.
 const insertTaxTransaction = `
 	INSERT INTO
 		tax_transaction(transaction_date, deposit_rp, withdraw_rp, fee, upline_bonus, remain, ppn)
 	VALUES
 `
 
-func (tr *taxRepository) InsertTaxTransactions(transactionDate int64, taxTransactions []entity.TaxTransaction) error {
+func (tr *taxRepository) InsertTaxTransactions(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error) {
 	serviceConn := tr.serviceConn
-	tx, err := serviceConn.Begin()
+	ctx, cancel := dbconn.WithQueryTimeout(ctx, tr.options.Load().ServiceQueryTimeout)
+	defer cancel()
+	tx, err := serviceConn.BeginTx(ctx, nil)
 	if err != nil {
-		log.Println("[TaxRepository.InsertTaxTransaction]:: error begin database transaction in service database.")
-		tx.Rollback()
-		return err
+		slog.ErrorContext(ctx, "[TaxRepository.InsertTaxTransaction]:: error begin database transaction in service database.", logger.Err(err))
+		return 0, err
 	}
 	var inserts []string
 	var args []interface{}
@@ -382,18 +506,19 @@ func (tr *taxRepository) InsertTaxTransactions(transactionDate int64, taxTransac
 		args = append(args, (v.TransactionDate), v.DepositRp, v.WithdrawRp, v.Fee, v.UplineBonus, v.Remain, v.Ppn)
 	}
 	queryVals := strings.Join(inserts, ",")
-	query := insertTaxTransaction + queryVals
-	res, err := tx.Exec(query, args...)
-	if err != nil {
-		log.Println("[TaxRepository.InsertTaxTransactions]:: error insert tax_transaction.")
+	upsert := tr.serviceDialect.Upsert([]string{"transaction_date"}, "deposit_rp", "withdraw_rp", "fee", "upline_bonus", "remain", "ppn")
+	query, args := tr.serviceDialect.Rebind(insertTaxTransaction+queryVals+" "+upsert, args...)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		slog.ErrorContext(ctx, "[TaxRepository.InsertTaxTransactions]:: error insert tax_transaction.", logger.Err(err))
 		tx.Rollback()
+		return 0, err
 	}
-	rows, err := res.RowsAffected()
-	if err != nil {
-		log.Println("[TaxRepository.InsertTaxTransactions]:: error when finding rows.")
-		return err
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, "[TaxRepository.InsertTaxTransactions]:: error commit tax_transaction.", logger.Err(err))
+		return 0, err
 	}
-	tx.Commit()
-	log.Printf("[TaxRepository.InsertTaxTransactions]:: created %d tax_transactions simultaneously.\n", rows)
-	return nil
+	// rows affected is not the days written, mysql count an updated row twice and postgresql or sqlite count it once.
+	days := int64(len(taxTransactions))
+	slog.InfoContext(ctx, "[TaxRepository.InsertTaxTransactions]:: created tax_transactions simultaneously.", slog.Int64("days", days))
+	return days, nil
 }
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"tax-aggregator-service-demo/pkg/tracing"
+	"tax-aggregator-service-demo/tax/entity"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// source tables that can be queried in chunks, used as key of chunk days.
+const (
+	DepositRpTable     = "deposit_rp"
+	WithdrawRpTable    = "withdraw_rp"
+	FeesTable          = "fees"
+	OldFeesTable       = "fees_old"
+	CounterBuyBtcTable = "counter_buy_btc"
+)
+
+// default number of chunks of a single source query running at the same time
+const defaultChunkConcurrency = 4
+
+// queryChunks split [startDate, endDate) into sub-ranges of chunkDays and run query on each of them in parallel up to concurrency,
+// the result of every sub-range is returned in chronological order. the range is queried at once when chunkDays is not set.
+func queryChunks[T any](ctx context.Context, chunkDays, concurrency int, startDate, endDate int64, query func(ctx context.Context, startDate, endDate int64) ([]T, error)) ([][]T, error) {
+	chunkSize := int64(chunkDays) * 86400
+	if chunkSize <= 0 || endDate-startDate <= chunkSize {
+		result, err := query(ctx, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		return [][]T{result}, nil
+	}
+	if concurrency <= 0 {
+		concurrency = defaultChunkConcurrency
+	}
+	results := make([][]T, (endDate-startDate+chunkSize-1)/chunkSize)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i := range results {
+		i, chunkStart := i, startDate+int64(i)*chunkSize
+		chunkEnd := min(chunkStart+chunkSize, endDate)
+		g.Go(func() (err error) {
+			chunkCtx, span := tracing.Start(gctx, "TaxRepository.queryChunk",
+				attribute.Int64("chunk.start_date", chunkStart),
+				attribute.Int64("chunk.end_date", chunkEnd),
+			)
+			results[i], err = query(chunkCtx, chunkStart, chunkEnd)
+			span.SetAttributes(attribute.Int("db.rows", len(results[i])))
+			tracing.End(span, err)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// mergeChunks stitch the sub-range results grouped by day of month, a day split between two sub-ranges is summed.
+func mergeChunks[T any](chunks [][]T, dayOfMonth func(row *T) sql.NullInt64, add func(merged *T, row *T)) []T {
+	if len(chunks) == 1 {
+		return chunks[0]
+	}
+	merged := []T{}
+	days := map[int64]int{}
+	for _, chunk := range chunks {
+		for _, row := range chunk {
+			day := dayOfMonth(&row)
+			if !day.Valid {
+				continue
+			}
+			if i, ok := days[day.Int64]; ok {
+				add(&merged[i], &row)
+				continue
+			}
+			days[day.Int64] = len(merged)
+			merged = append(merged, row)
+		}
+	}
+	return merged
+}
+
+func addNullInt64(merged *sql.NullInt64, value sql.NullInt64) {
+	if value.Valid {
+		merged.Int64 += value.Int64
+		merged.Valid = true
+	}
+}
+
+func mergeDepositRpTotalAmount(chunks [][]entity.DepositRpTotalAmount) []entity.DepositRpTotalAmount {
+	return mergeChunks(chunks, func(row *entity.DepositRpTotalAmount) sql.NullInt64 {
+		return row.DayOfMonth
+	}, func(merged *entity.DepositRpTotalAmount, row *entity.DepositRpTotalAmount) {
+		addNullInt64(&merged.TotalRp, row.TotalRp)
+		addNullInt64(&merged.TotalAmount, row.TotalAmount)
+		addNullInt64(&merged.TotalSubsidiFee, row.TotalSubsidiFee)
+	})
+}
+
+func mergeTotalWithdrawRp(chunks [][]entity.TotalWithdrawRp) []entity.TotalWithdrawRp {
+	return mergeChunks(chunks, func(row *entity.TotalWithdrawRp) sql.NullInt64 {
+		return row.DayOfMonth
+	}, func(merged *entity.TotalWithdrawRp, row *entity.TotalWithdrawRp) {
+		addNullInt64(&merged.TotalRp, row.TotalRp)
+	})
+}
+
+func mergeTotalFees(chunks [][]entity.TotalFee) []entity.TotalFee {
+	return mergeChunks(chunks, func(row *entity.TotalFee) sql.NullInt64 {
+		return row.DayOfMonth
+	}, func(merged *entity.TotalFee, row *entity.TotalFee) {
+		addNullInt64(&merged.TotalFee, row.TotalFee)
+		addNullInt64(&merged.TotalUplineBonus, row.TotalUplineBonus)
+		addNullInt64(&merged.TotalRemain, row.TotalRemain)
+	})
+}
+
+func mergeCounterFees(chunks [][]entity.CounterFee) []entity.CounterFee {
+	return mergeChunks(chunks, func(row *entity.CounterFee) sql.NullInt64 {
+		return row.DayOfMonth
+	}, func(merged *entity.CounterFee, row *entity.CounterFee) {
+		addNullInt64(&merged.TotalFee, row.TotalFee)
+	})
+}
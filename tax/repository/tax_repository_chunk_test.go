@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sync"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryChunks(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test query whole range without chunk days",
+			testFunction: func(t *testing.T) {
+				ranges := [][2]int64{}
+				chunks, err := queryChunks(context.Background(), 0, 4, 1682899200, 1685577600, func(ctx context.Context, startDate, endDate int64) ([]int64, error) {
+					ranges = append(ranges, [2]int64{startDate, endDate})
+					return []int64{startDate}, nil
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, [][2]int64{{1682899200, 1685577600}}, ranges)
+				assert.Equal(t, [][]int64{{1682899200}}, chunks)
+			},
+		},
+		{
+			name: "test query split range into week chunks in chronological order",
+			testFunction: func(t *testing.T) {
+				mu := sync.Mutex{}
+				ranges := map[int64]int64{}
+				chunks, err := queryChunks(context.Background(), 7, 2, 1682899200, 1685577600, func(ctx context.Context, startDate, endDate int64) ([]int64, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					ranges[startDate] = endDate
+					return []int64{startDate}, nil
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, map[int64]int64{
+					1682899200: 1683504000,
+					1683504000: 1684108800,
+					1684108800: 1684713600,
+					1684713600: 1685318400,
+					1685318400: 1685577600,
+				}, ranges)
+				assert.Equal(t, [][]int64{{1682899200}, {1683504000}, {1684108800}, {1684713600}, {1685318400}}, chunks)
+			},
+		},
+		{
+			name: "test query return first error",
+			testFunction: func(t *testing.T) {
+				_, err := queryChunks(context.Background(), 1, 2, 1682899200, 1683158400, func(ctx context.Context, startDate, endDate int64) ([]int64, error) {
+					if startDate == 1682985600 {
+						return nil, errors.New("lock wait timeout exceeded")
+					}
+					return []int64{startDate}, nil
+				})
+				assert.EqualError(t, err, "lock wait timeout exceeded")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
+
+func TestMergeTotalFees(t *testing.T) {
+	day := func(dayOfMonth int64) sql.NullInt64 { return sql.NullInt64{Int64: dayOfMonth, Valid: true} }
+	amount := func(amount int64) sql.NullInt64 { return sql.NullInt64{Int64: amount, Valid: true} }
+	merged := mergeTotalFees([][]entity.TotalFee{
+		{
+			{DayOfMonth: day(1), TotalFee: amount(10), TotalUplineBonus: amount(1), TotalRemain: amount(9)},
+			{DayOfMonth: day(2), TotalFee: amount(20), TotalUplineBonus: amount(2), TotalRemain: amount(18)},
+		},
+		{
+			{DayOfMonth: day(2), TotalFee: amount(5), TotalUplineBonus: sql.NullInt64{}, TotalRemain: amount(5)},
+			{DayOfMonth: day(3), TotalFee: amount(30), TotalUplineBonus: amount(3), TotalRemain: amount(27)},
+		},
+	})
+	assert.Equal(t, []entity.TotalFee{
+		{DayOfMonth: day(1), TotalFee: amount(10), TotalUplineBonus: amount(1), TotalRemain: amount(9)},
+		{DayOfMonth: day(2), TotalFee: amount(25), TotalUplineBonus: amount(2), TotalRemain: amount(23)},
+		{DayOfMonth: day(3), TotalFee: amount(30), TotalUplineBonus: amount(3), TotalRemain: amount(27)},
+	}, merged)
+}
+
+func TestTaxRepository_GetFeesChunked(t *testing.T) {
+	sourceConn, sourceMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	serviceConn, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	expectConnectionID(sourceMock)
+	sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WithArgs(int64(1682899200), int64(1682985600)).
+		WillReturnRows(sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"}).AddRow("1", "10", "1", "9"))
+	expectConnectionID(sourceMock)
+	sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WithArgs(int64(1682985600), int64(1683072000)).
+		WillReturnRows(sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"}).AddRow("1", "5", "0", "5").AddRow("2", "20", "2", "18"))
+	taxRepository := NewTaxRepository(sourceConn, serviceConn, WithChunking(map[string]int{FeesTable: 1}, 1))
+	totalFees, err := taxRepository.GetFees(context.Background(), 1682899200, 1683072000)
+	assert.NoError(t, err)
+	assert.Len(t, totalFees, 2)
+	assert.Equal(t, int64(15), totalFees[0].TotalFee.Int64)
+	assert.Equal(t, int64(20), totalFees[1].TotalFee.Int64)
+	assert.NoError(t, sourceMock.ExpectationsWereMet())
+}
+
+func TestTaxRepository_Reload(t *testing.T) {
+	chunkDays := map[string]int{FeesTable: 1}
+	taxRepository := NewTaxRepository(nil, nil, WithQueryTimeout(time.Second, time.Second), WithChunking(map[string]int{FeesTable: 7}, 1)).(*taxRepository)
+	taxRepository.Reload(&domain.TaxSettings{SourceQueryTimeout: time.Minute, ChunkDays: chunkDays, ChunkConcurrency: 4})
+	chunkDays[FeesTable] = 30 // settings are copied
+	options := taxRepository.options.Load()
+	assert.Equal(t, time.Minute, options.SourceQueryTimeout)
+	assert.Equal(t, time.Duration(0), options.ServiceQueryTimeout)
+	assert.Equal(t, map[string]int{FeesTable: 1}, options.ChunkDays)
+	assert.Equal(t, 4, options.ChunkConcurrency)
+}
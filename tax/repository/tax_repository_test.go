@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"regexp"
+	"tax-aggregator-service-demo/pkg/dbconn"
+	"tax-aggregator-service-demo/tax/entity"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -30,9 +34,10 @@ func TestTaxRepository_GetDepositRpTotalAmount(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_rp", "total_amount", "total_subsidi_fee"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getDepositRpTotalAmount)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				depositRpTotalAmount, err := taxRepository.GetDepositRpTotalAmount(tt.startDate, tt.endDate)
+				depositRpTotalAmount, err := taxRepository.GetDepositRpTotalAmount(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, depositRpTotalAmount)
 			},
@@ -51,9 +56,10 @@ func TestTaxRepository_GetDepositRpTotalAmount(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_rp", "total_amount", "total_subsidi_fee"})
 				rows.AddRow("1", "3403357", "3403357", "10")
 				rows.AddRow("2", "3403358", "3403357", "20")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getDepositRpTotalAmount)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				depositRpTotalAmount, err := taxRepository.GetDepositRpTotalAmount(tt.startDate, tt.endDate)
+				depositRpTotalAmount, err := taxRepository.GetDepositRpTotalAmount(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, depositRpTotalAmount)
 			},
@@ -89,9 +95,10 @@ func TestTaxRepository_GetTotalWithdrawRp(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_rp"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getTotalWithdrawRp)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(tt.startDate, tt.endDate)
+				totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, totalWithdrawRp)
 			},
@@ -109,13 +116,36 @@ func TestTaxRepository_GetTotalWithdrawRp(t *testing.T) {
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_rp"})
 				rows.AddRow("1", "3403357")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getTotalWithdrawRp)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(tt.startDate, tt.endDate)
+				totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, totalWithdrawRp)
 			},
 		},
+		{
+			name: "test get total withdraw rp failed when a row can't be scanned",
+			args: args{
+				startDate: 1680321600,
+				endDate:   1682852400,
+			},
+			testFunction: func(t *testing.T, tt args) {
+				sourceConn, sourceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceConn, _, err := sqlmock.New()
+				assert.NoError(t, err)
+				rows := sqlmock.NewRows([]string{"day_of_month", "total_rp"})
+				rows.AddRow("1", "3403357")
+				rows.AddRow("2", "not a number")
+				expectConnectionID(sourceMock)
+				sourceMock.ExpectQuery(regexp.QuoteMeta(getTotalWithdrawRp)).WillReturnRows(rows)
+				taxRepository := NewTaxRepository(sourceConn, serviceConn)
+				totalWithdrawRp, err := taxRepository.GetTotalWithdrawRp(context.Background(), tt.startDate, tt.endDate)
+				assert.Error(t, err)
+				assert.Nil(t, totalWithdrawRp)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -147,9 +177,10 @@ func TestTaxRepository_GetFees(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				fees, err := taxRepository.GetFees(tt.startDate, tt.endDate)
+				fees, err := taxRepository.GetFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, fees)
 			},
@@ -168,9 +199,10 @@ func TestTaxRepository_GetFees(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
 				rows.AddRow("1", "10000", "20000", "20000")
 				rows.AddRow("2", "20000", "40000", "40000")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				fees, err := taxRepository.GetFees(tt.startDate, tt.endDate)
+				fees, err := taxRepository.GetFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, fees)
 			},
@@ -206,9 +238,10 @@ func TestTaxRepository_GetOldFees(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getOldFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				oldFees, err := taxRepository.GetOldFees(tt.startDate, tt.endDate)
+				oldFees, err := taxRepository.GetOldFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, oldFees)
 			},
@@ -227,9 +260,10 @@ func TestTaxRepository_GetOldFees(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
 				rows.AddRow("1", "10000", "20000", "20000")
 				rows.AddRow("2", "20000", "40000", "40000")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				oldFees, err := taxRepository.GetFees(tt.startDate, tt.endDate)
+				oldFees, err := taxRepository.GetFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, oldFees)
 			},
@@ -265,9 +299,10 @@ func TestTaxRepository_GetCounterFees(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getCounterFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				counterFees, err := taxRepository.GetCounterFees(tt.startDate, tt.endDate)
+				counterFees, err := taxRepository.GetCounterFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, counterFees)
 			},
@@ -286,9 +321,10 @@ func TestTaxRepository_GetCounterFees(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee"})
 				rows.AddRow("1", "10000")
 				rows.AddRow("2", "20000")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getCounterFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				counterFees, err := taxRepository.GetCounterFees(tt.startDate, tt.endDate)
+				counterFees, err := taxRepository.GetCounterFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, counterFees)
 			},
@@ -324,9 +360,10 @@ func TestTaxRepository_GetFeesPerDay(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				fees, err := taxRepository.GetFeesPerDay(tt.startDate, tt.endDate)
+				fees, err := taxRepository.GetFeesPerDay(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, fees)
 			},
@@ -344,9 +381,10 @@ func TestTaxRepository_GetFeesPerDay(t *testing.T) {
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
 				rows.AddRow("1", "100000", "20000", "20000")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				fees, err := taxRepository.GetFeesPerDay(tt.startDate, tt.endDate)
+				fees, err := taxRepository.GetFeesPerDay(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, fees)
 			},
@@ -382,9 +420,10 @@ func TestTaxRepository_GetOldFeesPerDay(t *testing.T) {
 				serviceConn, _, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getOldFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				oldFees, err := taxRepository.GetOldFees(tt.startDate, tt.endDate)
+				oldFees, err := taxRepository.GetOldFees(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, oldFees)
 			},
@@ -402,9 +441,10 @@ func TestTaxRepository_GetOldFeesPerDay(t *testing.T) {
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "total_fee", "total_upline_bonus", "total_remain"})
 				rows.AddRow("1", "100000", "20000", "20000")
+				expectConnectionID(sourceMock)
 				sourceMock.ExpectQuery(regexp.QuoteMeta(getOldFees)).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				oldFees, err := taxRepository.GetOldFeesPerDay(tt.startDate, tt.endDate)
+				oldFees, err := taxRepository.GetOldFeesPerDay(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, oldFees)
 			},
@@ -440,9 +480,9 @@ func TestTaxRepository_GetTaxTransaction(t *testing.T) {
 				serviceConn, serviceMock, err := sqlmock.New()
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "deposit_rp", "withdraw_rp", "fee", "upline_bonus", "remain", "ppn"})
-				serviceMock.ExpectQuery(regexp.QuoteMeta(getTaxTransactions)).WillReturnRows(rows)
+				serviceMock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(getTaxTransactions, "DATE_PART('day', TO_TIMESTAMP(t.transaction_date))"))).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				taxTransactions, err := taxRepository.GetTaxTransactions(tt.startDate, tt.endDate)
+				taxTransactions, err := taxRepository.GetTaxTransactions(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.Empty(t, taxTransactions)
 			},
@@ -460,9 +500,9 @@ func TestTaxRepository_GetTaxTransaction(t *testing.T) {
 				assert.NoError(t, err)
 				rows := sqlmock.NewRows([]string{"day_of_month", "deposit_rp", "withdraw_rp", "fee", "upline_bonus", "remain", "ppn"})
 				rows.AddRow("1", "1000000000", "500000000", "300000000", "30000", "30000", "200000")
-				serviceMock.ExpectQuery(regexp.QuoteMeta(getTaxTransactions)).WillReturnRows(rows)
+				serviceMock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(getTaxTransactions, "DATE_PART('day', TO_TIMESTAMP(t.transaction_date))"))).WillReturnRows(rows)
 				taxRepository := NewTaxRepository(sourceConn, serviceConn)
-				taxTransactions, err := taxRepository.GetTaxTransactions(tt.startDate, tt.endDate)
+				taxTransactions, err := taxRepository.GetTaxTransactions(context.Background(), tt.startDate, tt.endDate)
 				assert.NoError(t, err)
 				assert.NotNil(t, taxTransactions)
 			},
@@ -475,3 +515,58 @@ func TestTaxRepository_GetTaxTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestTaxRepository_InsertTaxTransactions(t *testing.T) {
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test insert tax transactions return days written when mysql updates a conflicting row",
+			testFunction: func(t *testing.T) {
+				sourceConn, _, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				taxRepository := NewTaxRepository(sourceConn, serviceConn).(*taxRepository)
+				taxRepository.serviceDialect = dbconn.Dialect{Driver: dbconn.DriverMySQL}
+				serviceMock.ExpectBegin()
+				// the first day is updated and counted twice, the second day is inserted
+				serviceMock.ExpectExec(regexp.QuoteMeta("ON DUPLICATE KEY UPDATE deposit_rp = VALUES(deposit_rp)")).WillReturnResult(sqlmock.NewResult(0, 3))
+				serviceMock.ExpectCommit()
+				days, err := taxRepository.InsertTaxTransactions(context.Background(), 1682899200, []entity.TaxTransaction{
+					{TransactionDate: 1682899200, DepositRp: 11},
+					{TransactionDate: 1682985600, DepositRp: 40},
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), days)
+				assert.NoError(t, serviceMock.ExpectationsWereMet())
+			},
+		},
+		{
+			name: "test insert tax transactions failed when exec failed",
+			testFunction: func(t *testing.T) {
+				sourceConn, _, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceConn, serviceMock, err := sqlmock.New()
+				assert.NoError(t, err)
+				serviceMock.ExpectBegin()
+				serviceMock.ExpectExec(regexp.QuoteMeta("INSERT INTO")).WillReturnError(fmt.Errorf("duplicate"))
+				serviceMock.ExpectRollback()
+				days, err := NewTaxRepository(sourceConn, serviceConn).InsertTaxTransactions(context.Background(), 1682899200, []entity.TaxTransaction{{TransactionDate: 1682899200}})
+				assert.Error(t, err)
+				assert.Zero(t, days)
+				assert.NoError(t, serviceMock.ExpectationsWereMet())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+// source queries run on a pinned connection whose id is queried first to be able to kill the query.
+func expectConnectionID(sourceMock sqlmock.Sqlmock) {
+	sourceMock.ExpectQuery(regexp.QuoteMeta(dbconn.MySQLConnectionID)).WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(42))
+}
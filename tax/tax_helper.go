@@ -1,6 +1,24 @@
 package tax
 
+import "time"
+
+// source database group transactions by Asia/Jakarta business day (UTC+7)
+const jakartaOffset = 7 * 3600
+
+var jakarta = time.FixedZone("Asia/Jakarta", jakartaOffset)
+
 func RoundDay(time int64) int64 {
 	rounding := (time + (7 * 86400)) % 86400
 	return (time - rounding)
 }
+
+// SourceDayStart return the start of Asia/Jakarta business day on source database for transaction date on service database.
+func SourceDayStart(transactionDate int64) int64 {
+	return RoundDay(transactionDate) - jakartaOffset
+}
+
+// LastClosedDay return transaction date of the latest Asia/Jakarta business day which already closed closeDelay ago.
+func LastClosedDay(now time.Time, closeDelay time.Duration) int64 {
+	today := now.Add(-closeDelay).In(jakarta)
+	return time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).Unix() - 86400
+}
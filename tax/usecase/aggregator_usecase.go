@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/tax"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"time"
+)
+
+type aggregatorUsecase struct {
+	taxUsecase           domain.TaxUsecase
+	aggregatorRepository domain.AggregatorRepository
+	aggregatorConfig     *domain.AggregatorConfig
+	now                  func() time.Time
+}
+
+func NewAggregatorUsecase(taxUsecase domain.TaxUsecase, aggregatorRepository domain.AggregatorRepository, aggregatorConfig *domain.AggregatorConfig) domain.AggregatorUsecase {
+	return &aggregatorUsecase{
+		taxUsecase:           taxUsecase,
+		aggregatorRepository: aggregatorRepository,
+		aggregatorConfig:     aggregatorConfig,
+		now:                  time.Now,
+	}
+}
+
+// Aggregate persist every closed business day after the high-water mark, so downtime is caught up on the next run.
+// the watermark is moved forward after each day, a failed day is retried on the next run.
+func (au *aggregatorUsecase) Aggregate(ctx context.Context) error {
+	lastClosedDay := tax.LastClosedDay(au.now(), au.aggregatorConfig.CloseDelay)
+	watermark, err := au.aggregatorRepository.GetAggregationWatermark(ctx, domain.DailyAggregationWatermark)
+	if err != nil {
+		return err
+	}
+	nextDay := lastClosedDay
+	if watermark != nil {
+		metrics.AggregationWatermark.WithLabelValues(domain.DailyAggregationWatermark).Set(float64(watermark.Watermark))
+		nextDay = watermark.Watermark + 86400
+	} else if au.aggregatorConfig.StartDate > 0 {
+		nextDay = tax.RoundDay(au.aggregatorConfig.StartDate)
+	}
+	for day := nextDay; day <= lastClosedDay; day += 86400 {
+		if err := au.taxUsecase.AggregateDay(ctx, day); err != nil {
+			slog.ErrorContext(ctx, "[AggregatorUsecase.Aggregate]:: error aggregating day.", slog.String("day", time.Unix(day, 0).UTC().Format(time.DateOnly)), logger.Err(err))
+			return err
+		}
+		if err := au.aggregatorRepository.UpsertAggregationWatermark(ctx, &entity.AggregationWatermark{
+			Name:      domain.DailyAggregationWatermark,
+			Watermark: day,
+			UpdatedAt: au.now().Unix(),
+		}); err != nil {
+			return err
+		}
+		metrics.AggregationWatermark.WithLabelValues(domain.DailyAggregationWatermark).Set(float64(day))
+		slog.InfoContext(ctx, "[AggregatorUsecase.Aggregate]:: aggregated day.", slog.String("day", time.Unix(day, 0).UTC().Format(time.DateOnly)))
+	}
+	return nil
+}
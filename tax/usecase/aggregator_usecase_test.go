@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/tax/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAggregatorUsecase_Aggregate(t *testing.T) {
+	// 2023-05-10 02:00 Asia/Jakarta, the last closed business day is 2023-05-09
+	now := func() time.Time { return time.Unix(1683658800, 0) }
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test aggregate catch up every day after watermark",
+			testFunction: func(t *testing.T) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				aggregatorRepository := mocks.NewAggregatorRepository(t)
+				aggregatorRepository.EXPECT().GetAggregationWatermark(mock.Anything, domain.DailyAggregationWatermark).Return(&entity.AggregationWatermark{
+					Name:      domain.DailyAggregationWatermark,
+					Watermark: 1683417600, // 2023-05-07
+				}, nil)
+				taxUsecase.EXPECT().AggregateDay(mock.Anything, int64(1683504000)).Return(nil).Once()
+				taxUsecase.EXPECT().AggregateDay(mock.Anything, int64(1683590400)).Return(nil).Once()
+				aggregatorRepository.EXPECT().UpsertAggregationWatermark(mock.Anything, mock.MatchedBy(func(watermark *entity.AggregationWatermark) bool {
+					return watermark.Watermark == 1683504000
+				})).Return(nil).Once()
+				aggregatorRepository.EXPECT().UpsertAggregationWatermark(mock.Anything, mock.MatchedBy(func(watermark *entity.AggregationWatermark) bool {
+					return watermark.Watermark == 1683590400
+				})).Return(nil).Once()
+
+				aggregatorUsecase := &aggregatorUsecase{
+					taxUsecase:           taxUsecase,
+					aggregatorRepository: aggregatorRepository,
+					aggregatorConfig:     &domain.AggregatorConfig{},
+					now:                  now,
+				}
+				assert.NoError(t, aggregatorUsecase.Aggregate(context.Background()))
+			},
+		},
+		{
+			name: "test aggregate only last closed day without watermark",
+			testFunction: func(t *testing.T) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				aggregatorRepository := mocks.NewAggregatorRepository(t)
+				aggregatorRepository.EXPECT().GetAggregationWatermark(mock.Anything, domain.DailyAggregationWatermark).Return(nil, nil)
+				taxUsecase.EXPECT().AggregateDay(mock.Anything, int64(1683590400)).Return(nil).Once()
+				aggregatorRepository.EXPECT().UpsertAggregationWatermark(mock.Anything, mock.Anything).Return(nil).Once()
+
+				aggregatorUsecase := &aggregatorUsecase{
+					taxUsecase:           taxUsecase,
+					aggregatorRepository: aggregatorRepository,
+					aggregatorConfig:     &domain.AggregatorConfig{},
+					now:                  now,
+				}
+				assert.NoError(t, aggregatorUsecase.Aggregate(context.Background()))
+			},
+		},
+		{
+			name: "test aggregate wait for close delay before aggregating",
+			testFunction: func(t *testing.T) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				aggregatorRepository := mocks.NewAggregatorRepository(t)
+				aggregatorRepository.EXPECT().GetAggregationWatermark(mock.Anything, domain.DailyAggregationWatermark).Return(&entity.AggregationWatermark{
+					Name:      domain.DailyAggregationWatermark,
+					Watermark: 1683504000, // 2023-05-08
+				}, nil)
+
+				aggregatorUsecase := &aggregatorUsecase{
+					taxUsecase:           taxUsecase,
+					aggregatorRepository: aggregatorRepository,
+					aggregatorConfig:     &domain.AggregatorConfig{CloseDelay: 3 * time.Hour},
+					now:                  now,
+				}
+				assert.NoError(t, aggregatorUsecase.Aggregate(context.Background()))
+			},
+		},
+		{
+			name: "test aggregate keep watermark when a day failed",
+			testFunction: func(t *testing.T) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				aggregatorRepository := mocks.NewAggregatorRepository(t)
+				aggregatorRepository.EXPECT().GetAggregationWatermark(mock.Anything, domain.DailyAggregationWatermark).Return(&entity.AggregationWatermark{
+					Name:      domain.DailyAggregationWatermark,
+					Watermark: 1683417600, // 2023-05-07
+				}, nil)
+				taxUsecase.EXPECT().AggregateDay(mock.Anything, int64(1683504000)).Return(errors.New("source database down")).Once()
+
+				aggregatorUsecase := &aggregatorUsecase{
+					taxUsecase:           taxUsecase,
+					aggregatorRepository: aggregatorRepository,
+					aggregatorConfig:     &domain.AggregatorConfig{},
+					now:                  now,
+				}
+				assert.Error(t, aggregatorUsecase.Aggregate(context.Background()))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"time"
+)
+
+type backfillUsecase struct {
+	taxUsecase         domain.TaxUsecase
+	taxRepository      domain.TaxRepository
+	backfillRepository domain.BackfillRepository
+	backfillConfig     *domain.BackfillConfig
+	now                func() time.Time
+}
+
+func NewBackfillUsecase(taxUsecase domain.TaxUsecase, taxRepository domain.TaxRepository, backfillRepository domain.BackfillRepository, backfillConfig *domain.BackfillConfig) domain.BackfillUsecase {
+	return &backfillUsecase{
+		taxUsecase:         taxUsecase,
+		taxRepository:      taxRepository,
+		backfillRepository: backfillRepository,
+		backfillConfig:     backfillConfig,
+		now:                time.Now,
+	}
+}
+
+// Backfill walks [from, to) one month chunk at a time through GetTax, chunks already checkpointed as done are skipped,
+// so re-running the same range after a crash continues from the chunks that were not finished.
+func (bu *backfillUsecase) Backfill(ctx context.Context, from, to int64) error {
+	today := bu.now().UTC().Truncate(24 * time.Hour).Unix()
+	if to > today { // only closed days are persisted by GetTax
+		to = today
+	}
+	chunks := BackfillChunks(from, to)
+	if len(chunks) == 0 {
+		slog.InfoContext(ctx, "[BackfillUsecase.Backfill]:: nothing to backfill.")
+		return nil
+	}
+	checkpoints, err := bu.backfillRepository.GetBackfillCheckpoints(ctx, chunks[0].StartDate, chunks[len(chunks)-1].EndDate)
+	if err != nil {
+		return err
+	}
+	doneChunks := map[int64]int64{}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Status == domain.BackfillStatusDone {
+			doneChunks[checkpoint.ChunkStart] = checkpoint.ChunkEnd
+		}
+	}
+	pendingChunks := []domain.BackfillChunk{}
+	for _, chunk := range chunks {
+		if chunkEnd, ok := doneChunks[chunk.StartDate]; ok && chunkEnd >= chunk.EndDate {
+			continue
+		}
+		pendingChunks = append(pendingChunks, chunk)
+	}
+	slog.InfoContext(ctx, "[BackfillUsecase.Backfill]:: chunks to process.", slog.Int("done", len(chunks)-len(pendingChunks)), slog.Int("total", len(chunks)), slog.Int("pending", len(pendingChunks)))
+
+	concurrency := bu.backfillConfig.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu        sync.Mutex
+		processed int
+		failed    int
+		started   = bu.now()
+	)
+	semaphore := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for _, chunk := range pendingChunks {
+		if ctx.Err() != nil { // stop dispatching chunks once cancelled, unfinished chunks are resumed on the next run
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(chunk domain.BackfillChunk) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			err := bu.backfillChunk(ctx, chunk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			processed++
+			if err != nil {
+				failed++
+				slog.ErrorContext(ctx, "[BackfillUsecase.Backfill]:: error backfilling chunk.", slog.String("chunk", chunkLabel(chunk)), logger.Err(err))
+			}
+			elapsed := bu.now().Sub(started)
+			eta := time.Duration(int64(elapsed) / int64(processed) * int64(len(pendingChunks)-processed))
+			slog.InfoContext(ctx, "[BackfillUsecase.Backfill]:: chunk finished.",
+				slog.String("chunk", chunkLabel(chunk)),
+				slog.Int("processed", processed),
+				slog.Int("pending", len(pendingChunks)),
+				slog.String("progress", fmt.Sprintf("%.1f%%", float64(processed)*100/float64(len(pendingChunks)))),
+				slog.Duration("elapsed", elapsed.Round(time.Second)),
+				slog.Duration("eta", eta.Round(time.Second)),
+			)
+		}(chunk)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("backfill failed on %d of %d chunks, re-run the same range to resume", failed, len(pendingChunks))
+	}
+	return nil
+}
+
+func (bu *backfillUsecase) backfillChunk(ctx context.Context, chunk domain.BackfillChunk) error {
+	checkpoint := &entity.BackfillCheckpoint{
+		ChunkStart: chunk.StartDate,
+		ChunkEnd:   chunk.EndDate,
+		Status:     domain.BackfillStatusDone,
+	}
+	taxTransactionSummaries, err := bu.taxRepository.GetTaxTransactions(ctx, chunk.StartDate, chunk.EndDate)
+	if err != nil {
+		return err
+	}
+	if len(taxTransactionSummaries) < chunk.AmountOfDays { // skip source queries when every day is already present
+		// GetTax fetch every day missing in the chunk, including gaps before or between persisted days
+		taxResponse, err := bu.taxUsecase.GetTax(ctx, &domain.TaxDate{
+			StartDate:    chunk.StartDate,
+			AmountOfDays: chunk.AmountOfDays,
+		})
+		if err != nil {
+			checkpoint.Status = domain.BackfillStatusFailed
+			checkpoint.UpdatedAt = bu.now().Unix()
+			if err := bu.backfillRepository.UpsertBackfillCheckpoint(ctx, checkpoint); err != nil {
+				slog.ErrorContext(ctx, "[BackfillUsecase.backfillChunk]:: error saving failed checkpoint.", logger.Err(err))
+			}
+			return err
+		}
+		checkpoint.DaysInserted = taxResponse.DaysWritten
+	}
+	checkpoint.UpdatedAt = bu.now().Unix()
+	return bu.backfillRepository.UpsertBackfillCheckpoint(ctx, checkpoint)
+}
+
+// BackfillChunks split [from, to) into calendar month chunks, the first chunk starts on the first day of from's month.
+func BackfillChunks(from, to int64) []domain.BackfillChunk {
+	chunks := []domain.BackfillChunk{}
+	if from >= to {
+		return chunks
+	}
+	fromTime := time.Unix(from, 0).UTC()
+	chunkStart := time.Date(fromTime.Year(), fromTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for chunkStart.Unix() < to {
+		chunkEnd := chunkStart.AddDate(0, 1, 0)
+		endDate := chunkEnd.Unix()
+		if endDate > to {
+			endDate = to
+		}
+		amountOfDays := int((endDate - chunkStart.Unix()) / 86400)
+		if amountOfDays > 0 {
+			chunks = append(chunks, domain.BackfillChunk{
+				StartDate:    chunkStart.Unix(),
+				EndDate:      chunkStart.Unix() + int64(amountOfDays*86400),
+				AmountOfDays: amountOfDays,
+			})
+		}
+		chunkStart = chunkEnd
+	}
+	return chunks
+}
+
+func chunkLabel(chunk domain.BackfillChunk) string {
+	return time.Unix(chunk.StartDate, 0).UTC().Format("2006-01-02") + "/" + time.Unix(chunk.EndDate, 0).UTC().Format("2006-01-02")
+}
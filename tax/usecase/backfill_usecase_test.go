@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/tax/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBackfillChunks(t *testing.T) {
+	// 2023-01-15 until 2023-03-10
+	chunks := BackfillChunks(1673740800, 1678406400)
+	assert.Equal(t, []domain.BackfillChunk{
+		{StartDate: 1672531200, EndDate: 1675209600, AmountOfDays: 31},
+		{StartDate: 1675209600, EndDate: 1677628800, AmountOfDays: 28},
+		{StartDate: 1677628800, EndDate: 1678406400, AmountOfDays: 9},
+	}, chunks)
+	assert.Empty(t, BackfillChunks(1678406400, 1678406400))
+}
+
+func TestBackfillUsecase_Backfill(t *testing.T) {
+	type args struct {
+		from int64
+		to   int64
+	}
+	tests := []struct {
+		name         string
+		args         args
+		testFunction func(t *testing.T, tt args)
+	}{
+		{
+			name: "test backfill skip done chunks and complete chunks",
+			args: args{
+				from: 1672531200, // 2023-01-01
+				to:   1677628800, // 2023-03-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				taxRepository := mocks.NewTaxRepository(t)
+				backfillRepository := mocks.NewBackfillRepository(t)
+				backfillRepository.EXPECT().GetBackfillCheckpoints(mock.Anything, int64(1672531200), int64(1677628800)).Return([]entity.BackfillCheckpoint{
+					{ChunkStart: 1672531200, ChunkEnd: 1675209600, Status: domain.BackfillStatusDone},
+				}, nil)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1675209600), int64(1677628800)).Return(make([]entity.TaxTransactionSummary, 28), nil)
+				backfillRepository.EXPECT().UpsertBackfillCheckpoint(mock.Anything, mock.MatchedBy(func(checkpoint *entity.BackfillCheckpoint) bool {
+					return checkpoint.ChunkStart == 1675209600 && checkpoint.Status == domain.BackfillStatusDone && checkpoint.DaysInserted == 0
+				})).Return(nil)
+
+				backfillUsecase := NewBackfillUsecase(taxUsecase, taxRepository, backfillRepository, &domain.BackfillConfig{Concurrency: 2})
+				assert.NoError(t, backfillUsecase.Backfill(context.Background(), tt.from, tt.to))
+			},
+		},
+		{
+			name: "test backfill fetch missing days and record failed chunks",
+			args: args{
+				from: 1672531200, // 2023-01-01
+				to:   1677628800, // 2023-03-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				taxRepository := mocks.NewTaxRepository(t)
+				backfillRepository := mocks.NewBackfillRepository(t)
+				backfillRepository.EXPECT().GetBackfillCheckpoints(mock.Anything, int64(1672531200), int64(1677628800)).Return(nil, nil)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1672531200), int64(1675209600)).Return(make([]entity.TaxTransactionSummary, 10), nil)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1675209600), int64(1677628800)).Return(nil, nil)
+				taxUsecase.EXPECT().GetTax(mock.Anything, &domain.TaxDate{StartDate: 1672531200, AmountOfDays: 31}).Return(&domain.TaxResponse{DaysWritten: 21}, nil)
+				taxUsecase.EXPECT().GetTax(mock.Anything, &domain.TaxDate{StartDate: 1675209600, AmountOfDays: 28}).Return(nil, errors.New("source database down"))
+				backfillRepository.EXPECT().UpsertBackfillCheckpoint(mock.Anything, mock.MatchedBy(func(checkpoint *entity.BackfillCheckpoint) bool {
+					return checkpoint.ChunkStart == 1672531200 && checkpoint.Status == domain.BackfillStatusDone && checkpoint.DaysInserted == 21
+				})).Return(nil)
+				backfillRepository.EXPECT().UpsertBackfillCheckpoint(mock.Anything, mock.MatchedBy(func(checkpoint *entity.BackfillCheckpoint) bool {
+					return checkpoint.ChunkStart == 1675209600 && checkpoint.Status == domain.BackfillStatusFailed
+				})).Return(nil)
+
+				backfillUsecase := NewBackfillUsecase(taxUsecase, taxRepository, backfillRepository, &domain.BackfillConfig{Concurrency: 1})
+				assert.Error(t, backfillUsecase.Backfill(context.Background(), tt.from, tt.to))
+			},
+		},
+		{
+			name: "test backfill never go past today",
+			args: args{
+				from: 1672531200, // 2023-01-01
+				to:   4102444800, // 2100-01-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxUsecase := mocks.NewTaxUsecase(t)
+				taxRepository := mocks.NewTaxRepository(t)
+				backfillRepository := mocks.NewBackfillRepository(t)
+				backfillRepository.EXPECT().GetBackfillCheckpoints(mock.Anything, int64(1672531200), int64(1673740800)).Return(nil, nil)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1672531200), int64(1673740800)).Return(make([]entity.TaxTransactionSummary, 14), nil)
+				backfillRepository.EXPECT().UpsertBackfillCheckpoint(mock.Anything, mock.Anything).Return(nil)
+
+				backfillUsecase := &backfillUsecase{
+					taxUsecase:         taxUsecase,
+					taxRepository:      taxRepository,
+					backfillRepository: backfillRepository,
+					backfillConfig:     &domain.BackfillConfig{Concurrency: 1},
+					now:                func() time.Time { return time.Unix(1673780000, 0) }, // 2023-01-15 11:33 UTC
+				}
+				assert.NoError(t, backfillUsecase.Backfill(context.Background(), tt.from, tt.to))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t, tt.args)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"sort"
+	"sync"
+	"tax-aggregator-service-demo/tax/domain"
+)
+
+// sourceFlights track the day ranges being computed from source database, so concurrent requests for the same
+// or overlapping ranges wait for the running computation of the days they share and only compute the other days.
+type sourceFlights struct {
+	mu      sync.Mutex
+	flights map[*sourceFlight]struct{}
+}
+
+// sourceFlight is the computation of [startDate, endDate), summaries are keyed by day start once done is closed.
+type sourceFlight struct {
+	startDate int64
+	endDate   int64
+	done      chan struct{}
+	once      sync.Once
+	summaries map[int64]domain.TaxSummary
+	err       error
+}
+
+func newSourceFlights() *sourceFlights {
+	return &sourceFlights{flights: map[*sourceFlight]struct{}{}}
+}
+
+// join split [startDate, endDate) into the flights already running over part of it, to wait for, and new flights
+// over the remaining gaps, registered for the caller to compute and finish.
+func (sf *sourceFlights) join(startDate, endDate int64) (leads, waits []*sourceFlight) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	for flight := range sf.flights {
+		if flight.startDate < endDate && flight.endDate > startDate {
+			waits = append(waits, flight)
+		}
+	}
+	sort.Slice(waits, func(i, j int) bool { return waits[i].startDate < waits[j].startDate })
+	gapStart := startDate
+	for _, flight := range waits {
+		if flight.startDate > gapStart {
+			leads = append(leads, sf.add(gapStart, flight.startDate))
+		}
+		gapStart = max(gapStart, flight.endDate)
+	}
+	if gapStart < endDate {
+		leads = append(leads, sf.add(gapStart, endDate))
+	}
+	return leads, waits
+}
+
+func (sf *sourceFlights) add(startDate, endDate int64) *sourceFlight {
+	flight := &sourceFlight{startDate: startDate, endDate: endDate, done: make(chan struct{})}
+	sf.flights[flight] = struct{}{}
+	return flight
+}
+
+// finish publish the result of flight to its waiters and forget it, later requests read the persisted days
+// from service database. only the first call has effect.
+func (sf *sourceFlights) finish(flight *sourceFlight, summaries map[int64]domain.TaxSummary, err error) {
+	flight.once.Do(func() {
+		sf.mu.Lock()
+		delete(sf.flights, flight)
+		sf.mu.Unlock()
+		flight.summaries = summaries
+		flight.err = err
+		close(flight.done)
+	})
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceFlights_Join(t *testing.T) {
+	ranges := func(flights []*sourceFlight) [][2]int64 {
+		dateRanges := [][2]int64{}
+		for _, flight := range flights {
+			dateRanges = append(dateRanges, [2]int64{flight.startDate, flight.endDate})
+		}
+		return dateRanges
+	}
+	sourceFlights := newSourceFlights()
+	leads, waits := sourceFlights.join(10, 20)
+	assert.Equal(t, [][2]int64{{10, 20}}, ranges(leads))
+	assert.Empty(t, waits)
+	running := leads[0]
+
+	leads, waits = sourceFlights.join(30, 40)
+	assert.Equal(t, [][2]int64{{30, 40}}, ranges(leads))
+	assert.Empty(t, waits)
+
+	leads, waits = sourceFlights.join(0, 50)
+	assert.Equal(t, [][2]int64{{0, 10}, {20, 30}, {40, 50}}, ranges(leads))
+	assert.Equal(t, [][2]int64{{10, 20}, {30, 40}}, ranges(waits))
+
+	leads, waits = sourceFlights.join(15, 18)
+	assert.Empty(t, leads)
+	assert.Equal(t, [][2]int64{{10, 20}}, ranges(waits))
+
+	errSource := errors.New("source database down")
+	sourceFlights.finish(running, nil, errSource)
+	sourceFlights.finish(running, nil, nil) // only the first finish has effect
+	<-running.done
+	assert.ErrorIs(t, running.err, errSource)
+	leads, _ = sourceFlights.join(10, 20)
+	assert.Equal(t, [][2]int64{{10, 20}}, ranges(leads))
+}
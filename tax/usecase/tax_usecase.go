@@ -1,33 +1,155 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
 	"math"
-	"sync"
+	"slices"
+	"sync/atomic"
+	"tax-aggregator-service-demo/pkg/cache"
+	"tax-aggregator-service-demo/pkg/logger"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+	"tax-aggregator-service-demo/pkg/tracing"
 	"tax-aggregator-service-demo/tax"
 	"tax-aggregator-service-demo/tax/domain"
 	"tax-aggregator-service-demo/tax/entity"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// fees table migration date on source database, fees before migration are only in fees_old
+// and fees on the migration date are split between fees_old and fees.
+const (
+	feesMigrationStart = 1662742800
+	feesMigrationEnd   = 1662829200
 )
 
+// default number of source queries running at the same time for a single FetchSourceTax
+const defaultSourceConcurrency = 5
+
 type taxUsecase struct {
 	taxRepository domain.TaxRepository
-	taxConfig     *domain.TaxConfig
+	taxConfig     atomic.Pointer[domain.TaxConfig]
+	options       atomic.Pointer[TaxUsecaseOptions]
+	resultCache   *cache.LRU[taxCacheKey, domain.TaxResponse]
+	sourceFetches *ratelimit.Semaphore
+	sourceFlights *sourceFlights
+}
+
+// result cache key of GetTax, start date is rounded into the day
+type taxCacheKey struct {
+	StartDate    int64
+	AmountOfDays int
+}
+
+type TaxUsecaseOptions struct {
+	SourceFallback    bool
+	SourceConcurrency int
+	ResultCacheSize   int
+	ResultCacheTTL    time.Duration
+	// SourceFetchConcurrency bound FetchSourceTax running at once across every caller, unbounded when not set
+	SourceFetchConcurrency  int
+	SourceFetchQueueSize    int
+	SourceFetchQueueTimeout time.Duration
+}
+
+type TaxUsecaseOption func(*TaxUsecaseOptions)
+
+// WithSourceFallback toggle querying source database on request path when days are missing in service database,
+// disabled when the aggregator is responsible for persisting every closed day.
+func WithSourceFallback(sourceFallback bool) TaxUsecaseOption {
+	return func(options *TaxUsecaseOptions) {
+		options.SourceFallback = sourceFallback
+	}
+}
+
+// WithSourceConcurrency bound the number of source queries running at the same time for a single FetchSourceTax.
+func WithSourceConcurrency(sourceConcurrency int) TaxUsecaseOption {
+	return func(options *TaxUsecaseOptions) {
+		if sourceConcurrency > 0 {
+			options.SourceConcurrency = sourceConcurrency
+		}
+	}
+}
+
+// WithResultCache keep up to size GetTax responses of ranges fully persisted in service database for ttl,
+// responses are invalidated whenever a day inside their range is written. disabled when size is not set.
+func WithResultCache(size int, ttl time.Duration) TaxUsecaseOption {
+	return func(options *TaxUsecaseOptions) {
+		options.ResultCacheSize = size
+		options.ResultCacheTTL = ttl
+	}
 }
 
-func NewTaxUsecase(taxRepository domain.TaxRepository, taxConfig *domain.TaxConfig) domain.TaxUsecase {
-	return &taxUsecase{
+// WithSourceFetchLimit bound the number of FetchSourceTax running at once to concurrency, up to queue size calls wait
+// for queue timeout for a free slot, the others fail right away with domain.ErrSourceBusy.
+func WithSourceFetchLimit(concurrency, queueSize int, queueTimeout time.Duration) TaxUsecaseOption {
+	return func(options *TaxUsecaseOptions) {
+		options.SourceFetchConcurrency = concurrency
+		options.SourceFetchQueueSize = queueSize
+		options.SourceFetchQueueTimeout = queueTimeout
+	}
+}
+
+func NewTaxUsecase(taxRepository domain.TaxRepository, taxConfig *domain.TaxConfig, opts ...TaxUsecaseOption) domain.TaxUsecase {
+	options := TaxUsecaseOptions{
+		SourceFallback:    true,
+		SourceConcurrency: defaultSourceConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	taxUsecase := &taxUsecase{
 		taxRepository: taxRepository,
-		taxConfig:     taxConfig,
+		sourceFlights: newSourceFlights(),
 	}
+	taxUsecase.taxConfig.Store(taxConfig)
+	taxUsecase.options.Store(&options)
+	if options.ResultCacheSize > 0 {
+		taxUsecase.resultCache = cache.NewLRU[taxCacheKey, domain.TaxResponse](options.ResultCacheSize, options.ResultCacheTTL)
+	}
+	taxUsecase.sourceFetches = ratelimit.NewSemaphore(options.SourceFetchConcurrency, options.SourceFetchQueueSize, options.SourceFetchQueueTimeout)
+	return taxUsecase
 }
 
-func (tu *taxUsecase) GetTax(taxDate *domain.TaxDate) (*domain.TaxResponse, error) {
-	taxResponse := &domain.TaxResponse{}
+func (tu *taxUsecase) GetTax(ctx context.Context, taxDate *domain.TaxDate) (*domain.TaxResponse, error) {
+	ctx, span := tracing.Start(ctx, "TaxUsecase.GetTax",
+		attribute.Int64("tax.start_date", taxDate.StartDate),
+		attribute.Int("tax.amount_of_days", taxDate.AmountOfDays),
+	)
+	taxResponse, err := tu.getTax(ctx, taxDate)
+	if err == nil {
+		span.SetAttributes(attribute.String("tax.source", taxResponse.Source), attribute.String("tax.cache", taxResponse.Cache))
+	}
+	tracing.End(span, err)
+	return taxResponse, err
+}
+
+func (tu *taxUsecase) getTax(ctx context.Context, taxDate *domain.TaxDate) (*domain.TaxResponse, error) {
+	taxResponse := &domain.TaxResponse{Source: domain.TaxSourceCache}
 	summaries := []domain.TaxSummary{}
 	aggregateFees := []domain.AggregateFee{}
 	bankFee := 0
 	beginDate := tax.RoundDay(taxDate.StartDate)
 	endDate := beginDate + int64(taxDate.AmountOfDays*86400)
+	cacheKey := taxCacheKey{StartDate: beginDate, AmountOfDays: taxDate.AmountOfDays}
+	var cacheGeneration uint64
+	if tu.resultCache != nil {
+		if cached, ok := tu.resultCache.Get(cacheKey); ok {
+			cached.Summary = slices.Clone(cached.Summary)
+			cached.Cache = domain.TaxCacheHit
+			return &cached, nil
+		}
+		cacheGeneration = tu.resultCache.Generation()
+		taxResponse.Cache = domain.TaxCacheMiss
+	}
 
 	if beginDate < 1393632000 { // if the start_time is on February 2014 special case
 		for i := 1; i <= int(taxDate.AmountOfDays); i++ {
@@ -48,26 +170,14 @@ func (tu *taxUsecase) GetTax(taxDate *domain.TaxDate) (*domain.TaxResponse, erro
 			aggregateFees = append(aggregateFees, *aggregateFee)
 		}
 	}
-	dayToBeQueried := 1
-	taxTransactionValid := true
-	taxTransactionSummaries, err := tu.taxRepository.GetTaxTransactions(beginDate, endDate)
+	taxTransactionSummaries, err := tu.taxRepository.GetTaxTransactions(ctx, beginDate, endDate)
 	if err != nil {
 		return nil, err
 	}
-	if len(taxTransactionSummaries) == 0 {
-		taxTransactionValid = false
-	} else {
-		if len(taxTransactionSummaries) < int(taxDate.AmountOfDays) {
-			for _, tts := range taxTransactionSummaries {
-				if int64(dayToBeQueried) < tts.DayOfMonth {
-					dayToBeQueried = int(tts.DayOfMonth)
-				}
-			}
-			dayToBeQueried += 1
-			taxTransactionValid = false
-		}
-	}
+	taxTransactionValid := len(taxTransactionSummaries) > 0 && len(taxTransactionSummaries) >= int(taxDate.AmountOfDays)
+	persistedDays := map[int64]bool{}
 	for _, serviceTax := range taxTransactionSummaries {
+		persistedDays[serviceTax.DayOfMonth] = true
 		summaries[serviceTax.DayOfMonth-1].DepositRp = serviceTax.DepositRp
 		summaries[serviceTax.DayOfMonth-1].WithdrawRp = serviceTax.WithdrawRp
 		summaries[serviceTax.DayOfMonth-1].Fee = serviceTax.Fee
@@ -80,194 +190,329 @@ func (tu *taxUsecase) GetTax(taxDate *domain.TaxDate) (*domain.TaxResponse, erro
 		taxResponse.TotalRemain += serviceTax.Remain
 		taxResponse.TotalPpn += serviceTax.Ppn
 	}
-	if taxTransactionValid { // only valid if data is fully existed on service database.
+	if taxTransactionValid || !tu.options.Load().SourceFallback || taxDate.PersistedOnly { // only valid if data is fully existed on service database.
+		taxResponse.Summary = summaries
+		if taxTransactionValid && tu.resultCache != nil { // only closed days are persisted, so a fully persisted range is final
+			cached := *taxResponse
+			cached.Summary = slices.Clone(summaries)
+			tu.resultCache.Add(cacheKey, cached, cacheGeneration)
+		}
 		return taxResponse, nil
 	} else { // if the data is not fully available in service database, query to source database & save to service database.
-		continueDate := (beginDate + (int64(dayToBeQueried-1) * 86400))
-		taxResponseFromSource, err := tu.FetchSourceTax(&domain.TaxSourceDate{
-			StartDate:    continueDate,
-			EndDate:      endDate,
-			StartDay:     dayToBeQueried,
-			AmountOfDays: taxDate.AmountOfDays - (dayToBeQueried - 1),
-		})
+		sourceSummaries, daysWritten, err := tu.fetchSourceDays(ctx, beginDate, missingDayRanges(beginDate, taxDate.AmountOfDays, persistedDays))
 		if err != nil {
 			return nil, err
 		}
-
-		taxTransactions := []entity.TaxTransaction{}
-		for _, trfs := range taxResponseFromSource.Summary {
-			if continueDate < 1393632000 {
-				dayToBeQueried = 15
-			}
-			summaries[trfs.DayOfMonth-1].DepositRp = trfs.DepositRp
-			summaries[trfs.DayOfMonth-1].WithdrawRp = trfs.WithdrawRp
-			summaries[trfs.DayOfMonth-1].Fee = trfs.Fee
-			summaries[trfs.DayOfMonth-1].UplineBonus = trfs.UplineBonus
-			summaries[trfs.DayOfMonth-1].Remain = trfs.Remain
-			summaries[trfs.DayOfMonth-1].Ppn = trfs.Ppn
-
-			taxTransaction := new(entity.TaxTransaction)
-			taxTransaction.TransactionDate = beginDate + (int64(trfs.DayOfMonth-1) * 86400)
-			taxTransaction.DepositRp = trfs.DepositRp
-			taxTransaction.WithdrawRp = trfs.WithdrawRp
-			taxTransaction.Fee = trfs.Fee
-			taxTransaction.UplineBonus = trfs.UplineBonus
-			taxTransaction.Remain = trfs.Remain
-			taxTransaction.Ppn = trfs.Ppn
-
-			if (time.Now().Year() == time.Unix(beginDate, 0).Year()) && (time.Now().Month() == time.Unix(beginDate, 0).Month()) && (trfs.DayOfMonth >= time.Now().Day()) {
+		taxResponse.Source = domain.TaxSourceSource
+		for day := beginDate; day < endDate; day += 86400 {
+			sourceSummary, ok := sourceSummaries[day]
+			index := int((day - beginDate) / 86400)
+			if !ok || index >= len(summaries) {
 				continue
-			} else {
-				taxTransactions = append(taxTransactions, *taxTransaction)
-			}
-		}
-		taxResponse.TotalRevenue += taxResponseFromSource.TotalRevenue
-		taxResponse.TotalBankFee += taxResponseFromSource.TotalBankFee
-		taxResponse.TotalUplineBonus += taxResponseFromSource.TotalUplineBonus
-		taxResponse.TotalRemain += taxResponseFromSource.TotalRemain
-		taxResponse.TotalPpn += taxResponseFromSource.TotalPpn
-		if len(taxTransactions) > 0 {
-			if err := tu.taxRepository.InsertTaxTransactions(continueDate, taxTransactions); err != nil {
-				return nil, err
 			}
+			summaries[index].DepositRp = sourceSummary.DepositRp
+			summaries[index].WithdrawRp = sourceSummary.WithdrawRp
+			summaries[index].Fee = sourceSummary.Fee
+			summaries[index].UplineBonus = sourceSummary.UplineBonus
+			summaries[index].Remain = sourceSummary.Remain
+			summaries[index].Ppn = sourceSummary.Ppn
+			taxResponse.TotalRevenue += sourceSummary.Fee
+			taxResponse.TotalBankFee += int64(bankFee)
+			taxResponse.TotalUplineBonus += sourceSummary.UplineBonus
+			taxResponse.TotalRemain += sourceSummary.Remain
+			taxResponse.TotalPpn += sourceSummary.Ppn
 		}
+		taxResponse.DaysWritten = daysWritten
 	}
 
 	taxResponse.Summary = summaries
 	return taxResponse, nil
 }
 
-func (tu *taxUsecase) FetchSourceTax(taxSourceDate *domain.TaxSourceDate) (*domain.TaxResponse, error) {
-	taxResponse := &domain.TaxResponse{}
-	var summaries []domain.TaxSummary
-	var aggregateFees []domain.AggregateFee
-	bankFee := 0
-	if taxSourceDate.StartDate < 1393632000 { // if startDate is on February 2014 special calculation from 15 February (Special case)
-		taxSourceDate.StartDay = 15
-		for i := 1; i <= int(taxSourceDate.AmountOfDays); i++ {
-			summary := new(domain.TaxSummary)
-			aggregateFee := new(domain.AggregateFee)
-			aggregateFee.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
-			summary.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
-			summaries = append(summaries, *summary)
-			aggregateFees = append(aggregateFees, *aggregateFee)
+// missingDayRanges return the runs of days of the range starting at beginDate which are not persisted in service database,
+// persisted days are keyed by day of month, eg: a day skipped by the aggregator in the middle of the month is its own run.
+func missingDayRanges(beginDate int64, amountOfDays int, persistedDays map[int64]bool) [][2]int64 {
+	dateRanges := [][2]int64{}
+	for i := 0; i < amountOfDays; i++ {
+		if persistedDays[int64(i+1)] {
+			continue
 		}
-	} else {
-		for i := 1; i <= int(taxSourceDate.AmountOfDays); i++ {
-			summary := new(domain.TaxSummary)
-			aggregateFee := new(domain.AggregateFee)
-			aggregateFee.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
-			summary.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
-			summaries = append(summaries, *summary)
-			aggregateFees = append(aggregateFees, *aggregateFee)
+		day := beginDate + int64(i)*86400
+		if last := len(dateRanges) - 1; last >= 0 && dateRanges[last][1] == day {
+			dateRanges[last][1] = day + 86400
+			continue
 		}
+		dateRanges = append(dateRanges, [2]int64{day, day + 86400})
 	}
+	return dateRanges
+}
 
-	depositRpTotalAmount, err := tu.taxRepository.GetDepositRpTotalAmount(taxSourceDate.StartDate, taxSourceDate.EndDate)
-	if err != nil {
-		return nil, err
-	}
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func([]entity.DepositRpTotalAmount) {
-		for _, depositRp := range depositRpTotalAmount {
-			if !depositRp.DayOfMonth.Valid {
-				continue
+// fetchSourceDays compute dateRanges of the range starting at beginDate from source database and persist
+// the closed days, days already being computed by a concurrent request are waited for instead of computed again.
+// when the request computing them went away its days are computed again. summaries are keyed by day start.
+func (tu *taxUsecase) fetchSourceDays(ctx context.Context, beginDate int64, dateRanges [][2]int64) (map[int64]domain.TaxSummary, int64, error) {
+	summaries := map[int64]domain.TaxSummary{}
+	var daysWritten int64
+	pending := dateRanges
+	for len(pending) > 0 {
+		var retry [][2]int64
+		for _, dateRange := range pending {
+			leads, waits := tu.sourceFlights.join(dateRange[0], dateRange[1])
+			// computed before waiting, so requests never wait on each other in a cycle
+			rows, err := tu.computeSourceLeads(ctx, beginDate, leads, summaries)
+			if err != nil {
+				return nil, 0, err
 			}
-			summaries[int(depositRp.DayOfMonth.Int64)-taxSourceDate.StartDay].DepositRp = depositRp.TotalAmount.Int64
-		}
-		wg.Done()
-	}(depositRpTotalAmount)
-
-	withdrawRpTotalAmount, err := tu.taxRepository.GetTotalWithdrawRp(taxSourceDate.StartDate, taxSourceDate.EndDate)
-	if err != nil {
-		return nil, err
-	}
-	wg.Add(1)
-	go func([]entity.TotalWithdrawRp) {
-		for _, withdrawRp := range withdrawRpTotalAmount {
-			if !withdrawRp.DayOfMonth.Valid {
-				continue
+			daysWritten += rows
+			for _, flight := range waits {
+				metrics.SourceFetchesCoalesced.Inc()
+				select {
+				case <-flight.done:
+				case <-ctx.Done():
+					return nil, 0, ctx.Err()
+				}
+				overlapStart, overlapEnd := max(flight.startDate, dateRange[0]), min(flight.endDate, dateRange[1])
+				if flight.err != nil {
+					if ctx.Err() == nil && (errors.Is(flight.err, context.Canceled) || errors.Is(flight.err, context.DeadlineExceeded)) {
+						retry = append(retry, [2]int64{overlapStart, overlapEnd})
+						continue
+					}
+					return nil, 0, flight.err
+				}
+				for day := overlapStart; day < overlapEnd; day += 86400 {
+					if summary, ok := flight.summaries[day]; ok {
+						summaries[day] = summary
+					}
+				}
 			}
-			summaries[int(withdrawRp.DayOfMonth.Int64)-taxSourceDate.StartDay].DepositRp = withdrawRp.TotalRp.Int64
 		}
-	}(withdrawRpTotalAmount)
+		pending = retry
+	}
+	return summaries, daysWritten, nil
+}
 
-	if (taxSourceDate.StartDate <= 1662742800) && (taxSourceDate.EndDate >= 1662829199) {
-		oldFees, err := tu.taxRepository.GetOldFees(taxSourceDate.StartDate, 1662742800)
-		if err != nil {
-			return nil, err
+// computeSourceLeads compute the flights led by the request into summaries. every lead is finished when one of them
+// failed or panicked, the panic is published to the waiters as an error before being raised again.
+func (tu *taxUsecase) computeSourceLeads(ctx context.Context, beginDate int64, leads []*sourceFlight, summaries map[int64]domain.TaxSummary) (daysWritten int64, err error) {
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			err = fmt.Errorf("computing source days panicked: %v", recovered)
 		}
-		wg.Add(1)
-		go func([]entity.TotalFee) {
-			for _, oldFee := range oldFees {
-				if !oldFee.DayOfMonth.Valid {
-					continue
-				}
-				aggregateFees[int(oldFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalFee = oldFee.TotalFee.Int64
-				aggregateFees[int(oldFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalRemain = oldFee.TotalRemain.Int64
-				aggregateFees[int(oldFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalUplineBonus = oldFee.TotalUplineBonus.Int64
-			}
-			wg.Done()
-		}(oldFees)
-
-		migrationNewFees, err := tu.taxRepository.GetFeesPerDay(1662742800, 1662829200)
 		if err != nil {
-			return nil, err
+			for _, flight := range leads {
+				tu.sourceFlights.finish(flight, nil, err)
+			}
 		}
-
-		migrationOldFees, err := tu.taxRepository.GetOldFeesPerDay(1662742800, 1662829200)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+	for _, flight := range leads {
+		flightSummaries, rows, err := tu.computeSourceFlight(ctx, beginDate, flight)
+		tu.sourceFlights.finish(flight, flightSummaries, err)
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
+		daysWritten += rows
+		maps.Copy(summaries, flightSummaries)
+	}
+	return daysWritten, nil
+}
 
-		migrationFees := &domain.AggregateFee{
-			DayOfMonth:       int(migrationNewFees.DayOfMonth.Int64),
-			TotalFee:         (migrationNewFees.TotalFee.Int64 + migrationOldFees.TotalFee.Int64),
-			TotalUplineBonus: (migrationNewFees.TotalUplineBonus.Int64 + migrationOldFees.TotalUplineBonus.Int64),
-			TotalRemain:      (migrationNewFees.TotalRemain.Int64 + migrationOldFees.TotalRemain.Int64),
+// computeSourceFlight fetch the days of flight from source database and insert the closed ones into service database.
+// days are read by Asia/Jakarta business day like AggregateDay, so a transaction date covers the same source rows whoever wrote it.
+func (tu *taxUsecase) computeSourceFlight(ctx context.Context, beginDate int64, flight *sourceFlight) (map[int64]domain.TaxSummary, int64, error) {
+	taxResponse, err := tu.FetchSourceTax(ctx, &domain.TaxSourceDate{
+		StartDate:    tax.SourceDayStart(flight.startDate),
+		EndDate:      tax.SourceDayStart(flight.endDate),
+		StartDay:     int((flight.startDate-beginDate)/86400) + 1,
+		AmountOfDays: int((flight.endDate - flight.startDate) / 86400),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	summaries := map[int64]domain.TaxSummary{}
+	taxTransactions := []entity.TaxTransaction{}
+	for _, summary := range taxResponse.Summary {
+		transactionDate := beginDate + (int64(summary.DayOfMonth-1) * 86400)
+		summaries[transactionDate] = summary
+		if (time.Now().Year() == time.Unix(beginDate, 0).Year()) && (time.Now().Month() == time.Unix(beginDate, 0).Month()) && (summary.DayOfMonth >= time.Now().Day()) {
+			continue
 		}
-		aggregateFees[migrationFees.DayOfMonth-taxSourceDate.StartDay].TotalFee = migrationFees.TotalFee
-		aggregateFees[migrationFees.DayOfMonth-taxSourceDate.StartDay].TotalRemain = migrationFees.TotalRemain
-		aggregateFees[migrationFees.DayOfMonth-taxSourceDate.StartDay].TotalUplineBonus = migrationFees.TotalUplineBonus
+		taxTransactions = append(taxTransactions, entity.TaxTransaction{
+			TransactionDate: transactionDate,
+			DepositRp:       summary.DepositRp,
+			WithdrawRp:      summary.WithdrawRp,
+			Fee:             summary.Fee,
+			UplineBonus:     summary.UplineBonus,
+			Remain:          summary.Remain,
+			Ppn:             summary.Ppn,
+		})
+	}
+	if len(taxTransactions) == 0 {
+		return summaries, 0, nil
+	}
+	daysWritten, err := tu.taxRepository.InsertTaxTransactions(ctx, flight.startDate, taxTransactions)
+	if err != nil {
+		return nil, 0, err
+	}
+	tu.InvalidateTax(ctx, flight.startDate, flight.endDate)
+	return summaries, daysWritten, nil
+}
 
-		newFees, err := tu.taxRepository.GetFees(1662829200, taxSourceDate.EndDate) // fees calculation with fees after migration date
-		if err != nil {
-			return nil, err
+// FetchSourceTax issue every source query in parallel, bounded by source concurrency, the first failed query cancel the others.
+// results are merged after every query returned so the summaries don't depend on the order the queries finished.
+// it waits for a slot of the source fetch limit first, domain.ErrSourceBusy is returned when none is free in time.
+func (tu *taxUsecase) FetchSourceTax(ctx context.Context, taxSourceDate *domain.TaxSourceDate) (*domain.TaxResponse, error) {
+	ctx, span := tracing.Start(ctx, "TaxUsecase.FetchSourceTax",
+		attribute.Int64("tax.start_date", taxSourceDate.StartDate),
+		attribute.Int64("tax.end_date", taxSourceDate.EndDate),
+		attribute.Int("tax.amount_of_days", taxSourceDate.AmountOfDays),
+	)
+	release, err := tu.acquireSourceFetch(ctx)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	defer release()
+	taxResponse, err := tu.fetchSourceTax(ctx, taxSourceDate)
+	tracing.End(span, err)
+	return taxResponse, err
+}
+
+func (tu *taxUsecase) acquireSourceFetch(ctx context.Context) (func(), error) {
+	release, err := tu.sourceFetches.Acquire(ctx)
+	switch {
+	case errors.Is(err, ratelimit.ErrQueueFull):
+		metrics.RateLimited.WithLabelValues(metrics.RateLimitReasonSourceQueueFull).Inc()
+	case errors.Is(err, ratelimit.ErrQueueTimeout):
+		metrics.RateLimited.WithLabelValues(metrics.RateLimitReasonSourceQueueTimeout).Inc()
+	default:
+		return release, err
+	}
+	running, queued := tu.sourceFetches.InFlight()
+	slog.WarnContext(ctx, "[TaxUsecase.FetchSourceTax]:: source fetch limit reached.", slog.Int("running", running), slog.Int("queued", queued), logger.Err(err))
+	return nil, fmt.Errorf("%w: %w", domain.ErrSourceBusy, err)
+}
+
+func (tu *taxUsecase) fetchSourceTax(ctx context.Context, taxSourceDate *domain.TaxSourceDate) (*domain.TaxResponse, error) {
+	taxResponse := &domain.TaxResponse{}
+	var summaries []domain.TaxSummary
+	var aggregateFees []domain.AggregateFee
+	bankFee := 0
+	if taxSourceDate.StartDate < tax.SourceDayStart(1393632000) { // if startDate is on February 2014 special calculation from 15 February (Special case)
+		taxSourceDate.StartDay = 15
+	}
+	for i := 1; i <= int(taxSourceDate.AmountOfDays); i++ {
+		summary := new(domain.TaxSummary)
+		aggregateFee := new(domain.AggregateFee)
+		aggregateFee.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
+		summary.DayOfMonth = int(taxSourceDate.StartDay) + (i - 1)
+		summaries = append(summaries, *summary)
+		aggregateFees = append(aggregateFees, *aggregateFee)
+	}
+	dayIndex := func(dayOfMonth sql.NullInt64) int {
+		index := int(dayOfMonth.Int64) - taxSourceDate.StartDay
+		if !dayOfMonth.Valid || index < 0 || index >= len(summaries) {
+			return -1
 		}
-		wg.Add(1)
-		go func([]entity.TotalFee) {
-			for _, newFee := range newFees {
-				if !newFee.DayOfMonth.Valid {
-					continue
-				}
-				aggregateFees[int(newFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalFee = newFee.TotalFee.Int64
-				aggregateFees[int(newFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalUplineBonus = newFee.TotalUplineBonus.Int64
-				aggregateFees[int(newFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalRemain = newFee.TotalRemain.Int64
-			}
-			wg.Done()
-		}(newFees)
+		return index
 	}
-	wg.Wait()
 
-	counterFees, err := tu.taxRepository.GetCounterFees(taxSourceDate.StartDate, taxSourceDate.EndDate)
-	if err != nil {
+	var (
+		depositRpTotalAmount  []entity.DepositRpTotalAmount
+		withdrawRpTotalAmount []entity.TotalWithdrawRp
+		oldFees               []entity.TotalFee
+		newFees               []entity.TotalFee
+		migrationOldFees      *entity.TotalFee
+		migrationNewFees      *entity.TotalFee
+		counterFees           []entity.CounterFee
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(tu.options.Load().SourceConcurrency)
+	g.Go(func() (err error) {
+		depositRpTotalAmount, err = tu.taxRepository.GetDepositRpTotalAmount(gctx, taxSourceDate.StartDate, taxSourceDate.EndDate)
+		return err
+	})
+	g.Go(func() (err error) {
+		withdrawRpTotalAmount, err = tu.taxRepository.GetTotalWithdrawRp(gctx, taxSourceDate.StartDate, taxSourceDate.EndDate)
+		return err
+	})
+	if taxSourceDate.StartDate < feesMigrationStart { // fees before migration date are only in fees_old
+		g.Go(func() (err error) {
+			oldFees, err = tu.taxRepository.GetOldFees(gctx, taxSourceDate.StartDate, min(taxSourceDate.EndDate, feesMigrationStart))
+			return err
+		})
+	}
+	if taxSourceDate.StartDate < feesMigrationEnd && taxSourceDate.EndDate > feesMigrationStart { // fees on migration date are split between both tables
+		g.Go(func() (err error) {
+			migrationNewFees, err = tu.taxRepository.GetFeesPerDay(gctx, feesMigrationStart, feesMigrationEnd)
+			return err
+		})
+		g.Go(func() (err error) {
+			migrationOldFees, err = tu.taxRepository.GetOldFeesPerDay(gctx, feesMigrationStart, feesMigrationEnd)
+			return err
+		})
+	}
+	if taxSourceDate.EndDate > feesMigrationEnd { // fees calculation with fees after migration date
+		g.Go(func() (err error) {
+			newFees, err = tu.taxRepository.GetFees(gctx, max(taxSourceDate.StartDate, feesMigrationEnd), taxSourceDate.EndDate)
+			return err
+		})
+	}
+	g.Go(func() (err error) {
+		counterFees, err = tu.taxRepository.GetCounterFees(gctx, taxSourceDate.StartDate, taxSourceDate.EndDate)
+		return err
+	})
+	if err := g.Wait(); err != nil {
 		return nil, err
 	}
+
+	for _, depositRp := range depositRpTotalAmount {
+		if index := dayIndex(depositRp.DayOfMonth); index >= 0 {
+			summaries[index].DepositRp = depositRp.TotalAmount.Int64
+		}
+	}
+	for _, withdrawRp := range withdrawRpTotalAmount {
+		if index := dayIndex(withdrawRp.DayOfMonth); index >= 0 {
+			summaries[index].WithdrawRp = withdrawRp.TotalRp.Int64
+		}
+	}
+	for _, fees := range [][]entity.TotalFee{oldFees, newFees} {
+		for _, fee := range fees {
+			if index := dayIndex(fee.DayOfMonth); index >= 0 {
+				aggregateFees[index].TotalFee = fee.TotalFee.Int64
+				aggregateFees[index].TotalUplineBonus = fee.TotalUplineBonus.Int64
+				aggregateFees[index].TotalRemain = fee.TotalRemain.Int64
+			}
+		}
+	}
+	if migrationNewFees != nil && migrationOldFees != nil {
+		dayOfMonth := migrationNewFees.DayOfMonth
+		if !dayOfMonth.Valid {
+			dayOfMonth = migrationOldFees.DayOfMonth
+		}
+		if index := dayIndex(dayOfMonth); index >= 0 {
+			aggregateFees[index].TotalFee = migrationNewFees.TotalFee.Int64 + migrationOldFees.TotalFee.Int64
+			aggregateFees[index].TotalUplineBonus = migrationNewFees.TotalUplineBonus.Int64 + migrationOldFees.TotalUplineBonus.Int64
+			aggregateFees[index].TotalRemain = migrationNewFees.TotalRemain.Int64 + migrationOldFees.TotalRemain.Int64
+		}
+	}
 	for _, counterFee := range counterFees {
-		if !counterFee.DayOfMonth.Valid {
-			continue
+		if index := dayIndex(counterFee.DayOfMonth); index >= 0 {
+			aggregateFees[index].TotalFee += counterFee.TotalFee.Int64
+			aggregateFees[index].TotalRemain += counterFee.TotalFee.Int64 - int64(bankFee)
 		}
-		aggregateFees[int(counterFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalFee += counterFee.TotalFee.Int64
-		aggregateFees[int(counterFee.DayOfMonth.Int64)-taxSourceDate.StartDay].TotalRemain += counterFee.TotalFee.Int64 - int64(bankFee)
 	}
 	var ppn int64
+	taxConfig := tu.taxConfig.Load()
 	for _, aggregateFee := range aggregateFees {
-		if taxSourceDate.StartDate+(int64(aggregateFee.DayOfMonth-taxSourceDate.StartDay)*86400) >= tu.taxConfig.TimeStartPpn {
-			if (taxSourceDate.StartDate + (int64(aggregateFee.DayOfMonth-int(taxSourceDate.StartDay)) * 86400)) < tu.taxConfig.TimeStartPpnNew {
-				ppn = int64(math.Ceil(float64(aggregateFee.TotalFee*tu.taxConfig.TarifPpn) / float64(100+tu.taxConfig.TarifPpn)))
+		if taxSourceDate.StartDate+(int64(aggregateFee.DayOfMonth-taxSourceDate.StartDay)*86400) >= taxConfig.TimeStartPpn {
+			if (taxSourceDate.StartDate + (int64(aggregateFee.DayOfMonth-int(taxSourceDate.StartDay)) * 86400)) < taxConfig.TimeStartPpnNew {
+				ppn = int64(math.Ceil(float64(aggregateFee.TotalFee*taxConfig.TarifPpn) / float64(100+taxConfig.TarifPpn)))
 			} else {
-				ppn = int64(math.Ceil(float64(aggregateFee.TotalFee*tu.taxConfig.TarifPpnNew) / float64(100+tu.taxConfig.TarifPpnNew)))
+				ppn = int64(math.Ceil(float64(aggregateFee.TotalFee*taxConfig.TarifPpnNew) / float64(100+taxConfig.TarifPpnNew)))
 			}
 		}
 		aggregateFee.TotalFee -= ppn
@@ -286,3 +531,69 @@ func (tu *taxUsecase) FetchSourceTax(taxSourceDate *domain.TaxSourceDate) (*doma
 	taxResponse.Summary = summaries
 	return taxResponse, nil
 }
+
+// AggregateDay compute a single closed business day from source database and persist it into service database,
+// the day is skipped when it's already present. a request writing the same day at the same time is upserted into the same row.
+func (tu *taxUsecase) AggregateDay(ctx context.Context, transactionDate int64) error {
+	transactionDate = tax.RoundDay(transactionDate)
+	taxTransactionSummaries, err := tu.taxRepository.GetTaxTransactions(ctx, transactionDate, transactionDate+86400)
+	if err != nil {
+		return err
+	}
+	if len(taxTransactionSummaries) > 0 {
+		return nil
+	}
+	sourceDayStart := tax.SourceDayStart(transactionDate)
+	taxResponse, err := tu.FetchSourceTax(ctx, &domain.TaxSourceDate{
+		StartDate:    sourceDayStart,
+		EndDate:      sourceDayStart + 86400,
+		StartDay:     time.Unix(transactionDate, 0).UTC().Day(),
+		AmountOfDays: 1,
+	})
+	if err != nil {
+		return err
+	}
+	if len(taxResponse.Summary) == 0 {
+		return nil
+	}
+	summary := taxResponse.Summary[0]
+	_, err = tu.taxRepository.InsertTaxTransactions(ctx, transactionDate, []entity.TaxTransaction{{
+		TransactionDate: transactionDate,
+		DepositRp:       summary.DepositRp,
+		WithdrawRp:      summary.WithdrawRp,
+		Fee:             summary.Fee,
+		UplineBonus:     summary.UplineBonus,
+		Remain:          summary.Remain,
+		Ppn:             summary.Ppn,
+	}})
+	if err != nil {
+		return err
+	}
+	tu.InvalidateTax(ctx, transactionDate, transactionDate+86400)
+	return nil
+}
+
+// InvalidateTax remove cached GetTax responses overlapping [startDate, endDate), it must be called whenever tax_transaction
+// is written or adjusted, responses being computed at the same time are not cached. return the number of removed responses.
+func (tu *taxUsecase) InvalidateTax(ctx context.Context, startDate, endDate int64) int {
+	if tu.resultCache == nil {
+		return 0
+	}
+	return tu.resultCache.RemoveFunc(func(key taxCacheKey) bool {
+		return key.StartDate < endDate && key.StartDate+int64(key.AmountOfDays*86400) > startDate
+	})
+}
+
+// Reload swap the tax config and source concurrency of taxSettings and reload the repository,
+// requests already running keep the previous settings.
+func (tu *taxUsecase) Reload(taxSettings *domain.TaxSettings) {
+	taxConfig := taxSettings.TaxConfig
+	tu.taxConfig.Store(&taxConfig)
+	options := *tu.options.Load()
+	options.SourceConcurrency = defaultSourceConcurrency
+	if taxSettings.SourceConcurrency > 0 {
+		options.SourceConcurrency = taxSettings.SourceConcurrency
+	}
+	tu.options.Store(&options)
+	tu.taxRepository.Reload(taxSettings)
+}
@@ -0,0 +1,459 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"tax-aggregator-service-demo/pkg/metrics"
+	"tax-aggregator-service-demo/pkg/ratelimit"
+	"tax-aggregator-service-demo/tax"
+	"tax-aggregator-service-demo/tax/domain"
+	"tax-aggregator-service-demo/tax/entity"
+	"testing"
+	"time"
+
+	mocks "tax-aggregator-service-demo/mocks/tax/domain"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaxUsecase_GetTax(t *testing.T) {
+	type args struct {
+		taxDate *domain.TaxDate
+	}
+	tests := []struct {
+		name         string
+		args         args
+		testFunction func(t *testing.T, tt args)
+	}{
+		{
+			name: "test get tax served from service database without source fallback",
+			args: args{
+				taxDate: &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 3}, // 2023-05-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100, Fee: 11, UplineBonus: 2, Remain: 9, Ppn: 1},
+				}, nil)
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{}, WithSourceFallback(false))
+				taxResponse, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Len(t, taxResponse.Summary, 3)
+				assert.Equal(t, int64(100), taxResponse.Summary[0].DepositRp)
+				assert.Equal(t, int64(11), taxResponse.TotalRevenue)
+				assert.Equal(t, int64(1), taxResponse.TotalPpn)
+				assert.Equal(t, domain.TaxSourceCache, taxResponse.Source)
+			},
+		},
+		{
+			name: "test get tax persisted only doesn't fetch missing days from source database",
+			args: args{
+				taxDate: &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 3, PersistedOnly: true}, // 2023-05-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100, Fee: 11},
+				}, nil)
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{}, WithSourceFallback(true))
+				taxResponse, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Len(t, taxResponse.Summary, 3)
+				assert.Equal(t, int64(11), taxResponse.TotalRevenue)
+				assert.Equal(t, domain.TaxSourceCache, taxResponse.Source)
+			},
+		},
+		{
+			name: "test get tax served from result cache for fully persisted range",
+			args: args{
+				taxDate: &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2}, // 2023-05-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100, Fee: 11},
+					{DayOfMonth: 2, DepositRp: 200, Fee: 22},
+				}, nil).Once()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{}, WithResultCache(10, time.Hour))
+				taxResponse, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.TaxCacheMiss, taxResponse.Cache)
+				taxResponse.Summary[0].DepositRp = 0 // callers can't modify the cached response
+
+				taxResponse, err = taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.TaxCacheHit, taxResponse.Cache)
+				assert.Equal(t, int64(100), taxResponse.Summary[0].DepositRp)
+				assert.Equal(t, int64(33), taxResponse.TotalRevenue)
+			},
+		},
+		{
+			name: "test get tax not cached when range is missing days and invalidated by written days",
+			args: args{
+				taxDate: &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2}, // 2023-05-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100},
+				}, nil).Twice()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{}, WithSourceFallback(false), WithResultCache(10, time.Hour))
+				for i := 0; i < 2; i++ {
+					taxResponse, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+					assert.NoError(t, err)
+					assert.Equal(t, domain.TaxCacheMiss, taxResponse.Cache)
+				}
+
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100},
+					{DayOfMonth: 2, DepositRp: 200},
+				}, nil).Once()
+				_, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, taxUsecase.InvalidateTax(context.Background(), 1682985600, 1683072000))
+				assert.Equal(t, 0, taxUsecase.InvalidateTax(context.Background(), 1683072000, 1683158400))
+			},
+		},
+		{
+			name: "test get tax fetch only the days missing before and between persisted days",
+			args: args{
+				taxDate: &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 4}, // 2023-05-01
+			},
+			testFunction: func(t *testing.T, tt args) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683244800)).Return([]entity.TaxTransactionSummary{
+					{DayOfMonth: 1, DepositRp: 100},
+					{DayOfMonth: 3, DepositRp: 300},
+				}, nil)
+				for _, dateRange := range [][2]int64{{1682985600, 1683072000}, {1683158400, 1683244800}} { // 2023-05-02 and 2023-05-04
+					sourceStart, sourceEnd := dateRange[0]-7*3600, dateRange[1]-7*3600 // read by Asia/Jakarta business day
+					taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, sourceStart, sourceEnd).Return([]entity.DepositRpTotalAmount{
+						{DayOfMonth: sql.NullInt64{Int64: (dateRange[0]-1682899200)/86400 + 1, Valid: true}, TotalAmount: sql.NullInt64{Int64: 1000, Valid: true}},
+					}, nil).Once()
+					taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, sourceStart, sourceEnd).Return(nil, nil).Once()
+					taxRepository.EXPECT().GetFees(mock.Anything, sourceStart, sourceEnd).Return(nil, nil).Once()
+					taxRepository.EXPECT().GetCounterFees(mock.Anything, sourceStart, sourceEnd).Return(nil, nil).Once()
+					taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, dateRange[0], mock.Anything).Return(1, nil).Once()
+				}
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+				taxResponse, err := taxUsecase.GetTax(context.Background(), tt.taxDate)
+				assert.NoError(t, err)
+				assert.Equal(t, []int64{100, 1000, 300, 1000}, []int64{
+					taxResponse.Summary[0].DepositRp, taxResponse.Summary[1].DepositRp, taxResponse.Summary[2].DepositRp, taxResponse.Summary[3].DepositRp,
+				})
+				assert.Equal(t, int64(2), taxResponse.DaysWritten)
+				assert.Equal(t, domain.TaxSourceSource, taxResponse.Source)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t, tt.args)
+		})
+	}
+}
+
+func TestTaxUsecase_GetTax_Coalesce(t *testing.T) {
+	day := func(dayOfMonth int64) sql.NullInt64 { return sql.NullInt64{Int64: dayOfMonth, Valid: true} }
+	amount := func(amount int64) sql.NullInt64 { return sql.NullInt64{Int64: amount, Valid: true} }
+	// expectSource mock the source queries of the business days [startDate, endDate) once, deposits are blocked until unblock is closed
+	expectSource := func(taxRepository *mocks.TaxRepository, startDate, endDate int64, deposits []entity.DepositRpTotalAmount, unblock chan struct{}) {
+		startDate, endDate = tax.SourceDayStart(startDate), tax.SourceDayStart(endDate)
+		taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, startDate, endDate).RunAndReturn(func(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+			<-unblock
+			return deposits, nil
+		}).Once()
+		taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, startDate, endDate).Return(nil, nil).Once()
+		taxRepository.EXPECT().GetFees(mock.Anything, startDate, endDate).Return(nil, nil).Once()
+		taxRepository.EXPECT().GetCounterFees(mock.Anything, startDate, endDate).Return(nil, nil).Once()
+	}
+	getTax := func(taxUsecase domain.TaxUsecase, taxDate *domain.TaxDate) <-chan *domain.TaxResponse {
+		responses := make(chan *domain.TaxResponse, 1)
+		go func() {
+			taxResponse, err := taxUsecase.GetTax(context.Background(), taxDate)
+			assert.NoError(t, err)
+			responses <- taxResponse
+		}()
+		return responses
+	}
+	flights := func(usecase domain.TaxUsecase) int {
+		sourceFlights := usecase.(*taxUsecase).sourceFlights
+		sourceFlights.mu.Lock()
+		defer sourceFlights.mu.Unlock()
+		return len(sourceFlights.flights)
+	}
+	waitCoalesced := func(before float64) {
+		assert.Eventually(t, func() bool { return testutil.ToFloat64(metrics.SourceFetchesCoalesced) > before }, time.Second, time.Millisecond)
+	}
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test identical concurrent requests fetch and insert once",
+			testFunction: func(t *testing.T) {
+				unblock := make(chan struct{})
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return(nil, nil).Twice()
+				expectSource(taxRepository, 1682899200, 1683072000, []entity.DepositRpTotalAmount{{DayOfMonth: day(1), TotalAmount: amount(1000)}}, unblock)
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1682899200), mock.Anything).Return(2, nil).Once()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+
+				coalesced := testutil.ToFloat64(metrics.SourceFetchesCoalesced)
+				first := getTax(taxUsecase, &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2}) // 2023-05-01
+				assert.Eventually(t, func() bool { return flights(taxUsecase) == 1 }, time.Second, time.Millisecond)
+				second := getTax(taxUsecase, &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2})
+				waitCoalesced(coalesced)
+				close(unblock)
+
+				firstResponse, secondResponse := <-first, <-second
+				assert.Equal(t, firstResponse.Summary, secondResponse.Summary)
+				assert.Equal(t, int64(1000), secondResponse.Summary[0].DepositRp)
+				assert.Equal(t, domain.TaxSourceSource, secondResponse.Source)
+				assert.Equal(t, int64(2), firstResponse.DaysWritten+secondResponse.DaysWritten)
+			},
+		},
+		{
+			name: "test overlapping request only fetch the days not being computed",
+			testFunction: func(t *testing.T) {
+				unblock := make(chan struct{})
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return(nil, nil).Once()
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683244800)).Return(nil, nil).Once()
+				expectSource(taxRepository, 1682899200, 1683072000, []entity.DepositRpTotalAmount{{DayOfMonth: day(1), TotalAmount: amount(1000)}}, unblock)
+				unblocked := make(chan struct{})
+				close(unblocked)
+				expectSource(taxRepository, 1683072000, 1683244800, []entity.DepositRpTotalAmount{{DayOfMonth: day(3), TotalAmount: amount(3000)}}, unblocked)
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1682899200), mock.Anything).Return(2, nil).Once()
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1683072000), mock.Anything).Return(2, nil).Once()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+
+				coalesced := testutil.ToFloat64(metrics.SourceFetchesCoalesced)
+				first := getTax(taxUsecase, &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2}) // 2023-05-01
+				assert.Eventually(t, func() bool { return flights(taxUsecase) == 1 }, time.Second, time.Millisecond)
+				second := getTax(taxUsecase, &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 4})
+				waitCoalesced(coalesced)
+				close(unblock)
+
+				firstResponse, secondResponse := <-first, <-second
+				assert.Len(t, firstResponse.Summary, 2)
+				assert.Equal(t, []int64{1000, 0, 3000, 0}, []int64{
+					secondResponse.Summary[0].DepositRp, secondResponse.Summary[1].DepositRp, secondResponse.Summary[2].DepositRp, secondResponse.Summary[3].DepositRp,
+				})
+				assert.Equal(t, int64(2), secondResponse.DaysWritten)
+			},
+		},
+		{
+			name: "test waiting request fail instead of hang when the computing request panicked",
+			testFunction: func(t *testing.T) {
+				unblock := make(chan struct{})
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetTaxTransactions(mock.Anything, int64(1682899200), int64(1683072000)).Return(nil, nil).Twice()
+				expectSource(taxRepository, 1682899200, 1683072000, []entity.DepositRpTotalAmount{{DayOfMonth: day(1), TotalAmount: amount(1000)}}, unblock)
+				taxRepository.EXPECT().InsertTaxTransactions(mock.Anything, int64(1682899200), mock.Anything).RunAndReturn(func(ctx context.Context, transactionDate int64, taxTransactions []entity.TaxTransaction) (int64, error) {
+					panic("service database driver bug")
+				}).Once()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+
+				coalesced := testutil.ToFloat64(metrics.SourceFetchesCoalesced)
+				panicked := make(chan struct{})
+				go func() {
+					defer close(panicked)
+					assert.PanicsWithValue(t, "service database driver bug", func() {
+						taxUsecase.GetTax(context.Background(), &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2}) // 2023-05-01
+					})
+				}()
+				assert.Eventually(t, func() bool { return flights(taxUsecase) == 1 }, time.Second, time.Millisecond)
+				errs := make(chan error, 1)
+				go func() {
+					_, err := taxUsecase.GetTax(context.Background(), &domain.TaxDate{StartDate: 1682899200, AmountOfDays: 2})
+					errs <- err
+				}()
+				waitCoalesced(coalesced)
+				close(unblock)
+
+				<-panicked
+				select {
+				case err := <-errs:
+					assert.ErrorContains(t, err, "panicked")
+				case <-time.After(time.Second):
+					t.Fatal("waiting request hang on the panicked flight")
+				}
+				assert.Zero(t, flights(taxUsecase))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.testFunction)
+	}
+}
+
+func TestTaxUsecase_FetchSourceTax(t *testing.T) {
+	day := func(dayOfMonth int64) sql.NullInt64 { return sql.NullInt64{Int64: dayOfMonth, Valid: true} }
+	amount := func(amount int64) sql.NullInt64 { return sql.NullInt64{Int64: amount, Valid: true} }
+	tests := []struct {
+		name         string
+		testFunction func(t *testing.T)
+	}{
+		{
+			name: "test fetch source tax merge parallel queries after fees migration",
+			testFunction: func(t *testing.T) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.DepositRpTotalAmount{
+					{DayOfMonth: day(1), TotalAmount: amount(1000)},
+					{DayOfMonth: day(3), TotalAmount: amount(3000)},
+				}, nil)
+				taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.TotalWithdrawRp{
+					{DayOfMonth: day(2), TotalRp: amount(500)},
+				}, nil)
+				taxRepository.EXPECT().GetFees(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.TotalFee{
+					{DayOfMonth: day(1), TotalFee: amount(111), TotalUplineBonus: amount(10), TotalRemain: amount(101)},
+					{DayOfMonth: day(2), TotalFee: amount(222), TotalUplineBonus: amount(20), TotalRemain: amount(202)},
+				}, nil)
+				taxRepository.EXPECT().GetCounterFees(mock.Anything, int64(1682899200), int64(1683158400)).Return([]entity.CounterFee{
+					{DayOfMonth: day(1), TotalFee: amount(111)},
+				}, nil)
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{
+					TimeStartPpn:    1478624400,
+					TimeStartPpnNew: 1648746000,
+					TarifPpn:        10,
+					TarifPpnNew:     11,
+				}, WithSourceConcurrency(2))
+				taxResponse, err := taxUsecase.FetchSourceTax(context.Background(), &domain.TaxSourceDate{
+					StartDate:    1682899200, // 2023-05-01
+					EndDate:      1683158400,
+					StartDay:     1,
+					AmountOfDays: 3,
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, []domain.TaxSummary{
+					{DayOfMonth: 1, DepositRp: 1000, Fee: 200, UplineBonus: 10, Remain: 190, Ppn: 22},
+					{DayOfMonth: 2, WithdrawRp: 500, Fee: 200, UplineBonus: 20, Remain: 180, Ppn: 22},
+					{DayOfMonth: 3, DepositRp: 3000},
+				}, taxResponse.Summary)
+				assert.Equal(t, int64(400), taxResponse.TotalRevenue)
+				assert.Equal(t, int64(30), taxResponse.TotalUplineBonus)
+				assert.Equal(t, int64(370), taxResponse.TotalRemain)
+				assert.Equal(t, int64(44), taxResponse.TotalPpn)
+			},
+		},
+		{
+			name: "test fetch source tax split fees around migration date",
+			testFunction: func(t *testing.T) {
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, int64(1662681600), int64(1662940800)).Return([]entity.DepositRpTotalAmount{}, nil)
+				taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, int64(1662681600), int64(1662940800)).Return([]entity.TotalWithdrawRp{}, nil)
+				taxRepository.EXPECT().GetOldFees(mock.Anything, int64(1662681600), int64(1662742800)).Return([]entity.TotalFee{
+					{DayOfMonth: day(9), TotalFee: amount(110)},
+				}, nil)
+				taxRepository.EXPECT().GetFeesPerDay(mock.Anything, int64(1662742800), int64(1662829200)).Return(&entity.TotalFee{
+					DayOfMonth: day(10), TotalFee: amount(50),
+				}, nil)
+				taxRepository.EXPECT().GetOldFeesPerDay(mock.Anything, int64(1662742800), int64(1662829200)).Return(&entity.TotalFee{
+					DayOfMonth: day(10), TotalFee: amount(60),
+				}, nil)
+				taxRepository.EXPECT().GetFees(mock.Anything, int64(1662829200), int64(1662940800)).Return([]entity.TotalFee{
+					{DayOfMonth: day(11), TotalFee: amount(70)},
+				}, nil)
+				taxRepository.EXPECT().GetCounterFees(mock.Anything, int64(1662681600), int64(1662940800)).Return([]entity.CounterFee{}, nil)
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+				taxResponse, err := taxUsecase.FetchSourceTax(context.Background(), &domain.TaxSourceDate{
+					StartDate:    1662681600, // 2022-09-09
+					EndDate:      1662940800,
+					StartDay:     9,
+					AmountOfDays: 3,
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, int64(110), taxResponse.Summary[0].Fee)
+				assert.Equal(t, int64(110), taxResponse.Summary[1].Fee)
+				assert.Equal(t, int64(70), taxResponse.Summary[2].Fee)
+			},
+		},
+		{
+			name: "test fetch source tax cancel running queries on first error",
+			testFunction: func(t *testing.T) {
+				waitCancel := func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("source database down"))
+				taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, startDate, endDate int64) ([]entity.TotalWithdrawRp, error) {
+					return nil, waitCancel(ctx)
+				}).Maybe()
+				taxRepository.EXPECT().GetFees(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, startDate, endDate int64) ([]entity.TotalFee, error) {
+					return nil, waitCancel(ctx)
+				}).Maybe()
+				taxRepository.EXPECT().GetCounterFees(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, startDate, endDate int64) ([]entity.CounterFee, error) {
+					return nil, waitCancel(ctx)
+				}).Maybe()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{})
+				_, err := taxUsecase.FetchSourceTax(context.Background(), &domain.TaxSourceDate{
+					StartDate:    1682899200, // 2023-05-01
+					EndDate:      1683158400,
+					StartDay:     1,
+					AmountOfDays: 3,
+				})
+				assert.EqualError(t, err, "source database down")
+			},
+		},
+		{
+			name: "test fetch source tax fail busy when source fetch limit is full",
+			testFunction: func(t *testing.T) {
+				started := make(chan struct{})
+				unblock := make(chan struct{})
+				taxRepository := mocks.NewTaxRepository(t)
+				taxRepository.EXPECT().GetDepositRpTotalAmount(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, startDate, endDate int64) ([]entity.DepositRpTotalAmount, error) {
+					close(started)
+					<-unblock
+					return nil, nil
+				}).Once()
+				taxRepository.EXPECT().GetTotalWithdrawRp(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+				taxRepository.EXPECT().GetFees(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+				taxRepository.EXPECT().GetCounterFees(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+				taxUsecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{}, WithSourceFetchLimit(1, 0, 2*time.Second))
+				taxSourceDate := func() *domain.TaxSourceDate {
+					return &domain.TaxSourceDate{StartDate: 1682899200, EndDate: 1683158400, StartDay: 1, AmountOfDays: 3}
+				}
+				done := make(chan error)
+				go func() {
+					_, err := taxUsecase.FetchSourceTax(context.Background(), taxSourceDate())
+					done <- err
+				}()
+				<-started
+				_, err := taxUsecase.FetchSourceTax(context.Background(), taxSourceDate())
+				assert.ErrorIs(t, err, domain.ErrSourceBusy)
+				retryAfter, ok := ratelimit.RetryAfter(err)
+				assert.True(t, ok)
+				assert.Equal(t, 2*time.Second, retryAfter)
+				close(unblock)
+				assert.NoError(t, <-done)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFunction(t)
+		})
+	}
+}
+
+func TestTaxUsecase_Reload(t *testing.T) {
+	taxSettings := &domain.TaxSettings{
+		TaxConfig:        domain.TaxConfig{TimeStartPpn: 1478624400, TimeStartPpnNew: 1648746000, TarifPpn: 10, TarifPpnNew: 12},
+		ChunkDays:        map[string]int{"fees": 1},
+		ChunkConcurrency: 2,
+	}
+	taxRepository := mocks.NewTaxRepository(t)
+	taxRepository.EXPECT().Reload(taxSettings).Once()
+	usecase := NewTaxUsecase(taxRepository, &domain.TaxConfig{TarifPpn: 10, TarifPpnNew: 11}, WithSourceConcurrency(8)).(*taxUsecase)
+	usecase.Reload(taxSettings)
+	assert.Equal(t, taxSettings.TaxConfig, *usecase.taxConfig.Load())
+	assert.Equal(t, defaultSourceConcurrency, usecase.options.Load().SourceConcurrency)
+	assert.True(t, usecase.options.Load().SourceFallback)
+}